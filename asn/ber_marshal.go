@@ -297,9 +297,9 @@ func makeField(v reflect.Value, params fieldParameters) (encoder, error) {
 					if err != nil {
 						fmt.Errorf("iterate subtype error")
 					}
-
-					berType.value = structEncoder(s)
 				}
+				// set even when every member is OPTIONAL and absent
+				berType.value = structEncoder(s)
 			}
 		case reflect.Slice:
 			tag.class = ClassUniversal
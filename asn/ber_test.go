@@ -37,6 +37,12 @@ type intSlice struct {
 type sliceInStruct struct {
 	A []int `ber:"tagNum:0,seq"`
 }
+type optionalStruct struct {
+	A *int `ber:"tagNum:0,optional"`
+}
+type choiceSliceInStruct struct {
+	A []choiceTest `ber:"tagNum:0,seq"`
+}
 
 var i int
 
@@ -218,6 +224,18 @@ func TestMarshal(t *testing.T) {
 			"3008" + "800100" + "a203" + "800140",
 			"seq",
 		},
+		{
+			"sliceTest7",
+			choiceSliceInStruct{[]choiceTest{{1, &i, nil, nil, nil, nil}}},
+			"3005" + "a003" + "800100",
+			"seq",
+		},
+		{
+			"optionalTest1",
+			optionalStruct{},
+			"3000",
+			"seq",
+		},
 	}
 
 	for _, tc := range testCases {
@@ -413,6 +431,18 @@ func TestUnmarshal(t *testing.T) {
 			"3008" + "800100" + "a203" + "800140",
 			"seq",
 		},
+		{
+			"sliceTest7",
+			&choiceSliceInStruct{[]choiceTest{{1, &i, nil, nil, nil, nil}}},
+			"3005" + "a003" + "800100",
+			"seq",
+		},
+		{
+			"optionalTest1",
+			&optionalStruct{},
+			"3000",
+			"seq",
+		},
 	}
 
 	for _, tc := range testCases {
@@ -298,8 +298,11 @@ func ParseField(v reflect.Value, bytes []byte, params fieldParameters) error {
 
 		sliceLen := len(valArray)
 		newSlice := reflect.MakeSlice(sliceType, sliceLen, sliceLen)
+		// the tag of the SEQUENCE OF does not apply to its components
+		tempParams := params
+		tempParams.tagNumber = nil
 		for i := 0; i < sliceLen; i++ {
-			err := ParseField(newSlice.Index(i), valArray[i], params)
+			err := ParseField(newSlice.Index(i), valArray[i], tempParams)
 			if err != nil {
 				return err
 			}
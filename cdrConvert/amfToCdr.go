@@ -0,0 +1,418 @@
+package cdrConvert
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/free5gc/CDRUtil/asn"
+	"github.com/free5gc/CDRUtil/cdrType"
+	"github.com/free5gc/openapi/models"
+)
+
+var registrationMessageTypeToCdr = map[models.RegistrationMessageType]asn.Enumerated{
+	"INITIAL":        cdrType.RegistrationMessageTypePresentInitial,
+	"MOBILITY":       cdrType.RegistrationMessageTypePresentMobility,
+	"PERIODIC":       cdrType.RegistrationMessageTypePresentPeriodic,
+	"EMERGENCY":      cdrType.RegistrationMessageTypePresentEmergency,
+	"DEREGISTRATION": cdrType.RegistrationMessageTypePresentDeregistration,
+}
+
+var micoModeIndicationToCdr = map[models.MicoModeIndication]asn.Enumerated{
+	"MICO_MODE":    cdrType.MICOModeIndicationPresentMICOMode,
+	"NO_MICO_MODE": cdrType.MICOModeIndicationPresentNoMICOMode,
+}
+
+var smsIndicationToCdr = map[models.SmsIndication]asn.Enumerated{
+	"SMS_SUPPORTED":     cdrType.SmsIndicationPresentSMSSupported,
+	"SMS_NOT_SUPPORTED": cdrType.SmsIndicationPresentSMSNotSupported,
+}
+
+var roamerInOutToCdr = map[models.RoamerInOut]asn.Enumerated{
+	"IN_BOUND":  cdrType.RoamerInOutPresentRoamerInBound,
+	"OUT_BOUND": cdrType.RoamerInOutPresentRoamerOutBound,
+}
+
+var restrictionTypeToCdr = map[models.RestrictionType]asn.Enumerated{
+	"ALLOWED_AREAS":     cdrType.RestrictionTypePresentAllowedAreas,
+	"NOT_ALLOWED_AREAS": cdrType.RestrictionTypePresentNotAllowedAreas,
+}
+
+var coreNetworkTypeToCdr = map[models.CoreNetworkType]asn.Enumerated{
+	"5GC": cdrType.CoreNetworkTypePresentFiveGC,
+	"EPC": cdrType.CoreNetworkTypePresentEPC,
+}
+
+// The members common to the AMF charging information
+type amfUserInformation struct {
+	userIdentifier          *cdrType.InvolvedParty
+	userEquipmentInfo       *cdrType.SubscriberEquipmentNumber
+	supiUnauthenticatedFlag *asn.NULL
+	userRoamerInOut         *cdrType.RoamerInOut
+	userLocationInformation *cdrType.UserLocationInformation
+	ueTimeZone              *cdrType.MSTimeZone
+	ratType                 *cdrType.RATType
+	userLocationASN1        *cdrType.UserLocationInformationStructured
+}
+
+func amfUserInformationToCdr(userInformation *models.UserInformation, userLocation *models.UserLocation,
+	ueTimeZone string, ratType models.RatType) (amfUserInformation, error) {
+	var info amfUserInformation
+
+	if userInformation != nil {
+		if userInformation.ServedGPSI != "" {
+			userIdentifier, err := GpsiToCdr(userInformation.ServedGPSI)
+			if err != nil {
+				return info, err
+			}
+			info.userIdentifier = &userIdentifier
+		}
+		if userInformation.ServedPEI != "" {
+			equipment, err := cdrType.NewSubscriberEquipmentNumberFromPei(userInformation.ServedPEI)
+			if err != nil {
+				return info, err
+			}
+			info.userEquipmentInfo = &equipment
+		}
+		if userInformation.UnauthenticatedFlag {
+			flag := asn.NULL(true)
+			info.supiUnauthenticatedFlag = &flag
+		}
+		if userInformation.RoamerInOut != "" {
+			value, ok := roamerInOutToCdr[userInformation.RoamerInOut]
+			if !ok {
+				return info, fmt.Errorf("unsupported roamerInOut %q", userInformation.RoamerInOut)
+			}
+			info.userRoamerInOut = &cdrType.RoamerInOut{Value: value}
+		}
+	}
+	if userLocation != nil {
+		uli, structured, err := UserLocationToCdr(*userLocation)
+		if err != nil {
+			return info, err
+		}
+		info.userLocationInformation = uli
+		info.userLocationASN1 = &structured
+	}
+	if ueTimeZone != "" {
+		timeZone, err := UeTimeZoneToCdr(ueTimeZone)
+		if err != nil {
+			return info, err
+		}
+		info.ueTimeZone = &timeZone
+	}
+	if ratType != "" {
+		rat, err := RatTypeToCdr(ratType)
+		if err != nil {
+			return info, err
+		}
+		info.ratType = &rat
+	}
+	return info, nil
+}
+
+func AreaToCdr(area models.Area) (cdrType.Area, error) {
+	var cdrArea cdrType.Area
+
+	for _, tac := range area.Tacs {
+		value, err := hex.DecodeString(tac)
+		if err != nil || (len(value) != 2 && len(value) != 3) {
+			return cdrArea, fmt.Errorf("invalid tac %q", tac)
+		}
+		cdrArea.Tacs = append(cdrArea.Tacs, cdrType.TAC{Value: value})
+	}
+	if area.AreaCode != "" {
+		areaCode := asn.OctetString(area.AreaCode)
+		cdrArea.AreaCode = &areaCode
+	}
+	return cdrArea, nil
+}
+
+func AreaListToCdr(areaList []models.Area) ([]cdrType.Area, error) {
+	cdrAreaList := make([]cdrType.Area, 0, len(areaList))
+	for _, area := range areaList {
+		cdrArea, err := AreaToCdr(area)
+		if err != nil {
+			return nil, err
+		}
+		cdrAreaList = append(cdrAreaList, cdrArea)
+	}
+	return cdrAreaList, nil
+}
+
+func ServiceAreaRestrictionToCdr(restriction models.ServiceAreaRestriction) (cdrType.ServiceAreaRestriction, error) {
+	var cdrRestriction cdrType.ServiceAreaRestriction
+
+	if restriction.RestrictionType != "" {
+		value, ok := restrictionTypeToCdr[restriction.RestrictionType]
+		if !ok {
+			return cdrRestriction, fmt.Errorf("unsupported restrictionType %q", restriction.RestrictionType)
+		}
+		cdrRestriction.RestrictionType = &cdrType.RestrictionType{Value: value}
+	}
+	if len(restriction.Areas) > 0 {
+		areas, err := AreaListToCdr(restriction.Areas)
+		if err != nil {
+			return cdrRestriction, err
+		}
+		cdrRestriction.Areas = areas
+	}
+	if restriction.MaxNumOfTAs != 0 {
+		maxNumOfTAs := int64(restriction.MaxNumOfTAs)
+		cdrRestriction.MaxNumOfTAs = &maxNumOfTAs
+	}
+	if restriction.MaxNumOfTAsForNotAllowedAreas != 0 {
+		maxNumOfTAs := int64(restriction.MaxNumOfTAsForNotAllowedAreas)
+		cdrRestriction.MaxNumOfTAsForNotAllowedAreas = &maxNumOfTAs
+	}
+	return cdrRestriction, nil
+}
+
+// serviceAreaRestrictionListToCdr converts the service area restriction of
+// the AMF, a single one in TS 32.291 the models carry in a list.
+func serviceAreaRestrictionListToCdr(restrictions []models.ServiceAreaRestriction) (
+	cdrType.ServiceAreaRestriction, error) {
+	if len(restrictions) != 1 {
+		return cdrType.ServiceAreaRestriction{}, fmt.Errorf("%d serviceAreaRestriction, expected one", len(restrictions))
+	}
+	return ServiceAreaRestrictionToCdr(restrictions[0])
+}
+
+func PsCellInformationToCdr(psCellInformation models.PsCellInformation) (cdrType.PSCellInformation, error) {
+	var cdrPsCellInformation cdrType.PSCellInformation
+
+	if psCellInformation.Nrcgi != nil {
+		ncgi, err := NcgiToCdr(*psCellInformation.Nrcgi)
+		if err != nil {
+			return cdrPsCellInformation, err
+		}
+		cdrPsCellInformation.NRcgi = &ncgi
+	}
+	if psCellInformation.Ecgi != nil {
+		ecgi, err := EcgiToCdr(*psCellInformation.Ecgi)
+		if err != nil {
+			return cdrPsCellInformation, err
+		}
+		cdrPsCellInformation.Ecgi = &ecgi
+	}
+	return cdrPsCellInformation, nil
+}
+
+func RegistrationChargingInformationToCdr(registration models.RegistrationChargingInformation) (
+	cdrType.RegistrationChargingInformation, error) {
+	var cdrRegistration cdrType.RegistrationChargingInformation
+
+	messageType, ok := registrationMessageTypeToCdr[registration.RegistrationMessagetype]
+	if !ok {
+		return cdrRegistration, fmt.Errorf("unsupported registrationMessagetype %q",
+			registration.RegistrationMessagetype)
+	}
+	cdrRegistration.RegistrationMessagetype = cdrType.RegistrationMessageType{Value: messageType}
+
+	info, err := amfUserInformationToCdr(registration.UserInformation, registration.UserLocationinfo,
+		registration.UetimeZone, registration.RATType)
+	if err != nil {
+		return cdrRegistration, err
+	}
+	cdrRegistration.UserIdentifier = info.userIdentifier
+	cdrRegistration.UserEquipmentInfo = info.userEquipmentInfo
+	cdrRegistration.SUPIunauthenticatedFlag = info.supiUnauthenticatedFlag
+	cdrRegistration.UserRoamerInOut = info.userRoamerInOut
+	cdrRegistration.UserLocationInformation = info.userLocationInformation
+	cdrRegistration.UETimeZone = info.ueTimeZone
+	cdrRegistration.RATType = info.ratType
+	cdrRegistration.UserLocationInformationASN1 = info.userLocationASN1
+
+	if registration.MICOModeIndication != "" {
+		value, ok := micoModeIndicationToCdr[registration.MICOModeIndication]
+		if !ok {
+			return cdrRegistration, fmt.Errorf("unsupported mICOModeIndication %q", registration.MICOModeIndication)
+		}
+		cdrRegistration.MICOModeIndication = &cdrType.MICOModeIndication{Value: value}
+	}
+	if registration.SmsIndication != "" {
+		value, ok := smsIndicationToCdr[registration.SmsIndication]
+		if !ok {
+			return cdrRegistration, fmt.Errorf("unsupported smsIndication %q", registration.SmsIndication)
+		}
+		cdrRegistration.SmsIndication = &cdrType.SmsIndication{Value: value}
+	}
+	if len(registration.TaiList) > 0 {
+		if cdrRegistration.TaiList, err = TaiListToCdr(registration.TaiList); err != nil {
+			return cdrRegistration, err
+		}
+	}
+	if len(registration.ServiceAreaRestriction) > 0 {
+		restriction, err := serviceAreaRestrictionListToCdr(registration.ServiceAreaRestriction)
+		if err != nil {
+			return cdrRegistration, err
+		}
+		cdrRegistration.ServiceAreaRestriction = &restriction
+	}
+	if len(registration.RequestedNSSAI) > 0 {
+		if cdrRegistration.RequestedNSSAI, err = SnssaiListToCdr(registration.RequestedNSSAI); err != nil {
+			return cdrRegistration, err
+		}
+	}
+	if len(registration.AllowedNSSAI) > 0 {
+		if cdrRegistration.AllowedNSSAI, err = SnssaiListToCdr(registration.AllowedNSSAI); err != nil {
+			return cdrRegistration, err
+		}
+	}
+	if len(registration.RejectedNSSAI) > 0 {
+		if cdrRegistration.RejectedNSSAI, err = SnssaiListToCdr(registration.RejectedNSSAI); err != nil {
+			return cdrRegistration, err
+		}
+	}
+	for _, nssaiMap := range registration.NSSAIMapList {
+		if nssaiMap.ServingSnssai == nil || nssaiMap.HomeSnssai == nil {
+			return cdrRegistration, fmt.Errorf("incomplete nSSAIMap")
+		}
+		serving, err := SnssaiToCdr(*nssaiMap.ServingSnssai)
+		if err != nil {
+			return cdrRegistration, err
+		}
+		home, err := SnssaiToCdr(*nssaiMap.HomeSnssai)
+		if err != nil {
+			return cdrRegistration, err
+		}
+		cdrRegistration.NSSAIMapList = append(cdrRegistration.NSSAIMapList, cdrType.NSSAIMap{
+			ServingSnssai: serving,
+			HomeSnssai:    home,
+		})
+	}
+	if registration.PSCellInformation != nil {
+		psCellInformation, err := PsCellInformationToCdr(*registration.PSCellInformation)
+		if err != nil {
+			return cdrRegistration, err
+		}
+		cdrRegistration.PSCellInformation = &psCellInformation
+	}
+	if registration.AmfUeNgapId != 0 {
+		cdrRegistration.AmfUeNgapId = &cdrType.AmfUeNgapId{Value: int64(registration.AmfUeNgapId)}
+	}
+	if registration.RanUeNgapId != 0 {
+		cdrRegistration.RanUeNgapId = &cdrType.RanUeNgapId{Value: int64(registration.RanUeNgapId)}
+	}
+	if registration.RanNodeId != nil {
+		ranNodeId, err := GlobalRanNodeIdToCdr(*registration.RanNodeId)
+		if err != nil {
+			return cdrRegistration, err
+		}
+		cdrRegistration.RanNodeId = &ranNodeId
+	}
+
+	return cdrRegistration, nil
+}
+
+func N2ConnectionChargingInformationToCdr(n2Connection models.N2ConnectionChargingInformation) (
+	cdrType.N2ConnectionChargingInformation, error) {
+	cdrN2Connection := cdrType.N2ConnectionChargingInformation{
+		N2ConnectionMessageType: cdrType.N2ConnectionMessageType{Value: int64(n2Connection.N2ConnectionMessageType)},
+	}
+
+	info, err := amfUserInformationToCdr(n2Connection.UserInformation, n2Connection.UserLocationinfo,
+		n2Connection.UetimeZone, n2Connection.RATType)
+	if err != nil {
+		return cdrN2Connection, err
+	}
+	cdrN2Connection.UserIdentifier = info.userIdentifier
+	cdrN2Connection.UserEquipmentInfo = info.userEquipmentInfo
+	cdrN2Connection.SUPIunauthenticatedFlag = info.supiUnauthenticatedFlag
+	cdrN2Connection.UserRoamerInOut = info.userRoamerInOut
+	cdrN2Connection.UserLocationInformation = info.userLocationInformation
+	cdrN2Connection.UETimeZone = info.ueTimeZone
+	cdrN2Connection.RATType = info.ratType
+	cdrN2Connection.UserLocationInformationASN1 = info.userLocationASN1
+
+	if n2Connection.RanUeNgapId != 0 {
+		cdrN2Connection.RanUeNgapId = &cdrType.RanUeNgapId{Value: int64(n2Connection.RanUeNgapId)}
+	}
+	if n2Connection.RanNodeId != nil {
+		ranNodeId, err := GlobalRanNodeIdToCdr(*n2Connection.RanNodeId)
+		if err != nil {
+			return cdrN2Connection, err
+		}
+		cdrN2Connection.RanNodeId = &ranNodeId
+	}
+	for _, ratType := range n2Connection.RestrictedRatList {
+		rat, err := RatTypeToCdr(ratType)
+		if err != nil {
+			return cdrN2Connection, err
+		}
+		cdrN2Connection.RestrictedRatList = append(cdrN2Connection.RestrictedRatList, rat)
+	}
+	if len(n2Connection.ForbiddenAreaList) > 0 {
+		if cdrN2Connection.ForbiddenAreaList, err = AreaListToCdr(n2Connection.ForbiddenAreaList); err != nil {
+			return cdrN2Connection, err
+		}
+	}
+	if len(n2Connection.ServiceAreaRestriction) > 0 {
+		restriction, err := serviceAreaRestrictionListToCdr(n2Connection.ServiceAreaRestriction)
+		if err != nil {
+			return cdrN2Connection, err
+		}
+		cdrN2Connection.ServiceAreaRestriction = &restriction
+	}
+	for _, coreNetworkType := range n2Connection.RestrictedCnList {
+		value, ok := coreNetworkTypeToCdr[coreNetworkType]
+		if !ok {
+			return cdrN2Connection, fmt.Errorf("unsupported coreNetworkType %q", coreNetworkType)
+		}
+		cdrN2Connection.RestrictedCnList = append(cdrN2Connection.RestrictedCnList,
+			cdrType.CoreNetworkType{Value: value})
+	}
+	if len(n2Connection.AllowedNSSAI) > 0 {
+		if cdrN2Connection.AllowedNSSAI, err = SnssaiListToCdr(n2Connection.AllowedNSSAI); err != nil {
+			return cdrN2Connection, err
+		}
+	}
+	if n2Connection.RrcEstCause != "" {
+		cause, err := hex.DecodeString(n2Connection.RrcEstCause)
+		if err != nil {
+			return cdrN2Connection, fmt.Errorf("invalid rrcEstCause %q", n2Connection.RrcEstCause)
+		}
+		cdrN2Connection.RrcEstablishmentCause = &cdrType.RrcEstablishmentCause{Value: cause}
+	}
+	if n2Connection.PSCellInformation != nil {
+		psCellInformation, err := PsCellInformationToCdr(*n2Connection.PSCellInformation)
+		if err != nil {
+			return cdrN2Connection, err
+		}
+		cdrN2Connection.PSCellInformation = &psCellInformation
+	}
+	if n2Connection.AmfUeNgapId != 0 {
+		cdrN2Connection.AmfUeNgapId = &cdrType.AmfUeNgapId{Value: int64(n2Connection.AmfUeNgapId)}
+	}
+
+	return cdrN2Connection, nil
+}
+
+// AmfChargingRecordToCdr builds the record of an AMF registration or N2
+// connection event reported to the CHF named chfName, see
+// OneTimeEventRecordToCdr.
+func AmfChargingRecordToCdr(chfName string, request models.ChargingDataRequest) (cdrType.ChargingRecord, error) {
+	if request.RegistrationChargingInformation == nil && request.N2ConnectionChargingInformation == nil {
+		return cdrType.ChargingRecord{}, fmt.Errorf("neither registration nor N2 connection charging information")
+	}
+	record, err := OneTimeEventRecordToCdr(chfName, request, "AMF")
+	if err != nil {
+		return record, err
+	}
+
+	if request.RegistrationChargingInformation != nil {
+		registration, err := RegistrationChargingInformationToCdr(*request.RegistrationChargingInformation)
+		if err != nil {
+			return record, err
+		}
+		record.RegistrationChargingInformation = &registration
+	}
+	if request.N2ConnectionChargingInformation != nil {
+		n2Connection, err := N2ConnectionChargingInformationToCdr(*request.N2ConnectionChargingInformation)
+		if err != nil {
+			return record, err
+		}
+		record.N2ConnectionChargingInformation = &n2Connection
+	}
+
+	return record, nil
+}
@@ -0,0 +1,174 @@
+package cdrConvert
+
+import (
+	"testing"
+	"time"
+
+	"github.com/free5gc/CDRUtil/cdrType"
+	"github.com/free5gc/openapi/models"
+	"github.com/stretchr/testify/require"
+)
+
+func amfRequest() models.ChargingDataRequest {
+	invocationTime := time.Date(2022, time.May, 4, 10, 0, 0, 0, time.UTC)
+	plmnId := &models.PlmnId{Mcc: "208", Mnc: "93"}
+	userInformation := &models.UserInformation{
+		ServedGPSI: "msisdn-886912345678",
+		ServedPEI:  "imei-4370816125816151",
+	}
+	userLocation := &models.UserLocation{
+		NrLocation: &models.NrLocation{
+			Tai:  &models.Tai{PlmnId: plmnId, Tac: "000001"},
+			Ncgi: &models.Ncgi{PlmnId: plmnId, NrCellId: "000000010"},
+		},
+	}
+	ranNodeId := &models.GlobalRanNodeId{PlmnId: plmnId, GNbId: &models.GNbId{BitLength: 24, GNBValue: "000102"}}
+	return models.ChargingDataRequest{
+		InvocationTimeStamp:  &invocationTime,
+		SubscriberIdentifier: "imsi-208930000000003",
+		RegistrationChargingInformation: &models.RegistrationChargingInformation{
+			RegistrationMessagetype: "INITIAL",
+			UserInformation:         userInformation,
+			UserLocationinfo:        userLocation,
+			UetimeZone:              "+08:00",
+			RATType:                 "NR",
+			MICOModeIndication:      "NO_MICO_MODE",
+			SmsIndication:           "SMS_SUPPORTED",
+			TaiList:                 []models.Tai{{PlmnId: plmnId, Tac: "000001"}},
+			ServiceAreaRestriction: []models.ServiceAreaRestriction{{
+				RestrictionType: "ALLOWED_AREAS",
+				Areas:           []models.Area{{Tacs: []string{"000001", "000002"}}},
+			}},
+			RequestedNSSAI: []models.Snssai{{Sst: 1, Sd: "010203"}, {Sst: 2}},
+			AllowedNSSAI:   []models.Snssai{{Sst: 1, Sd: "010203"}},
+			RejectedNSSAI:  []models.Snssai{{Sst: 2}},
+			NSSAIMapList: []models.NssaiMap{{
+				ServingSnssai: &models.Snssai{Sst: 1, Sd: "010203"},
+				HomeSnssai:    &models.Snssai{Sst: 1, Sd: "112233"},
+			}},
+			AmfUeNgapId: 1,
+			RanUeNgapId: 2,
+			RanNodeId:   ranNodeId,
+		},
+		N2ConnectionChargingInformation: &models.N2ConnectionChargingInformation{
+			N2ConnectionMessageType: 1,
+			UserInformation:         userInformation,
+			UserLocationinfo:        userLocation,
+			RATType:                 "NR",
+			RanUeNgapId:             2,
+			RanNodeId:               ranNodeId,
+			RestrictedRatList:       []models.RatType{"EUTRA"},
+			ForbiddenAreaList:       []models.Area{{Tacs: []string{"0003"}}},
+			RestrictedCnList:        []models.CoreNetworkType{"EPC"},
+			RrcEstCause:             "03",
+		},
+	}
+}
+
+func TestAmfChargingRecordToCdr(t *testing.T) {
+	t.Parallel()
+
+	record, err := AmfChargingRecordToCdr("chf", amfRequest())
+	require.NoError(t, err)
+	require.Equal(t, "chf", string(record.RecordingNetworkFunctionID.Value))
+	require.Equal(t, cdrType.NetworkFunctionalityPresentAMF, record.NFunctionConsumerInformation.NetworkFunctionality.Value)
+	require.Equal(t, cdrType.CauseForRecClosingPresentNormalRelease, record.CauseForRecClosing.Value)
+
+	registration := record.RegistrationChargingInformation
+	require.Equal(t, cdrType.RegistrationMessageTypePresentInitial, registration.RegistrationMessagetype.Value)
+	require.NotNil(t, registration.UserIdentifier)
+	require.NotNil(t, registration.UserEquipmentInfo)
+	require.NotNil(t, registration.UserLocationInformation)
+	require.NotNil(t, registration.UserLocationInformationASN1)
+	offset, _, err := registration.UETimeZone.Decode()
+	require.NoError(t, err)
+	require.Equal(t, 8*3600, offset)
+	require.Equal(t, cdrType.RATTypePresentNR, registration.RATType.Value)
+	require.Equal(t, cdrType.MICOModeIndicationPresentNoMICOMode, registration.MICOModeIndication.Value)
+	require.Equal(t, cdrType.SmsIndicationPresentSMSSupported, registration.SmsIndication.Value)
+	require.Len(t, registration.TaiList, 1)
+	require.Equal(t, cdrType.RestrictionTypePresentAllowedAreas, registration.ServiceAreaRestriction.RestrictionType.Value)
+	require.Len(t, registration.ServiceAreaRestriction.Areas[0].Tacs, 2)
+	require.Len(t, registration.RequestedNSSAI, 2)
+	require.Len(t, registration.AllowedNSSAI, 1)
+	require.Len(t, registration.RejectedNSSAI, 1)
+	require.Len(t, registration.NSSAIMapList, 1)
+	require.Equal(t, int64(1), registration.AmfUeNgapId.Value)
+	require.Equal(t, int64(2), registration.RanUeNgapId.Value)
+	require.Equal(t, int64(24), registration.RanNodeId.GNbId.BitLength)
+
+	n2Connection := record.N2ConnectionChargingInformation
+	require.Equal(t, int64(1), n2Connection.N2ConnectionMessageType.Value)
+	require.Nil(t, n2Connection.UETimeZone)
+	require.Equal(t, []cdrType.RATType{{Value: cdrType.RATTypePresentEUTRAN}}, n2Connection.RestrictedRatList)
+	require.Len(t, n2Connection.ForbiddenAreaList, 1)
+	require.Equal(t, []cdrType.CoreNetworkType{{Value: cdrType.CoreNetworkTypePresentEPC}}, n2Connection.RestrictedCnList)
+	require.Equal(t, []byte{3}, []byte(n2Connection.RrcEstablishmentCause.Value))
+	require.Nil(t, n2Connection.AmfUeNgapId)
+
+	testCases := []struct {
+		name   string
+		modify func(r *models.ChargingDataRequest)
+	}{
+		{"chargingInformation", func(r *models.ChargingDataRequest) {
+			r.RegistrationChargingInformation = nil
+			r.N2ConnectionChargingInformation = nil
+		}},
+		{"nodeFunctionality", func(r *models.ChargingDataRequest) {
+			r.NfConsumerIdentification = &models.NfIdentification{NodeFunctionality: "SMF"}
+		}},
+		{"registrationMessagetype", func(r *models.ChargingDataRequest) {
+			r.RegistrationChargingInformation.RegistrationMessagetype = "RELOCATION"
+		}},
+		{"servedGPSI", func(r *models.ChargingDataRequest) {
+			r.RegistrationChargingInformation.UserInformation = &models.UserInformation{ServedGPSI: "886912345678"}
+		}},
+		{"roamerInOut", func(r *models.ChargingDataRequest) {
+			r.RegistrationChargingInformation.UserInformation = &models.UserInformation{RoamerInOut: "AWAY"}
+		}},
+		{"mICOModeIndication", func(r *models.ChargingDataRequest) {
+			r.RegistrationChargingInformation.MICOModeIndication = "MICO"
+		}},
+		{"smsIndication", func(r *models.ChargingDataRequest) { r.RegistrationChargingInformation.SmsIndication = "SMS" }},
+		{"taiList", func(r *models.ChargingDataRequest) {
+			r.RegistrationChargingInformation.TaiList[0].Tac = "1"
+		}},
+		{"serviceAreaRestriction", func(r *models.ChargingDataRequest) {
+			restrictions := r.RegistrationChargingInformation.ServiceAreaRestriction
+			r.RegistrationChargingInformation.ServiceAreaRestriction = append(restrictions, restrictions[0])
+		}},
+		{"restrictionType", func(r *models.ChargingDataRequest) {
+			r.RegistrationChargingInformation.ServiceAreaRestriction = []models.ServiceAreaRestriction{{
+				RestrictionType: "SOME_AREAS",
+			}}
+		}},
+		{"nSSAIMap", func(r *models.ChargingDataRequest) {
+			r.RegistrationChargingInformation.NSSAIMapList[0].HomeSnssai = nil
+		}},
+		{"ranNodeId", func(r *models.ChargingDataRequest) {
+			r.RegistrationChargingInformation.RanNodeId = &models.GlobalRanNodeId{}
+		}},
+		{"restrictedRatList", func(r *models.ChargingDataRequest) {
+			r.N2ConnectionChargingInformation.RestrictedRatList = []models.RatType{"5G"}
+		}},
+		{"forbiddenAreaList", func(r *models.ChargingDataRequest) {
+			r.N2ConnectionChargingInformation.ForbiddenAreaList = []models.Area{{Tacs: []string{"tac"}}}
+		}},
+		{"restrictedCnList", func(r *models.ChargingDataRequest) {
+			r.N2ConnectionChargingInformation.RestrictedCnList = []models.CoreNetworkType{"4GC"}
+		}},
+		{"rrcEstCause", func(r *models.ChargingDataRequest) { r.N2ConnectionChargingInformation.RrcEstCause = "x" }},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := amfRequest()
+			tc.modify(&r)
+			_, err := AmfChargingRecordToCdr("chf", r)
+			require.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,210 @@
+package cdrConvert
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/free5gc/CDRUtil/asn"
+	"github.com/free5gc/CDRUtil/cdrType"
+	"github.com/free5gc/openapi/models"
+)
+
+// The NGAP procedure code of LOCATION REPORT, TS 38.413
+const ngapLocationReportProcedureCode = 18
+
+var presenceStateToCdr = map[models.PresenceState]asn.Enumerated{
+	"IN_AREA":     cdrType.PresenceReportingAreaStatusPresentInsideArea,
+	"OUT_OF_AREA": cdrType.PresenceReportingAreaStatusPresentOutsideArea,
+	"INACTIVE":    cdrType.PresenceReportingAreaStatusPresentInactive,
+	"UNKNOWN":     cdrType.PresenceReportingAreaStatusPresentUnknown,
+}
+
+// PresenceInfoToCdr converts the status of a presence reporting area. The
+// PRA identifier is encoded in 3 octets as in TS 29.274. The elements of the
+// area are left out, the identifier refers to them.
+func PresenceInfoToCdr(presenceInfo models.PresenceInfo) (cdrType.PresenceReportingAreaInfo, error) {
+	var cdrPresenceInfo cdrType.PresenceReportingAreaInfo
+
+	praId, err := strconv.ParseUint(presenceInfo.PraId, 10, 24)
+	if err != nil {
+		return cdrPresenceInfo, fmt.Errorf("invalid praId %q", presenceInfo.PraId)
+	}
+	cdrPresenceInfo.PresenceReportingAreaIdentifier = asn.OctetString{
+		byte(praId >> 16), byte(praId >> 8), byte(praId),
+	}
+
+	if presenceInfo.PresenceState != "" {
+		value, ok := presenceStateToCdr[presenceInfo.PresenceState]
+		if !ok {
+			return cdrPresenceInfo, fmt.Errorf("unsupported presenceState %q", presenceInfo.PresenceState)
+		}
+		cdrPresenceInfo.PresenceReportingAreaStatus = &cdrType.PresenceReportingAreaStatus{Value: value}
+	}
+	return cdrPresenceInfo, nil
+}
+
+// LocationReportingChargingInformationToCdr converts the location reporting
+// charging information, which can only hold a single presence reporting area.
+func LocationReportingChargingInformationToCdr(locationReporting models.LocationReportingChargingInformation) (
+	cdrType.LocationReportingChargingInformation, error) {
+	cdrLocationReporting := cdrType.LocationReportingChargingInformation{
+		LocationReportingMessagetype: cdrType.LocationReportingMessageType{
+			Value: int64(locationReporting.LocationReportingMessageType),
+		},
+	}
+
+	info, err := amfUserInformationToCdr(locationReporting.UserInformation, locationReporting.UserLocationinfo,
+		locationReporting.UetimeZone, locationReporting.RATType)
+	if err != nil {
+		return cdrLocationReporting, err
+	}
+	cdrLocationReporting.UserIdentifier = info.userIdentifier
+	cdrLocationReporting.UserEquipmentInfo = info.userEquipmentInfo
+	cdrLocationReporting.SUPIunauthenticatedFlag = info.supiUnauthenticatedFlag
+	cdrLocationReporting.UserRoamerInOut = info.userRoamerInOut
+	cdrLocationReporting.UserLocationInformation = info.userLocationInformation
+	cdrLocationReporting.UETimeZone = info.ueTimeZone
+	cdrLocationReporting.RATType = info.ratType
+	cdrLocationReporting.UserLocationInformationASN1 = info.userLocationASN1
+
+	switch len(locationReporting.PresenceReportingAreaInformation) {
+	case 0:
+	case 1:
+		for _, presenceInfo := range locationReporting.PresenceReportingAreaInformation {
+			cdrPresenceInfo, err := PresenceInfoToCdr(presenceInfo)
+			if err != nil {
+				return cdrLocationReporting, err
+			}
+			cdrLocationReporting.PresenceReportingAreaInfo = &cdrPresenceInfo
+		}
+	default:
+		return cdrLocationReporting, fmt.Errorf("%d presenceReportingAreaInfo for a single record",
+			len(locationReporting.PresenceReportingAreaInformation))
+	}
+	if locationReporting.PSCellInformation != nil {
+		psCellInformation, err := PsCellInformationToCdr(*locationReporting.PSCellInformation)
+		if err != nil {
+			return cdrLocationReporting, err
+		}
+		cdrLocationReporting.PSCellInformation = &psCellInformation
+	}
+
+	return cdrLocationReporting, nil
+}
+
+// LocationReportingChargingRecordToCdr builds the record of an AMF location
+// report to the CHF named chfName, see OneTimeEventRecordToCdr.
+func LocationReportingChargingRecordToCdr(chfName string, request models.ChargingDataRequest) (
+	cdrType.ChargingRecord, error) {
+	if request.LocationReportingChargingInformation == nil {
+		return cdrType.ChargingRecord{}, fmt.Errorf("missing locationReportingChargingInformation")
+	}
+	record, err := OneTimeEventRecordToCdr(chfName, request, "AMF")
+	if err != nil {
+		return record, err
+	}
+
+	locationReporting, err := LocationReportingChargingInformationToCdr(*request.LocationReportingChargingInformation)
+	if err != nil {
+		return record, err
+	}
+	record.LocationReportingChargingInformation = &locationReporting
+
+	return record, nil
+}
+
+// AmfLocationReportToCdr builds the records of a LOCATION_REPORT or
+// PRESENCE_IN_AOI_REPORT event notified by the AMF identified by amf, one
+// per presence reporting area of the report or a single one without area.
+// The location is timed by its UE location time stamp or, failing that, by
+// its age before the report.
+func AmfLocationReportToCdr(chfName string, amf models.NfIdentification, report models.AmfEventReport) (
+	[]cdrType.ChargingRecord, error) {
+	if report.Type != "LOCATION_REPORT" && report.Type != "PRESENCE_IN_AOI_REPORT" {
+		return nil, fmt.Errorf("unsupported amfEventType %q", report.Type)
+	}
+	if report.TimeStamp == nil {
+		return nil, fmt.Errorf("missing timeStamp")
+	}
+
+	locationReporting := models.LocationReportingChargingInformation{
+		LocationReportingMessageType: ngapLocationReportProcedureCode,
+		UserInformation: &models.UserInformation{
+			ServedGPSI: report.Gpsi,
+			ServedPEI:  report.Pei,
+		},
+		UserLocationinfo: report.Location,
+		UetimeZone:       report.Timezone,
+	}
+	var locationTime *time.Time
+	if location := report.Location; location != nil {
+		switch {
+		case location.EutraLocation != nil:
+			locationReporting.RATType = "EUTRA"
+			locationTime = userLocationTime(*report.TimeStamp,
+				location.EutraLocation.UeLocationTimestamp, location.EutraLocation.AgeOfLocationInformation)
+		case location.NrLocation != nil:
+			locationReporting.RATType = "NR"
+			locationTime = userLocationTime(*report.TimeStamp,
+				location.NrLocation.UeLocationTimestamp, location.NrLocation.AgeOfLocationInformation)
+		}
+	}
+	request := models.ChargingDataRequest{
+		SubscriberIdentifier:                 report.Supi,
+		NfConsumerIdentification:             &amf,
+		InvocationTimeStamp:                  report.TimeStamp,
+		LocationReportingChargingInformation: &locationReporting,
+	}
+
+	var areas []models.PresenceInfo
+	for _, area := range report.AreaList {
+		if area.PresenceInfo != nil {
+			areas = append(areas, *area.PresenceInfo)
+		}
+	}
+	if len(areas) == 0 {
+		record, err := amfLocationRecordToCdr(chfName, request, locationTime)
+		if err != nil {
+			return nil, err
+		}
+		return []cdrType.ChargingRecord{record}, nil
+	}
+
+	records := make([]cdrType.ChargingRecord, 0, len(areas))
+	for _, area := range areas {
+		locationReporting.PresenceReportingAreaInformation = map[string]models.PresenceInfo{area.PraId: area}
+		record, err := amfLocationRecordToCdr(chfName, request, locationTime)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// amfLocationRecordToCdr builds the record of an AMF location report, timing
+// the location by locationTime if known, the charging information of the
+// request having no room for it.
+func amfLocationRecordToCdr(chfName string, request models.ChargingDataRequest, locationTime *time.Time) (
+	cdrType.ChargingRecord, error) {
+	record, err := LocationReportingChargingRecordToCdr(chfName, request)
+	if err != nil {
+		return record, err
+	}
+	if locationTime != nil {
+		timeStamp := TimeStampToCdr(locationTime)
+		record.LocationReportingChargingInformation.UserLocationInfoTime = &timeStamp
+	}
+	return record, nil
+}
+
+// userLocationTime returns when the UE was last known at its location, given
+// its age in minutes before reportTime.
+func userLocationTime(reportTime time.Time, ueLocationTimestamp *time.Time, ageOfLocation int32) *time.Time {
+	if ueLocationTimestamp != nil {
+		return ueLocationTimestamp
+	}
+	locationTime := reportTime.Add(-time.Duration(ageOfLocation) * time.Minute)
+	return &locationTime
+}
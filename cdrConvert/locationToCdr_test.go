@@ -0,0 +1,202 @@
+package cdrConvert
+
+import (
+	"testing"
+	"time"
+
+	"github.com/free5gc/CDRUtil/asn"
+	"github.com/free5gc/CDRUtil/cdrType"
+	"github.com/free5gc/openapi/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPresenceInfoToCdr(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name         string
+		presenceInfo models.PresenceInfo
+		expected     asn.OctetString
+		status       *cdrType.PresenceReportingAreaStatus
+		err          bool
+	}{
+		{"zero", models.PresenceInfo{PraId: "0"}, asn.OctetString{0, 0, 0}, nil, false},
+		{"inArea", models.PresenceInfo{PraId: "65793", PresenceState: "IN_AREA"}, asn.OctetString{1, 1, 1},
+			&cdrType.PresenceReportingAreaStatus{Value: cdrType.PresenceReportingAreaStatusPresentInsideArea}, false},
+		{"largest", models.PresenceInfo{PraId: "16777215", PresenceState: "UNKNOWN"}, asn.OctetString{255, 255, 255},
+			&cdrType.PresenceReportingAreaStatus{Value: cdrType.PresenceReportingAreaStatusPresentUnknown}, false},
+		{"tooLarge", models.PresenceInfo{PraId: "16777216"}, nil, nil, true},
+		{"notNumber", models.PresenceInfo{PraId: "pra"}, nil, nil, true},
+		{"presenceState", models.PresenceInfo{PraId: "1", PresenceState: "NEAR_AREA"}, nil, nil, true},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			presenceInfo, err := PresenceInfoToCdr(tc.presenceInfo)
+			if tc.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, presenceInfo.PresenceReportingAreaIdentifier)
+			require.Equal(t, tc.status, presenceInfo.PresenceReportingAreaStatus)
+		})
+	}
+}
+
+func TestLocationReportingChargingRecordToCdr(t *testing.T) {
+	t.Parallel()
+
+	invocationTime := time.Date(2022, time.May, 4, 10, 0, 0, 0, time.UTC)
+	plmnId := &models.PlmnId{Mcc: "208", Mnc: "93"}
+	locationRequest := func() models.ChargingDataRequest {
+		return models.ChargingDataRequest{
+			InvocationTimeStamp: &invocationTime,
+			LocationReportingChargingInformation: &models.LocationReportingChargingInformation{
+				LocationReportingMessageType: 18,
+				UserInformation:              &models.UserInformation{ServedGPSI: "msisdn-886912345678"},
+				UserLocationinfo: &models.UserLocation{
+					EutraLocation: &models.EutraLocation{
+						Tai:  &models.Tai{PlmnId: plmnId, Tac: "0001"},
+						Ecgi: &models.Ecgi{PlmnId: plmnId, EutraCellId: "0000001"},
+					},
+				},
+				PSCellInformation: &models.PsCellInformation{
+					Nrcgi: &models.Ncgi{PlmnId: plmnId, NrCellId: "000000010"},
+				},
+				RATType: "EUTRA",
+				PresenceReportingAreaInformation: map[string]models.PresenceInfo{
+					"1": {PraId: "1", PresenceState: "OUT_OF_AREA"},
+				},
+			},
+		}
+	}
+
+	record, err := LocationReportingChargingRecordToCdr("chf", locationRequest())
+	require.NoError(t, err)
+	require.Equal(t, cdrType.NetworkFunctionalityPresentAMF, record.NFunctionConsumerInformation.NetworkFunctionality.Value)
+	locationReporting := record.LocationReportingChargingInformation
+	require.Equal(t, int64(18), locationReporting.LocationReportingMessagetype.Value)
+	require.NotNil(t, locationReporting.UserIdentifier)
+	require.NotNil(t, locationReporting.UserLocationInformation)
+	require.NotNil(t, locationReporting.UserLocationInformationASN1)
+	require.Equal(t, cdrType.RATTypePresentEUTRAN, locationReporting.RATType.Value)
+	require.Equal(t, cdrType.PresenceReportingAreaStatusPresentOutsideArea,
+		locationReporting.PresenceReportingAreaInfo.PresenceReportingAreaStatus.Value)
+	require.NotNil(t, locationReporting.PSCellInformation.NRcgi)
+	require.Nil(t, locationReporting.UserLocationInfoTime)
+
+	testCases := []struct {
+		name   string
+		modify func(r *models.ChargingDataRequest)
+	}{
+		{"locationReportingChargingInformation", func(r *models.ChargingDataRequest) {
+			r.LocationReportingChargingInformation = nil
+		}},
+		{"nodeFunctionality", func(r *models.ChargingDataRequest) {
+			r.NfConsumerIdentification = &models.NfIdentification{NodeFunctionality: "SMF"}
+		}},
+		{"userLocationinfo", func(r *models.ChargingDataRequest) {
+			r.LocationReportingChargingInformation.UserLocationinfo.EutraLocation.Tai.Tac = "1"
+		}},
+		{"presenceReportingAreaInformation", func(r *models.ChargingDataRequest) {
+			r.LocationReportingChargingInformation.PresenceReportingAreaInformation["2"] = models.PresenceInfo{PraId: "2"}
+		}},
+		{"praId", func(r *models.ChargingDataRequest) {
+			r.LocationReportingChargingInformation.PresenceReportingAreaInformation["1"] = models.PresenceInfo{}
+		}},
+		{"pSCellInformation", func(r *models.ChargingDataRequest) {
+			r.LocationReportingChargingInformation.PSCellInformation.Nrcgi.PlmnId = nil
+		}},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := locationRequest()
+			tc.modify(&r)
+			_, err := LocationReportingChargingRecordToCdr("chf", r)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestAmfLocationReportToCdr(t *testing.T) {
+	t.Parallel()
+
+	reportTime := time.Date(2022, time.May, 4, 10, 0, 0, 0, time.UTC)
+	locationTime := reportTime.Add(-time.Minute)
+	plmnId := &models.PlmnId{Mcc: "208", Mnc: "93"}
+	amf := models.NfIdentification{NFName: "amf", NodeFunctionality: "AMF"}
+	nrLocation := &models.NrLocation{
+		Tai:                      &models.Tai{PlmnId: plmnId, Tac: "000001"},
+		Ncgi:                     &models.Ncgi{PlmnId: plmnId, NrCellId: "000000010"},
+		AgeOfLocationInformation: 5,
+	}
+	eutraLocation := &models.EutraLocation{
+		Tai:                 &models.Tai{PlmnId: plmnId, Tac: "0001"},
+		Ecgi:                &models.Ecgi{PlmnId: plmnId, EutraCellId: "0000001"},
+		UeLocationTimestamp: &locationTime,
+	}
+	areaList := []models.AmfEventArea{
+		{PresenceInfo: &models.PresenceInfo{PraId: "1", PresenceState: "IN_AREA"}},
+		{LadnInfo: &models.LadnInfo{Ladn: "ladn"}},
+		{PresenceInfo: &models.PresenceInfo{PraId: "2", PresenceState: "OUT_OF_AREA"}},
+	}
+
+	testCases := []struct {
+		name         string
+		report       models.AmfEventReport
+		ratType      int64
+		locationTime time.Time
+		praIds       []asn.OctetString
+	}{
+		{"nrLocation", models.AmfEventReport{
+			Type:      "LOCATION_REPORT",
+			TimeStamp: &reportTime,
+			Supi:      "imsi-208930000000003",
+			Location:  &models.UserLocation{NrLocation: nrLocation},
+		}, cdrType.RATTypePresentNR, reportTime.Add(-5 * time.Minute), []asn.OctetString{nil}},
+		{"presenceInAoi", models.AmfEventReport{
+			Type:      "PRESENCE_IN_AOI_REPORT",
+			TimeStamp: &reportTime,
+			AreaList:  areaList,
+			Location:  &models.UserLocation{EutraLocation: eutraLocation},
+		}, cdrType.RATTypePresentEUTRAN, locationTime, []asn.OctetString{{0, 0, 1}, {0, 0, 2}}},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			records, err := AmfLocationReportToCdr("chf", amf, tc.report)
+			require.NoError(t, err)
+			require.Len(t, records, len(tc.praIds))
+			for i, record := range records {
+				locationReporting := record.LocationReportingChargingInformation
+				require.Equal(t, int64(ngapLocationReportProcedureCode),
+					locationReporting.LocationReportingMessagetype.Value)
+				require.Equal(t, tc.ratType, locationReporting.RATType.Value)
+				require.Equal(t, cdrType.NewTimeStamp(tc.locationTime), *locationReporting.UserLocationInfoTime)
+				if tc.praIds[i] == nil {
+					require.Nil(t, locationReporting.PresenceReportingAreaInfo)
+				} else {
+					require.Equal(t, tc.praIds[i],
+						locationReporting.PresenceReportingAreaInfo.PresenceReportingAreaIdentifier)
+				}
+			}
+		})
+	}
+
+	_, err := AmfLocationReportToCdr("chf", amf,
+		models.AmfEventReport{Type: "REACHABILITY_REPORT", TimeStamp: &reportTime})
+	require.Error(t, err)
+	_, err = AmfLocationReportToCdr("chf", amf, models.AmfEventReport{Type: "LOCATION_REPORT"})
+	require.Error(t, err)
+}
@@ -0,0 +1,223 @@
+package cdrConvert
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/free5gc/CDRUtil/asn"
+	"github.com/free5gc/CDRUtil/cdrType"
+	"github.com/free5gc/openapi/models"
+)
+
+var apiDirectionToCdr = map[models.ApiDirection]asn.Enumerated{
+	"INVOCATION":   cdrType.APIDirectionPresentInvocation,
+	"NOTIFICATION": cdrType.APIDirectionPresentNotification,
+}
+
+func NefChargingInformationToCdr(nef models.NefChargingInformation) (cdrType.ExposureFunctionAPIInformation, error) {
+	var apiInformation cdrType.ExposureFunctionAPIInformation
+
+	if nef.APIName == "" {
+		return apiInformation, fmt.Errorf("missing aPIName")
+	}
+	apiInformation.APIName = asn.IA5String(nef.APIName)
+
+	if nef.GroupIdentifier != "" {
+		groupIdentifier, err := E164AddressToCdr(nef.GroupIdentifier)
+		if err != nil {
+			return apiInformation, fmt.Errorf("groupIdentifier: %v", err)
+		}
+		apiInformation.GroupIdentifier = &groupIdentifier
+	}
+	if nef.APIDirection != "" {
+		value, ok := apiDirectionToCdr[nef.APIDirection]
+		if !ok {
+			return apiInformation, fmt.Errorf("unsupported aPIDirection %q", nef.APIDirection)
+		}
+		apiInformation.APIDirection = &cdrType.APIDirection{Value: value}
+	}
+	if nef.APITargetNetworkFunction != nil {
+		targetNf, err := NfIdentificationToCdr(*nef.APITargetNetworkFunction)
+		if err != nil {
+			return apiInformation, fmt.Errorf("aPITargetNetworkFunction: %v", err)
+		}
+		apiInformation.APITargetNetworkFunction = &targetNf
+	}
+	if nef.APIResultCode != 0 {
+		apiInformation.APIResultCode = &cdrType.APIResultCode{Value: int64(nef.APIResultCode)}
+	}
+	if nef.APIReference != "" {
+		reference := asn.IA5String(nef.APIReference)
+		apiInformation.APIReference = &reference
+	}
+	if nef.APIContent != "" {
+		content := asn.OctetString(nef.APIContent)
+		apiInformation.APIContent = &content
+	}
+	if nef.ExternalIndividualIdentifier != "" {
+		individual, err := GpsiToCdr(nef.ExternalIndividualIdentifier)
+		if err != nil {
+			return apiInformation, err
+		}
+		apiInformation.ExternalIndividualIdentifier = &individual
+	}
+	if nef.ExternalGroupIdentifier != "" {
+		apiInformation.ExternalGroupIdentifier = &cdrType.ExternalGroupIdentifier{
+			Value: asn.UTF8String(nef.ExternalGroupIdentifier),
+		}
+	}
+
+	return apiInformation, nil
+}
+
+// NefChargingRecordToCdr builds the record of a single API invocation or
+// notification reported by a NEF, see OneTimeEventRecordToCdr.
+func NefChargingRecordToCdr(chfName string, request models.ChargingDataRequest) (cdrType.ChargingRecord, error) {
+	if request.NEFChargingInformation == nil {
+		return cdrType.ChargingRecord{}, fmt.Errorf("missing nEFChargingInformation")
+	}
+	record, err := OneTimeEventRecordToCdr(chfName, request, "NEF")
+	if err != nil {
+		return record, err
+	}
+
+	apiInformation, err := NefChargingInformationToCdr(*request.NEFChargingInformation)
+	if err != nil {
+		return record, err
+	}
+	record.ExposureFunctionAPIInformation = &apiInformation
+
+	return record, nil
+}
+
+// NefApiAggregator counts the API calls reported by NEFs into one record per
+// window. Calls share a record when they fall in the same window and only
+// differ in their API content, which is left out of the aggregated record.
+// The count is carried as the service specific units of a single used unit
+// container, along with the time stamp of each call.
+//
+// It is safe for concurrent use.
+type NefApiAggregator struct {
+	chfName string
+	window  time.Duration
+
+	mu      sync.Mutex
+	records map[nefApiKey]*cdrType.ChargingRecord
+	order   []nefApiKey
+}
+
+type nefApiKey struct {
+	windowStart int64
+	// BER encoding of the record with neither time nor API content
+	record string
+}
+
+// NewNefApiAggregator returns an aggregator producing the records of the CHF
+// named chfName for windows of the given length, aligned on the Unix epoch.
+// The length must be a whole number of seconds, the resolution of the time
+// stamps and the duration of the records.
+func NewNefApiAggregator(chfName string, window time.Duration) (*NefApiAggregator, error) {
+	if window < time.Second {
+		return nil, fmt.Errorf("window %v is shorter than a second", window)
+	}
+	if window%time.Second != 0 {
+		return nil, fmt.Errorf("window %v is not a whole number of seconds", window)
+	}
+	return &NefApiAggregator{
+		chfName: chfName,
+		window:  window,
+		records: make(map[nefApiKey]*cdrType.ChargingRecord),
+	}, nil
+}
+
+// Add counts the API call reported by request in its window.
+func (a *NefApiAggregator) Add(request models.ChargingDataRequest) error {
+	record, err := NefChargingRecordToCdr(a.chfName, request)
+	if err != nil {
+		return err
+	}
+	record.ExposureFunctionAPIInformation.APIContent = nil
+
+	// the rating group of the calls, if reported
+	var ratingGroup cdrType.RatingGroupId
+	if len(request.MultipleUnitUsage) > 0 {
+		ratingGroup.Value = int64(request.MultipleUnitUsage[0].RatingGroup)
+	}
+	record.ListOfMultipleUnitUsage = []cdrType.MultipleUnitUsage{{RatingGroup: ratingGroup}}
+
+	record.RecordOpeningTime = cdrType.TimeStamp{}
+	fingerprint, err := asn.BerMarshalWithParams(record, "")
+	if err != nil {
+		return err
+	}
+	windowStart := a.windowStart(*request.InvocationTimeStamp)
+	record.RecordOpeningTime = TimeStampToCdr(&windowStart)
+	key := nefApiKey{windowStart: windowStart.Unix(), record: string(fingerprint)}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	aggregated, ok := a.records[key]
+	if !ok {
+		count := int64(0)
+		record.ListOfMultipleUnitUsage[0].UsedUnitContainers = []cdrType.UsedUnitContainer{{
+			ServiceSpecificUnits: &count,
+		}}
+		record.Duration = cdrType.CallDuration{Value: int64(a.window / time.Second)}
+		aggregated = &record
+		a.records[key] = aggregated
+		a.order = append(a.order, key)
+	}
+	container := &aggregated.ListOfMultipleUnitUsage[0].UsedUnitContainers[0]
+	*container.ServiceSpecificUnits++
+	container.EventTimeStampExt = append(container.EventTimeStampExt, TimeStampToCdr(request.InvocationTimeStamp))
+
+	return nil
+}
+
+// windowStart returns the start of the window of t, in the location of t.
+// Unlike time.Truncate, which counts from the zero time, the windows are
+// counted from the Unix epoch.
+func (a *NefApiAggregator) windowStart(t time.Time) time.Time {
+	offset := t.UnixNano() % int64(a.window)
+	if offset < 0 {
+		offset += int64(a.window)
+	}
+	return t.Add(-time.Duration(offset))
+}
+
+// Close returns the records of the windows ended by now, closed for time
+// limit, in the order of their first call.
+func (a *NefApiAggregator) Close(now time.Time) []cdrType.ChargingRecord {
+	return a.take(func(key nefApiKey) bool {
+		return !time.Unix(key.windowStart, 0).Add(a.window).After(now)
+	}, cdrType.CauseForRecClosingPresentTimeLimit)
+}
+
+// Flush returns the records of all windows, including the current ones,
+// closed for management intervention.
+func (a *NefApiAggregator) Flush() []cdrType.ChargingRecord {
+	return a.take(func(nefApiKey) bool { return true }, cdrType.CauseForRecClosingPresentManagementIntervention)
+}
+
+func (a *NefApiAggregator) take(closed func(nefApiKey) bool, cause int64) []cdrType.ChargingRecord {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	var records []cdrType.ChargingRecord
+	open := a.order[:0]
+	for _, key := range a.order {
+		if !closed(key) {
+			open = append(open, key)
+			continue
+		}
+		record := a.records[key]
+		delete(a.records, key)
+		record.CauseForRecClosing = cdrType.CauseForRecClosing{Value: cause}
+		records = append(records, *record)
+	}
+	a.order = open
+
+	return records
+}
@@ -0,0 +1,146 @@
+package cdrConvert
+
+import (
+	"testing"
+	"time"
+
+	"github.com/free5gc/CDRUtil/cdrType"
+	"github.com/free5gc/openapi/models"
+	"github.com/stretchr/testify/require"
+)
+
+func nefRequest(t time.Time, apiName, apiContent string) models.ChargingDataRequest {
+	return models.ChargingDataRequest{
+		InvocationTimeStamp: &t,
+		NEFChargingInformation: &models.NefChargingInformation{
+			APIName:    apiName,
+			APIContent: apiContent,
+		},
+	}
+}
+
+func TestNefChargingRecordToCdr(t *testing.T) {
+	t.Parallel()
+
+	invocationTime := time.Date(2022, time.May, 4, 10, 0, 0, 0, time.UTC)
+	nefCharging := func() models.NefChargingInformation {
+		return models.NefChargingInformation{
+			ExternalIndividualIdentifier: "extid-user@example.com",
+			ExternalGroupIdentifier:      "group@example.com",
+			GroupIdentifier:              "+886912345678",
+			APIDirection:                 "NOTIFICATION",
+			APITargetNetworkFunction: &models.NfIdentification{
+				NFName:            "fcd3ee5e-8ec8-4a5a-8da8-c64dc6bd25ab",
+				NodeFunctionality: "AMF",
+			},
+			APIResultCode: 200,
+			APIName:       "nnef-eventexposure",
+			APIReference:  "https://nef.example.com/3gpp-monitoring-event/v1",
+			APIContent:    "{}",
+		}
+	}
+
+	request := nefRequest(invocationTime, "", "")
+	*request.NEFChargingInformation = nefCharging()
+	record, err := NefChargingRecordToCdr("chf", request)
+	require.NoError(t, err)
+	require.Equal(t, cdrType.NetworkFunctionalityPresentNEF, record.NFunctionConsumerInformation.NetworkFunctionality.Value)
+	require.Equal(t, cdrType.NewTimeStamp(invocationTime), record.RecordOpeningTime)
+
+	apiInformation := record.ExposureFunctionAPIInformation
+	require.Equal(t, "nnef-eventexposure", string(apiInformation.APIName))
+	require.NotNil(t, apiInformation.ExternalIndividualIdentifier)
+	require.Equal(t, "group@example.com", string(apiInformation.ExternalGroupIdentifier.Value))
+	groupIdentifier, err := apiInformation.GroupIdentifier.Digits()
+	require.NoError(t, err)
+	require.Equal(t, "886912345678", groupIdentifier)
+	require.Equal(t, cdrType.APIDirectionPresentNotification, apiInformation.APIDirection.Value)
+	require.Equal(t, cdrType.NetworkFunctionalityPresentAMF,
+		apiInformation.APITargetNetworkFunction.NetworkFunctionality.Value)
+	require.Equal(t, int64(200), apiInformation.APIResultCode.Value)
+	require.Equal(t, "https://nef.example.com/3gpp-monitoring-event/v1", string(*apiInformation.APIReference))
+	require.Equal(t, "{}", string(*apiInformation.APIContent))
+
+	testCases := []struct {
+		name   string
+		modify func(nef *models.NefChargingInformation)
+	}{
+		{"aPIName", func(nef *models.NefChargingInformation) { nef.APIName = "" }},
+		{"groupIdentifier", func(nef *models.NefChargingInformation) { nef.GroupIdentifier = "group" }},
+		{"aPIDirection", func(nef *models.NefChargingInformation) { nef.APIDirection = "REQUEST" }},
+		{"aPITargetNetworkFunction", func(nef *models.NefChargingInformation) {
+			nef.APITargetNetworkFunction.NodeFunctionality = "UDR"
+		}},
+		{"externalIndividualIdentifier", func(nef *models.NefChargingInformation) {
+			nef.ExternalIndividualIdentifier = "user@example.com"
+		}},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := nefRequest(invocationTime, "", "")
+			*r.NEFChargingInformation = nefCharging()
+			tc.modify(r.NEFChargingInformation)
+			_, err := NefChargingRecordToCdr("chf", r)
+			require.Error(t, err)
+		})
+	}
+
+	_, err = NefChargingRecordToCdr("chf", models.ChargingDataRequest{InvocationTimeStamp: &invocationTime})
+	require.Error(t, err)
+}
+
+func TestNefApiAggregator(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewNefApiAggregator("chf", 500*time.Millisecond)
+	require.Error(t, err)
+	_, err = NewNefApiAggregator("chf", 1500*time.Millisecond)
+	require.Error(t, err)
+
+	// 7 minute windows, which the zero time does not align on the Unix epoch
+	window := 7 * time.Minute
+	aggregator, err := NewNefApiAggregator("chf", window)
+	require.NoError(t, err)
+	windowStart := time.Unix(3932500*7*60, 0)
+	zone := time.FixedZone("", 2*3600)
+	call := windowStart.Add(30 * time.Second).In(zone)
+	require.NotEqual(t, windowStart, call.Truncate(window))
+
+	require.NoError(t, aggregator.Add(nefRequest(call, "nnef-pfdmanagement", "a")))
+	require.NoError(t, aggregator.Add(nefRequest(call.Add(time.Minute), "nnef-pfdmanagement", "b")))
+	require.NoError(t, aggregator.Add(nefRequest(call, "nnef-eventexposure", "")))
+	require.NoError(t, aggregator.Add(nefRequest(call.Add(window), "nnef-pfdmanagement", "")))
+	require.Error(t, aggregator.Add(models.ChargingDataRequest{InvocationTimeStamp: &call}))
+
+	require.Empty(t, aggregator.Close(windowStart.Add(window-time.Second)))
+	records := aggregator.Close(windowStart.Add(window))
+	require.Len(t, records, 2)
+
+	record := records[0]
+	require.Equal(t, "nnef-pfdmanagement", string(record.ExposureFunctionAPIInformation.APIName))
+	require.Nil(t, record.ExposureFunctionAPIInformation.APIContent)
+	openingTime, err := record.RecordOpeningTime.Decode()
+	require.NoError(t, err)
+	require.True(t, windowStart.Equal(openingTime))
+	require.Equal(t, int64(7*60), record.Duration.Value)
+	require.Equal(t, cdrType.CauseForRecClosingPresentTimeLimit, record.CauseForRecClosing.Value)
+	container := record.ListOfMultipleUnitUsage[0].UsedUnitContainers[0]
+	require.Equal(t, int64(2), *container.ServiceSpecificUnits)
+	require.Equal(t, []cdrType.TimeStamp{
+		cdrType.NewTimeStamp(call),
+		cdrType.NewTimeStamp(call.Add(time.Minute)),
+	}, container.EventTimeStampExt)
+	require.Equal(t, "nnef-eventexposure", string(records[1].ExposureFunctionAPIInformation.APIName))
+
+	records = aggregator.Flush()
+	require.Len(t, records, 1)
+	openingTime, err = records[0].RecordOpeningTime.Decode()
+	require.NoError(t, err)
+	require.True(t, windowStart.Add(window).Equal(openingTime))
+	require.Equal(t, cdrType.CauseForRecClosingPresentManagementIntervention, records[0].CauseForRecClosing.Value)
+	require.Empty(t, aggregator.Flush())
+}
@@ -0,0 +1,474 @@
+package cdrConvert
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/free5gc/CDRUtil/asn"
+	"github.com/free5gc/CDRUtil/cdrType"
+	"github.com/free5gc/openapi/models"
+)
+
+var managementOperationToCdr = map[models.ManagementOperation]asn.Enumerated{
+	"CREATE_MOI":      cdrType.ManagementOperationPresentCreateMOI,
+	"MODIFY_MOI_ATTR": cdrType.ManagementOperationPresentModifyMOIAttributes,
+	"DELETE_MOI":      cdrType.ManagementOperationPresentDeleteMOI,
+}
+
+var managementOperationStatusToCdr = map[models.ManagementOperationStatus]asn.Enumerated{
+	"OPERATION_SUCCEEDED": cdrType.ManagementOperationStatusPresentOPERATIONSUCCEEDED,
+	"OPERATION_FAILED":    cdrType.ManagementOperationStatusPresentOPERATIONFAILED,
+}
+
+var operationalStateToCdr = map[string]asn.Enumerated{
+	"ENABLED":  cdrType.OperationalStatePresentENABLED,
+	"DISABLED": cdrType.OperationalStatePresentDISABLED,
+}
+
+var administrativeStateToCdr = map[string]asn.Enumerated{
+	"LOCKED":       cdrType.AdministrativeStatePresentLOCKED,
+	"UNLOCKED":     cdrType.AdministrativeStatePresentUNLOCKED,
+	"SHUTTINGDOWN": cdrType.AdministrativeStatePresentSHUTTINGDOWN,
+}
+
+var sharingLevelToCdr = map[string]asn.Enumerated{
+	"SHARED":     cdrType.SharingLevelPresentSHARED,
+	"NON_SHARED": cdrType.SharingLevelPresentNONSHARED,
+}
+
+var mobilityLevelToCdr = map[string]asn.Enumerated{
+	"STATIONARY":          cdrType.MobilityLevelPresentStationary,
+	"NOMADIC":             cdrType.MobilityLevelPresentNomadic,
+	"RESTRICTED_MOBILITY": cdrType.MobilityLevelPresentRestrictedMobility,
+	"FULLY_MOBILITY":      cdrType.MobilityLevelPresentFullyMobility,
+}
+
+var delayToleranceIndicatorToCdr = map[string]asn.Enumerated{
+	"SUPPORTED":     cdrType.DelayToleranceIndicatorPresentDTSupported,
+	"NOT_SUPPORTED": cdrType.DelayToleranceIndicatorPresentDTNotSupported,
+}
+
+var v2xCommunicationModeIndicatorToCdr = map[string]asn.Enumerated{
+	"SUPPORTED":     cdrType.V2XCommunicationModeIndicatorPresentV2XComSupported,
+	"NOT_SUPPORTED": cdrType.V2XCommunicationModeIndicatorPresentV2XComNotSupported,
+}
+
+// NsmStates are the operational and administrative states of the network
+// slice instance, members of the NSM charging information of TS 32.291 that
+// the models of openapi v1.0.7 leave out. The values are those of TS 32.291,
+// such as "ENABLED" and "LOCKED"; empty ones are not reported.
+type NsmStates struct {
+	ManagementOperationalState    string
+	ManagementAdministrativeState string
+}
+
+// ServiceProfileAttributes are the members of a service profile of TS 32.291
+// that the models of openapi v1.0.7 leave out. The values are those of
+// TS 32.291, such as "NON_SHARED"; zero ones are not reported.
+type ServiceProfileAttributes struct {
+	SST                           int32
+	ResourceSharingLevel          string
+	UEMobilityLevel               string
+	DelayToleranceIndicator       string
+	V2XCommunicationModeIndicator string
+}
+
+// enumToCdr returns the value of the member named name in values.
+func enumToCdr(name, value string, values map[string]asn.Enumerated) (asn.Enumerated, error) {
+	v, ok := values[value]
+	if !ok {
+		return 0, fmt.Errorf("unsupported %s %q", name, value)
+	}
+	return v, nil
+}
+
+// ThroughputToCdr keeps the guaranteed and maximum throughputs, in kbit/s,
+// as bit rate strings such as "100 Kbps".
+func ThroughputToCdr(throughput models.Throughput) cdrType.Throughput {
+	bitrate := func(kbps float32) cdrType.Bitrate {
+		return cdrType.Bitrate{Value: asn.OctetString(strconv.FormatFloat(float64(kbps), 'f', -1, 32) + " Kbps")}
+	}
+	return cdrType.Throughput{
+		GuaranteedThpt: bitrate(throughput.GuaranteedThpt),
+		MaximumThpt:    bitrate(throughput.MaximumThpt),
+	}
+}
+
+func ServiceProfileChargingInformationToCdr(serviceProfile models.ServiceProfileChargingInformation,
+	attributes ServiceProfileAttributes) (cdrType.ServiceProfileChargingInformation, error) {
+	var cdrProfile cdrType.ServiceProfileChargingInformation
+
+	optionalInt := func(v int32) *int64 {
+		if v == 0 {
+			return nil
+		}
+		i := int64(v)
+		return &i
+	}
+	optionalOctets := func(v string) *asn.OctetString {
+		if v == "" {
+			return nil
+		}
+		octets := asn.OctetString(v)
+		return &octets
+	}
+
+	cdrProfile.ServiceProfileIdentifier = optionalOctets(serviceProfile.ServiceProfileIdentifier)
+	if len(serviceProfile.SNSSAIList) > 0 {
+		snssaiList, err := SnssaiListToCdr(serviceProfile.SNSSAIList)
+		if err != nil {
+			return cdrProfile, err
+		}
+		cdrProfile.SNSSAIList = snssaiList
+	}
+	if attributes.SST != 0 {
+		cdrProfile.SST = &cdrType.SliceServiceType{Value: int64(attributes.SST)}
+	}
+	cdrProfile.Latency = optionalInt(serviceProfile.Latency)
+	if serviceProfile.Availability != 0 {
+		availability := int64(math.Round(float64(serviceProfile.Availability)))
+		cdrProfile.Availability = &availability
+	}
+	if attributes.ResourceSharingLevel != "" {
+		value, err := enumToCdr("resourceSharingLevel", attributes.ResourceSharingLevel, sharingLevelToCdr)
+		if err != nil {
+			return cdrProfile, err
+		}
+		cdrProfile.ResourceSharingLevel = &cdrType.SharingLevel{Value: value}
+	}
+	cdrProfile.Jitter = optionalInt(serviceProfile.Jitter)
+	cdrProfile.Reliability = optionalOctets(serviceProfile.Reliability)
+	cdrProfile.MaxNumberofUEs = optionalInt(serviceProfile.MaxNumberofUEs)
+	cdrProfile.CoverageArea = optionalOctets(serviceProfile.CoverageArea)
+	if attributes.UEMobilityLevel != "" {
+		value, err := enumToCdr("uEMobilityLevel", attributes.UEMobilityLevel, mobilityLevelToCdr)
+		if err != nil {
+			return cdrProfile, err
+		}
+		cdrProfile.UEMobilityLevel = &cdrType.MobilityLevel{Value: value}
+	}
+	if attributes.DelayToleranceIndicator != "" {
+		value, err := enumToCdr("delayToleranceIndicator", attributes.DelayToleranceIndicator,
+			delayToleranceIndicatorToCdr)
+		if err != nil {
+			return cdrProfile, err
+		}
+		cdrProfile.DelayToleranceIndicator = &cdrType.DelayToleranceIndicator{Value: value}
+	}
+	for _, t := range []struct {
+		throughput *models.Throughput
+		cdr        **cdrType.Throughput
+	}{
+		{serviceProfile.DLThptPerSlice, &cdrProfile.DLThroughtputPerSlice},
+		{serviceProfile.DLThptPerUE, &cdrProfile.DLThroughtputPerUE},
+		{serviceProfile.ULThptPerSlice, &cdrProfile.ULThroughtputPerSlice},
+		{serviceProfile.ULThptPerUE, &cdrProfile.ULThroughtputPerUE},
+	} {
+		if t.throughput != nil {
+			throughput := ThroughputToCdr(*t.throughput)
+			*t.cdr = &throughput
+		}
+	}
+	cdrProfile.MaxNumberofPDUsessions = optionalInt(serviceProfile.MaxNumberofPDUsessions)
+	cdrProfile.KPIsMonitoringList = optionalOctets(serviceProfile.KPIMonitoringList)
+	cdrProfile.SupportedAccessTechnology = optionalInt(serviceProfile.SupportedAccessTechnology)
+	if attributes.V2XCommunicationModeIndicator != "" {
+		value, err := enumToCdr("v2XCommunicationModeIndicator", attributes.V2XCommunicationModeIndicator,
+			v2xCommunicationModeIndicatorToCdr)
+		if err != nil {
+			return cdrProfile, err
+		}
+		cdrProfile.V2XCommunicationMode = &cdrType.V2XCommunicationModeIndicator{Value: value}
+	}
+	cdrProfile.AddServiceProfileChargingInfo = optionalOctets(serviceProfile.AddServiceProfileInfo)
+
+	return cdrProfile, nil
+}
+
+// NsmChargingInformationToCdr converts the NSM charging information along
+// with the members openapi v1.0.7 leaves out: the states of the slice
+// instance and the attributes of each service profile, in the order of the
+// list of service profiles. Attributes may be missing for the last profiles.
+func NsmChargingInformationToCdr(nsm models.NsmChargingInformation, states NsmStates,
+	attributes []ServiceProfileAttributes) (cdrType.NSMChargingInformation, error) {
+	var cdrNsm cdrType.NSMChargingInformation
+
+	if nsm.ManagementOperation != "" {
+		value, ok := managementOperationToCdr[nsm.ManagementOperation]
+		if !ok {
+			return cdrNsm, fmt.Errorf("unsupported managementOperation %q", nsm.ManagementOperation)
+		}
+		cdrNsm.ManagementOperation = &cdrType.ManagementOperation{Value: value}
+	}
+	if nsm.IdNetworkSliceInstance != "" {
+		nsiId := asn.OctetString(nsm.IdNetworkSliceInstance)
+		cdrNsm.IDnetworkSliceInstance = &nsiId
+	}
+	if len(attributes) > len(nsm.ListOfserviceProfileChargingInformation) {
+		return cdrNsm, fmt.Errorf("attributes of %d service profiles for %d", len(attributes),
+			len(nsm.ListOfserviceProfileChargingInformation))
+	}
+	for i, serviceProfile := range nsm.ListOfserviceProfileChargingInformation {
+		var profileAttributes ServiceProfileAttributes
+		if i < len(attributes) {
+			profileAttributes = attributes[i]
+		}
+		cdrProfile, err := ServiceProfileChargingInformationToCdr(serviceProfile, profileAttributes)
+		if err != nil {
+			return cdrNsm, err
+		}
+		cdrNsm.ListOfserviceProfileChargingInformation = append(cdrNsm.ListOfserviceProfileChargingInformation,
+			cdrProfile)
+	}
+	if nsm.ManagementOperationStatus != "" {
+		value, ok := managementOperationStatusToCdr[nsm.ManagementOperationStatus]
+		if !ok {
+			return cdrNsm, fmt.Errorf("unsupported managementOperationStatus %q", nsm.ManagementOperationStatus)
+		}
+		cdrNsm.ManagementOperationStatus = &cdrType.ManagementOperationStatus{Value: value}
+	}
+	if states.ManagementOperationalState != "" {
+		value, err := enumToCdr("managementOperationalState", states.ManagementOperationalState,
+			operationalStateToCdr)
+		if err != nil {
+			return cdrNsm, err
+		}
+		cdrNsm.OperationalState = &cdrType.OperationalState{Value: value}
+	}
+	if states.ManagementAdministrativeState != "" {
+		value, err := enumToCdr("managementAdministrativeState", states.ManagementAdministrativeState,
+			administrativeStateToCdr)
+		if err != nil {
+			return cdrNsm, err
+		}
+		cdrNsm.AdministrativeState = &cdrType.AdministrativeState{Value: value}
+	}
+
+	return cdrNsm, nil
+}
+
+func NsiLoadLevelInfoToCdr(loadLevel models.NsiLoadLevelInfo) (cdrType.NsiLoadLevelInfo, error) {
+	var cdrLoadLevel cdrType.NsiLoadLevelInfo
+
+	loadLevelInformation := int64(loadLevel.LoadLevelInformation)
+	cdrLoadLevel.LoadLevelInformation = &loadLevelInformation
+	if loadLevel.Snssai != nil {
+		snssai, err := SnssaiToCdr(*loadLevel.Snssai)
+		if err != nil {
+			return cdrLoadLevel, err
+		}
+		cdrLoadLevel.Snssai = &snssai
+	}
+	if loadLevel.NsiId != "" {
+		nsiId := asn.OctetString(loadLevel.NsiId)
+		cdrLoadLevel.NsiId = &nsiId
+	}
+	return cdrLoadLevel, nil
+}
+
+func NetworkAreaInfoToCdr(networkArea models.NetworkAreaInfo) (cdrType.NetworkAreaInfo, error) {
+	var cdrNetworkArea cdrType.NetworkAreaInfo
+
+	for _, ecgi := range networkArea.Ecgis {
+		cdrEcgi, err := EcgiToCdr(ecgi)
+		if err != nil {
+			return cdrNetworkArea, err
+		}
+		cdrNetworkArea.Ecgis = append(cdrNetworkArea.Ecgis, cdrEcgi)
+	}
+	for _, ncgi := range networkArea.Ncgis {
+		cdrNcgi, err := NcgiToCdr(ncgi)
+		if err != nil {
+			return cdrNetworkArea, err
+		}
+		cdrNetworkArea.Ncgis = append(cdrNetworkArea.Ncgis, cdrNcgi)
+	}
+	for _, ranNodeId := range networkArea.GRanNodeIds {
+		cdrRanNodeId, err := GlobalRanNodeIdToCdr(ranNodeId)
+		if err != nil {
+			return cdrNetworkArea, err
+		}
+		cdrNetworkArea.GRanNodeIds = append(cdrNetworkArea.GRanNodeIds, cdrRanNodeId)
+	}
+	if len(networkArea.Tais) > 0 {
+		tais, err := TaiListToCdr(networkArea.Tais)
+		if err != nil {
+			return cdrNetworkArea, err
+		}
+		cdrNetworkArea.Tais = tais
+	}
+	return cdrNetworkArea, nil
+}
+
+// ServiceExperienceInfoToCdr converts the service experience statistics of
+// the NWDAF. The mean opinion scores and variance are rounded to integers.
+func ServiceExperienceInfoToCdr(serviceExperience models.ServiceExperienceInfo) (cdrType.ServiceExperienceInfo, error) {
+	var cdrServiceExperience cdrType.ServiceExperienceInfo
+
+	round := func(v float32) *int64 {
+		i := int64(math.Round(float64(v)))
+		return &i
+	}
+
+	if svcExprc := serviceExperience.SvcExprc; svcExprc != nil {
+		cdrServiceExperience.SvcExprc = &cdrType.SvcExperience{
+			Mos:        round(svcExprc.Mos),
+			UpperRange: round(svcExprc.UpperRange),
+			LowerRange: round(svcExprc.LowerRange),
+		}
+	}
+	if serviceExperience.SvcExprcVariance != 0 {
+		cdrServiceExperience.SvcExprcVariance = round(serviceExperience.SvcExprcVariance)
+	}
+	if serviceExperience.Snssai != nil {
+		snssai, err := SnssaiToCdr(*serviceExperience.Snssai)
+		if err != nil {
+			return cdrServiceExperience, err
+		}
+		cdrServiceExperience.Snssai = &snssai
+	}
+	if serviceExperience.AppId != "" {
+		appId := asn.OctetString(serviceExperience.AppId)
+		cdrServiceExperience.AppId = &appId
+	}
+	if serviceExperience.Confidence != 0 {
+		confidence := int64(serviceExperience.Confidence)
+		cdrServiceExperience.Confidence = &confidence
+	}
+	if serviceExperience.Dnn != "" {
+		cdrServiceExperience.Dnn = &cdrType.DataNetworkNameIdentifier{Value: asn.IA5String(serviceExperience.Dnn)}
+	}
+	if serviceExperience.NetworkArea != nil {
+		networkArea, err := NetworkAreaInfoToCdr(*serviceExperience.NetworkArea)
+		if err != nil {
+			return cdrServiceExperience, err
+		}
+		cdrServiceExperience.NetworkArea = &networkArea
+	}
+	if serviceExperience.NsiId != "" {
+		nsiId := asn.OctetString(serviceExperience.NsiId)
+		cdrServiceExperience.NsiId = &nsiId
+	}
+	if serviceExperience.Ratio != 0 {
+		ratio := int64(serviceExperience.Ratio)
+		cdrServiceExperience.Ratio = &ratio
+	}
+	return cdrServiceExperience, nil
+}
+
+func NspaContainerInformationToCdr(container models.NspaContainerInformation) (cdrType.NSPAContainerInformation, error) {
+	var cdrContainer cdrType.NSPAContainerInformation
+
+	if container.Latency != 0 {
+		latency := int64(container.Latency)
+		cdrContainer.Latency = &latency
+	}
+	if container.Throughput != nil {
+		throughput := ThroughputToCdr(*container.Throughput)
+		cdrContainer.Throughput = &throughput
+	}
+	if container.MaximumPacketLossRate != "" {
+		lossRate := asn.UTF8String(container.MaximumPacketLossRate)
+		cdrContainer.MaximumPacketLossRate = &lossRate
+	}
+	if container.ServiceExperienceStatisticsData != nil {
+		serviceExperience, err := ServiceExperienceInfoToCdr(*container.ServiceExperienceStatisticsData)
+		if err != nil {
+			return cdrContainer, err
+		}
+		cdrContainer.ServiceExperienceStatisticsData = &serviceExperience
+	}
+	if container.TheNumberOfPDUSessions != 0 {
+		pduSessions := int64(container.TheNumberOfPDUSessions)
+		cdrContainer.NumberOfPDUSessions = &pduSessions
+	}
+	if container.TheNumberOfRegisteredSubscribers != 0 {
+		subscribers := int64(container.TheNumberOfRegisteredSubscribers)
+		cdrContainer.NumberOfRegisteredSubscribers = &subscribers
+	}
+	if container.LoadLevel != nil {
+		loadLevel, err := NsiLoadLevelInfoToCdr(*container.LoadLevel)
+		if err != nil {
+			return cdrContainer, err
+		}
+		cdrContainer.LoadLevel = &loadLevel
+	}
+	return cdrContainer, nil
+}
+
+func mnsRecordToCdr(chfName string, request models.ChargingDataRequest) (cdrType.ChargingRecord, error) {
+	record, err := OneTimeEventRecordToCdr(chfName, request, "MnS_Producer")
+	if err != nil {
+		return record, err
+	}
+	if request.MnSConsumerIdentifier != "" {
+		record.MnSConsumerIdentifier = &cdrType.MnSConsumerIdentifier{
+			Value: asn.OctetString(request.MnSConsumerIdentifier),
+		}
+	}
+	return record, nil
+}
+
+// NsmChargingRecordToCdr builds the record of a network slice management
+// operation reported by the MnS producer, see OneTimeEventRecordToCdr and
+// NsmChargingInformationToCdr.
+func NsmChargingRecordToCdr(chfName string, request models.ChargingDataRequest, states NsmStates,
+	attributes []ServiceProfileAttributes) (cdrType.ChargingRecord, error) {
+	if request.NSMChargingInformation == nil {
+		return cdrType.ChargingRecord{}, fmt.Errorf("missing nSMChargingInformation")
+	}
+	record, err := mnsRecordToCdr(chfName, request)
+	if err != nil {
+		return record, err
+	}
+
+	nsm, err := NsmChargingInformationToCdr(*request.NSMChargingInformation, states, attributes)
+	if err != nil {
+		return record, err
+	}
+	record.NSMChargingInformation = &nsm
+
+	return record, nil
+}
+
+// NspaChargingRecordToCdr builds the record of the network slice performance
+// and analytics usage reported by the MnS producer. The NSPA container
+// information is kept in the used unit containers it was reported in.
+func NspaChargingRecordToCdr(chfName string, request models.ChargingDataRequest) (cdrType.ChargingRecord, error) {
+	if request.NSPAChargingInformation == nil || request.NSPAChargingInformation.SingleNSSAI == nil {
+		return cdrType.ChargingRecord{}, fmt.Errorf("missing nSPAChargingInformation singleNSSAI")
+	}
+	record, err := mnsRecordToCdr(chfName, request)
+	if err != nil {
+		return record, err
+	}
+
+	snssai, err := SnssaiToCdr(*request.NSPAChargingInformation.SingleNSSAI)
+	if err != nil {
+		return record, err
+	}
+	record.NSPAChargingInformation = &cdrType.NSPAChargingInformation{SingelNSSAI: snssai}
+
+	multipleUnitUsage, err := MultiUnitUsageToCdr(request.MultipleUnitUsage)
+	if err != nil {
+		return record, err
+	}
+	for i, unitUsage := range request.MultipleUnitUsage {
+		for j, usedUnitContainer := range unitUsage.UsedUnitContainer {
+			if usedUnitContainer.NSPAContainerInformation == nil {
+				continue
+			}
+			container, err := NspaContainerInformationToCdr(*usedUnitContainer.NSPAContainerInformation)
+			if err != nil {
+				return record, err
+			}
+			multipleUnitUsage[i].UsedUnitContainers[j].NSPAContainerInformation = &container
+		}
+	}
+	if len(multipleUnitUsage) > 0 {
+		record.ListOfMultipleUnitUsage = multipleUnitUsage
+	}
+
+	return record, nil
+}
@@ -0,0 +1,272 @@
+package cdrConvert
+
+import (
+	"testing"
+	"time"
+
+	"github.com/free5gc/CDRUtil/asn"
+	"github.com/free5gc/CDRUtil/cdrType"
+	"github.com/free5gc/openapi/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestThroughputToCdr(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name       string
+		throughput models.Throughput
+		guaranteed string
+		maximum    string
+	}{
+		{"zero", models.Throughput{}, "0 Kbps", "0 Kbps"},
+		{"integer", models.Throughput{GuaranteedThpt: 100, MaximumThpt: 1000000}, "100 Kbps", "1000000 Kbps"},
+		{"fraction", models.Throughput{GuaranteedThpt: 0.5, MaximumThpt: 2.25}, "0.5 Kbps", "2.25 Kbps"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			throughput := ThroughputToCdr(tc.throughput)
+			require.Equal(t, tc.guaranteed, string(throughput.GuaranteedThpt.Value))
+			require.Equal(t, tc.maximum, string(throughput.MaximumThpt.Value))
+		})
+	}
+}
+
+func nsmRequest() models.ChargingDataRequest {
+	invocationTime := time.Date(2022, time.May, 4, 10, 0, 0, 0, time.UTC)
+	return models.ChargingDataRequest{
+		InvocationTimeStamp:   &invocationTime,
+		MnSConsumerIdentifier: "consumer",
+		NSMChargingInformation: &models.NsmChargingInformation{
+			ManagementOperation:    "CREATE_MOI",
+			IdNetworkSliceInstance: "nsi-1",
+			ListOfserviceProfileChargingInformation: []models.ServiceProfileChargingInformation{{
+				ServiceProfileIdentifier: "profile-1",
+				SNSSAIList:               []models.Snssai{{Sst: 1, Sd: "010203"}},
+				Latency:                  10,
+				Availability:             99.9,
+				MaxNumberofUEs:           1000,
+				DLThptPerSlice:           &models.Throughput{GuaranteedThpt: 100, MaximumThpt: 200},
+				ULThptPerUE:              &models.Throughput{GuaranteedThpt: 10, MaximumThpt: 20},
+			}},
+			ManagementOperationStatus: "OPERATION_SUCCEEDED",
+		},
+	}
+}
+
+// nsmInput is what NsmChargingRecordToCdr converts.
+type nsmInput struct {
+	request    models.ChargingDataRequest
+	states     NsmStates
+	attributes []ServiceProfileAttributes
+}
+
+func newNsmInput() nsmInput {
+	return nsmInput{
+		request: nsmRequest(),
+		states:  NsmStates{ManagementOperationalState: "ENABLED", ManagementAdministrativeState: "SHUTTINGDOWN"},
+		attributes: []ServiceProfileAttributes{{
+			SST:                           1,
+			ResourceSharingLevel:          "NON_SHARED",
+			UEMobilityLevel:               "NOMADIC",
+			DelayToleranceIndicator:       "SUPPORTED",
+			V2XCommunicationModeIndicator: "NOT_SUPPORTED",
+		}},
+	}
+}
+
+func TestNsmChargingRecordToCdr(t *testing.T) {
+	t.Parallel()
+
+	in := newNsmInput()
+	record, err := NsmChargingRecordToCdr("chf", in.request, in.states, in.attributes)
+	require.NoError(t, err)
+	require.Equal(t, cdrType.NetworkFunctionalityPresentMnSProducer,
+		record.NFunctionConsumerInformation.NetworkFunctionality.Value)
+	require.Equal(t, asn.OctetString("consumer"), record.MnSConsumerIdentifier.Value)
+
+	nsm := record.NSMChargingInformation
+	require.Equal(t, cdrType.ManagementOperationPresentCreateMOI, nsm.ManagementOperation.Value)
+	require.Equal(t, asn.OctetString("nsi-1"), *nsm.IDnetworkSliceInstance)
+	require.Equal(t, cdrType.ManagementOperationStatusPresentOPERATIONSUCCEEDED, nsm.ManagementOperationStatus.Value)
+	require.Equal(t, cdrType.OperationalStatePresentENABLED, nsm.OperationalState.Value)
+	require.Equal(t, cdrType.AdministrativeStatePresentSHUTTINGDOWN, nsm.AdministrativeState.Value)
+	require.Len(t, nsm.ListOfserviceProfileChargingInformation, 1)
+	profile := nsm.ListOfserviceProfileChargingInformation[0]
+	require.Equal(t, asn.OctetString("profile-1"), *profile.ServiceProfileIdentifier)
+	require.Len(t, profile.SNSSAIList, 1)
+	require.Equal(t, int64(10), *profile.Latency)
+	require.Equal(t, int64(100), *profile.Availability)
+	require.Nil(t, profile.Jitter)
+	require.Equal(t, int64(1000), *profile.MaxNumberofUEs)
+	require.Equal(t, "200 Kbps", string(profile.DLThroughtputPerSlice.MaximumThpt.Value))
+	require.Nil(t, profile.DLThroughtputPerUE)
+	require.Nil(t, profile.ULThroughtputPerSlice)
+	require.Equal(t, "10 Kbps", string(profile.ULThroughtputPerUE.GuaranteedThpt.Value))
+	require.Equal(t, int64(1), profile.SST.Value)
+	require.Equal(t, cdrType.SharingLevelPresentNONSHARED, profile.ResourceSharingLevel.Value)
+	require.Equal(t, cdrType.MobilityLevelPresentNomadic, profile.UEMobilityLevel.Value)
+	require.Equal(t, cdrType.DelayToleranceIndicatorPresentDTSupported, profile.DelayToleranceIndicator.Value)
+	require.Equal(t, cdrType.V2XCommunicationModeIndicatorPresentV2XComNotSupported,
+		profile.V2XCommunicationMode.Value)
+
+	// neither states nor attributes reported
+	record, err = NsmChargingRecordToCdr("chf", nsmRequest(), NsmStates{}, nil)
+	require.NoError(t, err)
+	nsm = record.NSMChargingInformation
+	require.Nil(t, nsm.OperationalState)
+	require.Nil(t, nsm.AdministrativeState)
+	profile = nsm.ListOfserviceProfileChargingInformation[0]
+	require.Nil(t, profile.SST)
+	require.Nil(t, profile.ResourceSharingLevel)
+	require.Nil(t, profile.UEMobilityLevel)
+	require.Nil(t, profile.DelayToleranceIndicator)
+	require.Nil(t, profile.V2XCommunicationMode)
+
+	testCases := []struct {
+		name   string
+		modify func(in *nsmInput)
+	}{
+		{"nSMChargingInformation", func(in *nsmInput) { in.request.NSMChargingInformation = nil }},
+		{"nodeFunctionality", func(in *nsmInput) {
+			in.request.NfConsumerIdentification = &models.NfIdentification{NodeFunctionality: "NEF"}
+		}},
+		{"managementOperation", func(in *nsmInput) {
+			in.request.NSMChargingInformation.ManagementOperation = "READ_MOI"
+		}},
+		{"sNSSAIList", func(in *nsmInput) {
+			in.request.NSMChargingInformation.ListOfserviceProfileChargingInformation[0].SNSSAIList[0].Sd = "0102"
+		}},
+		{"managementOperationStatus", func(in *nsmInput) {
+			in.request.NSMChargingInformation.ManagementOperationStatus = "OPERATION_PENDING"
+		}},
+		{"managementOperationalState", func(in *nsmInput) { in.states.ManagementOperationalState = "UNKNOWN" }},
+		{"managementAdministrativeState", func(in *nsmInput) {
+			in.states.ManagementAdministrativeState = "SHUTTING_DOWN"
+		}},
+		{"resourceSharingLevel", func(in *nsmInput) { in.attributes[0].ResourceSharingLevel = "PARTLY_SHARED" }},
+		{"uEMobilityLevel", func(in *nsmInput) { in.attributes[0].UEMobilityLevel = "MOBILE" }},
+		{"delayToleranceIndicator", func(in *nsmInput) { in.attributes[0].DelayToleranceIndicator = "TRUE" }},
+		{"v2XCommunicationModeIndicator", func(in *nsmInput) {
+			in.attributes[0].V2XCommunicationModeIndicator = "TRUE"
+		}},
+		{"attributes", func(in *nsmInput) { in.attributes = append(in.attributes, ServiceProfileAttributes{}) }},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			in := newNsmInput()
+			tc.modify(&in)
+			_, err := NsmChargingRecordToCdr("chf", in.request, in.states, in.attributes)
+			require.Error(t, err)
+		})
+	}
+}
+
+func nspaRequest() models.ChargingDataRequest {
+	invocationTime := time.Date(2022, time.May, 4, 10, 0, 0, 0, time.UTC)
+	plmnId := &models.PlmnId{Mcc: "208", Mnc: "93"}
+	return models.ChargingDataRequest{
+		InvocationTimeStamp:     &invocationTime,
+		NSPAChargingInformation: &models.NspaChargingInformation{SingleNSSAI: &models.Snssai{Sst: 1, Sd: "010203"}},
+		MultipleUnitUsage: []models.MultipleUnitUsage{{
+			RatingGroup: 3,
+			UsedUnitContainer: []models.UsedUnitContainer{
+				{LocalSequenceNumber: 1},
+				{
+					LocalSequenceNumber: 2,
+					NSPAContainerInformation: &models.NspaContainerInformation{
+						Latency:               5,
+						Throughput:            &models.Throughput{GuaranteedThpt: 100, MaximumThpt: 200},
+						MaximumPacketLossRate: "1E-3",
+						ServiceExperienceStatisticsData: &models.ServiceExperienceInfo{
+							SvcExprc:         &models.SvcExperience{Mos: 4.4, UpperRange: 4.6, LowerRange: 3.5},
+							SvcExprcVariance: 0.4,
+							AppId:            "app",
+							Dnn:              "internet",
+							NetworkArea: &models.NetworkAreaInfo{
+								Ncgis: []models.Ncgi{{PlmnId: plmnId, NrCellId: "000000010"}},
+								Tais:  []models.Tai{{PlmnId: plmnId, Tac: "000001"}},
+							},
+						},
+						TheNumberOfPDUSessions:           20,
+						TheNumberOfRegisteredSubscribers: 10,
+						LoadLevel:                        &models.NsiLoadLevelInfo{LoadLevelInformation: 50, NsiId: "nsi-1"},
+					},
+				},
+			},
+		}},
+	}
+}
+
+func TestNspaChargingRecordToCdr(t *testing.T) {
+	t.Parallel()
+
+	record, err := NspaChargingRecordToCdr("chf", nspaRequest())
+	require.NoError(t, err)
+	require.Equal(t, int64(1), record.NSPAChargingInformation.SingelNSSAI.SST.Value)
+	require.Len(t, record.ListOfMultipleUnitUsage, 1)
+	containers := record.ListOfMultipleUnitUsage[0].UsedUnitContainers
+	require.Len(t, containers, 2)
+	require.Nil(t, containers[0].NSPAContainerInformation)
+
+	nspa := containers[1].NSPAContainerInformation
+	require.Equal(t, int64(5), *nspa.Latency)
+	require.Equal(t, "100 Kbps", string(nspa.Throughput.GuaranteedThpt.Value))
+	require.Equal(t, asn.UTF8String("1E-3"), *nspa.MaximumPacketLossRate)
+	serviceExperience := nspa.ServiceExperienceStatisticsData
+	require.Equal(t, int64(4), *serviceExperience.SvcExprc.Mos)
+	require.Equal(t, int64(5), *serviceExperience.SvcExprc.UpperRange)
+	require.Equal(t, int64(4), *serviceExperience.SvcExprc.LowerRange)
+	require.Equal(t, int64(0), *serviceExperience.SvcExprcVariance)
+	require.Equal(t, asn.OctetString("app"), *serviceExperience.AppId)
+	require.Equal(t, "internet", string(serviceExperience.Dnn.Value))
+	require.Len(t, serviceExperience.NetworkArea.Ncgis, 1)
+	require.Len(t, serviceExperience.NetworkArea.Tais, 1)
+	require.Equal(t, int64(20), *nspa.NumberOfPDUSessions)
+	require.Equal(t, int64(10), *nspa.NumberOfRegisteredSubscribers)
+	require.Equal(t, int64(50), *nspa.LoadLevel.LoadLevelInformation)
+	require.Nil(t, nspa.LoadLevel.Snssai)
+
+	nspaContainer := func(r *models.ChargingDataRequest) *models.NspaContainerInformation {
+		return r.MultipleUnitUsage[0].UsedUnitContainer[1].NSPAContainerInformation
+	}
+	testCases := []struct {
+		name   string
+		modify func(r *models.ChargingDataRequest)
+	}{
+		{"nSPAChargingInformation", func(r *models.ChargingDataRequest) { r.NSPAChargingInformation = nil }},
+		{"singleNSSAI", func(r *models.ChargingDataRequest) { r.NSPAChargingInformation.SingleNSSAI.Sd = "x" }},
+		{"multihomedPDUAddress", func(r *models.ChargingDataRequest) {
+			r.MultipleUnitUsage[0].MultihomedPDUAddress = &models.PduAddress{PduIPv4Address: "10.60.0"}
+		}},
+		{"ncgis", func(r *models.ChargingDataRequest) {
+			nspaContainer(r).ServiceExperienceStatisticsData.NetworkArea.Ncgis[0].PlmnId = nil
+		}},
+		{"tais", func(r *models.ChargingDataRequest) {
+			nspaContainer(r).ServiceExperienceStatisticsData.NetworkArea.Tais[0].Tac = "01"
+		}},
+		{"snssai", func(r *models.ChargingDataRequest) {
+			nspaContainer(r).LoadLevel.Snssai = &models.Snssai{Sst: 1, Sd: "01"}
+		}},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := nspaRequest()
+			tc.modify(&r)
+			_, err := NspaChargingRecordToCdr("chf", r)
+			require.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,303 @@
+package cdrConvert
+
+import (
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/free5gc/CDRUtil/asn"
+	"github.com/free5gc/CDRUtil/cdrType"
+	"github.com/free5gc/openapi/models"
+)
+
+var triggerCategoryToCdr = map[models.TriggerCategory]asn.Enumerated{
+	"IMMEDIATE_REPORT": cdrType.TriggerCategoryPresentImmediateReport,
+	"DEFERRED_REPORT":  cdrType.TriggerCategoryPresentDeferredReport,
+}
+
+var partialRecordMethodToCdr = map[models.PartialRecordMethod]asn.Enumerated{
+	"DEFAULT":    cdrType.PartialRecordMethodPresentDefault,
+	"INDIVIDUAL": cdrType.PartialRecordMethodPresentIndividual,
+}
+
+var psDataOffStatusToCdr = map[models.Model3GpppsDataOffStatus]asn.Enumerated{
+	"ACTIVE":   cdrType.ThreeGPPPSDataOffStatusPresentActive,
+	"INACTIVE": cdrType.ThreeGPPPSDataOffStatusPresentInactive,
+}
+
+func RoamingTriggerToCdr(trigger models.Trigger) (cdrType.RoamingTrigger, error) {
+	var cdrTrigger cdrType.RoamingTrigger
+
+	if trigger.TriggerType != "" {
+		smfTrigger, err := SmfTriggerToCdr(trigger.TriggerType)
+		if err != nil {
+			return cdrTrigger, err
+		}
+		cdrTrigger.Trigger = &smfTrigger
+	}
+	if trigger.TriggerCategory != "" {
+		value, ok := triggerCategoryToCdr[trigger.TriggerCategory]
+		if !ok {
+			return cdrTrigger, fmt.Errorf("unsupported triggerCategory %q", trigger.TriggerCategory)
+		}
+		cdrTrigger.TriggerCategory = &cdrType.TriggerCategory{Value: value}
+	}
+	if trigger.TimeLimit != 0 {
+		cdrTrigger.TimeLimit = &cdrType.CallDuration{Value: int64(trigger.TimeLimit)}
+	}
+	// volumeLimit64 supersedes volumeLimit
+	switch {
+	case trigger.VolumeLimit64 != 0:
+		cdrTrigger.VolumeLimit = &cdrType.DataVolumeOctets{Value: int64(trigger.VolumeLimit64)}
+	case trigger.VolumeLimit != 0:
+		cdrTrigger.VolumeLimit = &cdrType.DataVolumeOctets{Value: int64(trigger.VolumeLimit)}
+	}
+	if trigger.MaxNumberOfccc != 0 {
+		maxNbChargingConditions := int64(trigger.MaxNumberOfccc)
+		cdrTrigger.MaxNbChargingConditions = &maxNbChargingConditions
+	}
+	return cdrTrigger, nil
+}
+
+func RoamingChargingProfileToCdr(profile models.RoamingChargingProfile) (cdrType.RoamingChargingProfile, error) {
+	var cdrProfile cdrType.RoamingChargingProfile
+
+	for _, trigger := range profile.Triggers {
+		cdrTrigger, err := RoamingTriggerToCdr(trigger)
+		if err != nil {
+			return cdrProfile, err
+		}
+		cdrProfile.RoamingTriggers = append(cdrProfile.RoamingTriggers, cdrTrigger)
+	}
+	if profile.PartialRecordMethod != "" {
+		value, ok := partialRecordMethodToCdr[profile.PartialRecordMethod]
+		if !ok {
+			return cdrProfile, fmt.Errorf("unsupported partialRecordMethod %q", profile.PartialRecordMethod)
+		}
+		cdrProfile.PartialRecordMethod = &cdrType.PartialRecordMethod{Value: value}
+	}
+	return cdrProfile, nil
+}
+
+func ServingNetworkFunctionIdToCdr(servingNf models.ServingNetworkFunctionId) (
+	cdrType.ServingNetworkFunctionID, error) {
+	var cdrServingNf cdrType.ServingNetworkFunctionID
+
+	if servingNf.ServingNetworkFunctionInformation == nil {
+		return cdrServingNf, fmt.Errorf("missing servingNetworkFunctionInformation")
+	}
+	nfInfo, err := NfIdentificationToCdr(*servingNf.ServingNetworkFunctionInformation)
+	if err != nil {
+		return cdrServingNf, err
+	}
+	cdrServingNf.ServingNetworkFunctionInformation = nfInfo
+
+	if servingNf.AMFId != "" {
+		// AMF Region ID, AMF Set ID and AMF Pointer, 6 hexadecimal digits
+		amfId, err := hex.DecodeString(servingNf.AMFId)
+		if err != nil || len(amfId) != 3 {
+			return cdrServingNf, fmt.Errorf("invalid aMFId %q", servingNf.AMFId)
+		}
+		cdrServingNf.AMFIdentifier = &cdrType.AMFID{Value: amfId}
+	}
+	return cdrServingNf, nil
+}
+
+// MultipleQfiContainerToCdr converts the usage of a QoS flow. The QoS
+// information and characteristics of the flow are not converted.
+func MultipleQfiContainerToCdr(container models.MultipleQfIcontainer) (cdrType.MultipleQFIContainer, error) {
+	var cdrContainer cdrType.MultipleQFIContainer
+
+	qfiInformation := container.QFIContainerInformation
+	if qfiInformation == nil || qfiInformation.ReportTime == nil {
+		return cdrContainer, fmt.Errorf("missing qFIContainerInformation reportTime")
+	}
+	cdrContainer.ReportTime = TimeStampToCdr(qfiInformation.ReportTime)
+
+	if triggers := TriggersToCdr(container.Triggers); len(triggers) > 0 {
+		cdrContainer.Triggers = triggers
+	}
+	if container.TriggerTimestamp != nil {
+		triggerTimeStamp := TimeStampToCdr(container.TriggerTimestamp)
+		cdrContainer.TriggerTimeStamp = &triggerTimeStamp
+	}
+	if container.Time != 0 {
+		cdrContainer.Time = &cdrType.CallDuration{Value: int64(container.Time)}
+	}
+	cdrContainer.DataTotalVolume = &cdrType.DataVolumeOctets{Value: int64(container.TotalVolume)}
+	cdrContainer.DataVolumeUplink = &cdrType.DataVolumeOctets{Value: int64(container.UplinkVolume)}
+	cdrContainer.DataVolumeDownlink = &cdrType.DataVolumeOctets{Value: int64(container.DownlinkVolume)}
+	cdrContainer.LocalSequenceNumber = &cdrType.LocalSequenceNumber{Value: int64(container.LocalSequenceNumber)}
+
+	cdrContainer.QosFlowId = &cdrType.QoSFlowId{Value: int64(qfiInformation.QFI)}
+	if qfiInformation.TimeofFirstUsage != nil {
+		timeOfFirstUsage := TimeStampToCdr(qfiInformation.TimeofFirstUsage)
+		cdrContainer.TimeOfFirstUsage = &timeOfFirstUsage
+	}
+	if qfiInformation.TimeofLastUsage != nil {
+		timeOfLastUsage := TimeStampToCdr(qfiInformation.TimeofLastUsage)
+		cdrContainer.TimeOfLastUsage = &timeOfLastUsage
+	}
+
+	info, err := amfUserInformationToCdr(nil, qfiInformation.UserLocationInformation,
+		qfiInformation.UetimeZone, qfiInformation.RATType)
+	if err != nil {
+		return cdrContainer, err
+	}
+	cdrContainer.UserLocationInformation = info.userLocationInformation
+	cdrContainer.UserLocationInformationASN1 = info.userLocationASN1
+	cdrContainer.UETimeZone = info.ueTimeZone
+	cdrContainer.RATType = info.ratType
+
+	switch len(qfiInformation.PresenceReportingAreaInformation) {
+	case 0:
+	case 1:
+		for _, presenceInfo := range qfiInformation.PresenceReportingAreaInformation {
+			cdrPresenceInfo, err := PresenceInfoToCdr(presenceInfo)
+			if err != nil {
+				return cdrContainer, err
+			}
+			cdrContainer.PresenceReportingAreaInfo = &cdrPresenceInfo
+		}
+	default:
+		return cdrContainer, fmt.Errorf("%d presenceReportingAreaInformation for a single container",
+			len(qfiInformation.PresenceReportingAreaInformation))
+	}
+	for _, servingNf := range qfiInformation.ServingNetworkFunctionID {
+		cdrServingNf, err := ServingNetworkFunctionIdToCdr(servingNf)
+		if err != nil {
+			return cdrContainer, err
+		}
+		cdrContainer.ServingNetworkFunctionID = append(cdrContainer.ServingNetworkFunctionID, cdrServingNf)
+	}
+	if qfiInformation.Var3gppPSDataOffStatus != "" {
+		value, ok := psDataOffStatusToCdr[qfiInformation.Var3gppPSDataOffStatus]
+		if !ok {
+			return cdrContainer, fmt.Errorf("unsupported 3gppPSDataOffStatus %q", qfiInformation.Var3gppPSDataOffStatus)
+		}
+		cdrContainer.ThreeGPPPSDataOffStatus = &cdrType.ThreeGPPPSDataOffStatus{Value: value}
+	}
+	if qfiInformation.Var3gppChargingId != 0 {
+		cdrContainer.ThreeGPPChargingID = &cdrType.ChargingID{Value: int64(qfiInformation.Var3gppChargingId)}
+	}
+	if qfiInformation.Diagnostics != 0 {
+		// the 5GSM cause of the release
+		diagnostics := cdrType.NewDiagnosticsGsm0408Cause(int64(qfiInformation.Diagnostics))
+		cdrContainer.Diagnostics = &diagnostics
+	}
+
+	return cdrContainer, nil
+}
+
+func RoamingQbcInformationToCdr(roamingQbc models.RoamingQbcInformation) (cdrType.RoamingQBCInformation, error) {
+	var cdrRoamingQbc cdrType.RoamingQBCInformation
+
+	for _, container := range roamingQbc.MultipleQFIcontainer {
+		cdrContainer, err := MultipleQfiContainerToCdr(container)
+		if err != nil {
+			return cdrRoamingQbc, err
+		}
+		cdrRoamingQbc.MultipleQFIcontainer = append(cdrRoamingQbc.MultipleQFIcontainer, cdrContainer)
+	}
+	if roamingQbc.UPFID != "" {
+		cdrRoamingQbc.UPFID = &cdrType.NetworkFunctionName{Value: asn.IA5String(roamingQbc.UPFID)}
+	}
+	if roamingQbc.RoamingChargingProfile != nil {
+		profile, err := RoamingChargingProfileToCdr(*roamingQbc.RoamingChargingProfile)
+		if err != nil {
+			return cdrRoamingQbc, err
+		}
+		cdrRoamingQbc.RoamingChargingProfile = &profile
+	}
+	return cdrRoamingQbc, nil
+}
+
+// RoamingQbcChargingRecordsToCdr builds the records of the QoS based
+// charging data reported by a V-SMF or H-SMF of a home routed session, see
+// OneTimeEventRecordToCdr. With the individual partial record method of the
+// roaming charging profile, each QoS flow gets its own partial record of the
+// session, in the order the flows are first reported: the records are
+// numbered from 1 on, each one lasting from the first usage of its flow to
+// the last report, and all but the last one are closed as partial records.
+// Otherwise all flows share a single record.
+func RoamingQbcChargingRecordsToCdr(chfName string, request models.ChargingDataRequest) (
+	[]cdrType.ChargingRecord, error) {
+	if request.RoamingQBCInformation == nil {
+		return nil, fmt.Errorf("missing roamingQBCInformation")
+	}
+	record, err := OneTimeEventRecordToCdr(chfName, request, "SMF")
+	if err != nil {
+		return nil, err
+	}
+
+	roamingQbc, err := RoamingQbcInformationToCdr(*request.RoamingQBCInformation)
+	if err != nil {
+		return nil, err
+	}
+	if profile := roamingQbc.RoamingChargingProfile; profile == nil || profile.PartialRecordMethod == nil ||
+		profile.PartialRecordMethod.Value != cdrType.PartialRecordMethodPresentIndividual ||
+		len(roamingQbc.MultipleQFIcontainer) == 0 {
+		record.RoamingQBCInformation = &roamingQbc
+		return []cdrType.ChargingRecord{record}, nil
+	}
+
+	// the containers are valid, having been converted once
+	var qfis []int32
+	containers := make(map[int32][]models.MultipleQfIcontainer)
+	for _, container := range request.RoamingQBCInformation.MultipleQFIcontainer {
+		qfi := container.QFIContainerInformation.QFI
+		if _, ok := containers[qfi]; !ok {
+			qfis = append(qfis, qfi)
+		}
+		containers[qfi] = append(containers[qfi], container)
+	}
+
+	// each record is converted on its own so that the records share nothing
+	records := make([]cdrType.ChargingRecord, 0, len(qfis))
+	for i, qfi := range qfis {
+		qfiRecord, err := OneTimeEventRecordToCdr(chfName, request, "SMF")
+		if err != nil {
+			return nil, err
+		}
+		qfiInformation := *request.RoamingQBCInformation
+		qfiInformation.MultipleQFIcontainer = containers[qfi]
+		qfiRoamingQbc, err := RoamingQbcInformationToCdr(qfiInformation)
+		if err != nil {
+			return nil, err
+		}
+		qfiRecord.RoamingQBCInformation = &qfiRoamingQbc
+
+		openingTime, closingTime := qfiUsagePeriod(containers[qfi])
+		qfiRecord.RecordOpeningTime = TimeStampToCdr(&openingTime)
+		qfiRecord.Duration = cdrType.CallDuration{
+			Value: int64(closingTime.Sub(openingTime).Round(time.Second) / time.Second),
+		}
+		sequenceNumber := int64(i + 1)
+		qfiRecord.RecordSequenceNumber = &sequenceNumber
+		if i < len(qfis)-1 {
+			qfiRecord.CauseForRecClosing = cdrType.CauseForRecClosing{Value: cdrType.CauseForRecClosingPresentPartialRecord}
+		}
+		records = append(records, qfiRecord)
+	}
+	return records, nil
+}
+
+// qfiUsagePeriod returns the period from the first usage, or else the first
+// report, of a QoS flow to its last report.
+func qfiUsagePeriod(containers []models.MultipleQfIcontainer) (time.Time, time.Time) {
+	var openingTime, closingTime time.Time
+	for i, container := range containers {
+		qfiInformation := container.QFIContainerInformation
+		firstTime := *qfiInformation.ReportTime
+		if qfiInformation.TimeofFirstUsage != nil && qfiInformation.TimeofFirstUsage.Before(firstTime) {
+			firstTime = *qfiInformation.TimeofFirstUsage
+		}
+		if i == 0 || firstTime.Before(openingTime) {
+			openingTime = firstTime
+		}
+		if i == 0 || qfiInformation.ReportTime.After(closingTime) {
+			closingTime = *qfiInformation.ReportTime
+		}
+	}
+	return openingTime, closingTime
+}
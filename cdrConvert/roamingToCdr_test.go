@@ -0,0 +1,150 @@
+package cdrConvert
+
+import (
+	"testing"
+	"time"
+
+	"github.com/free5gc/CDRUtil/cdrType"
+	"github.com/free5gc/openapi/models"
+	"github.com/stretchr/testify/require"
+)
+
+func qfiContainer(qfi int32, firstUsage, reportTime time.Time, volume int32) models.MultipleQfIcontainer {
+	return models.MultipleQfIcontainer{
+		Triggers:       []models.Trigger{{TriggerType: "VOLUME_LIMIT", TriggerCategory: "IMMEDIATE_REPORT"}},
+		TotalVolume:    volume,
+		UplinkVolume:   volume / 2,
+		DownlinkVolume: volume / 2,
+		QFIContainerInformation: &models.QfiContainerInformation{
+			QFI:              qfi,
+			ReportTime:       &reportTime,
+			TimeofFirstUsage: &firstUsage,
+			UetimeZone:       "+08:00",
+			RATType:          "EUTRA",
+		},
+	}
+}
+
+func roamingRequest(partialRecordMethod models.PartialRecordMethod) models.ChargingDataRequest {
+	invocationTime := time.Date(2022, time.May, 4, 10, 10, 0, 0, time.UTC)
+	start := time.Date(2022, time.May, 4, 10, 0, 0, 0, time.UTC)
+	return models.ChargingDataRequest{
+		InvocationTimeStamp: &invocationTime,
+		NfConsumerIdentification: &models.NfIdentification{
+			NFName:   "fcd3ee5e-8ec8-4a5a-8da8-c64dc6bd25ab",
+			NFPLMNID: &models.PlmnId{Mcc: "208", Mnc: "93"},
+		},
+		SubscriberIdentifier: "imsi-208930000000003",
+		RoamingQBCInformation: &models.RoamingQbcInformation{
+			MultipleQFIcontainer: []models.MultipleQfIcontainer{
+				qfiContainer(5, start, start.Add(4*time.Minute), 1000),
+				qfiContainer(9, start.Add(time.Minute), start.Add(5*time.Minute), 2000),
+				qfiContainer(5, start.Add(4*time.Minute), start.Add(8*time.Minute), 3000),
+			},
+			UPFID: "upf.operator.com",
+			RoamingChargingProfile: &models.RoamingChargingProfile{
+				Triggers:            []models.Trigger{{TriggerType: "TIME_LIMIT", TimeLimit: 600}},
+				PartialRecordMethod: partialRecordMethod,
+			},
+		},
+	}
+}
+
+func TestRoamingQbcChargingRecordsToCdr(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2022, time.May, 4, 10, 0, 0, 0, time.UTC)
+
+	records, err := RoamingQbcChargingRecordsToCdr("chf", roamingRequest("DEFAULT"))
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	require.Len(t, records[0].RoamingQBCInformation.MultipleQFIcontainer, 3)
+	require.Nil(t, records[0].RecordSequenceNumber)
+
+	records, err = RoamingQbcChargingRecordsToCdr("chf", roamingRequest("INDIVIDUAL"))
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+
+	testCases := []struct {
+		qfi         int64
+		containers  int
+		openingTime time.Time
+		duration    int64
+		cause       int64
+	}{
+		{5, 2, start, 8 * 60, cdrType.CauseForRecClosingPresentPartialRecord},
+		{9, 1, start.Add(time.Minute), 4 * 60, cdrType.CauseForRecClosingPresentNormalRelease},
+	}
+	for i, tc := range testCases {
+		record := records[i]
+		containers := record.RoamingQBCInformation.MultipleQFIcontainer
+		require.Len(t, containers, tc.containers)
+		for _, container := range containers {
+			require.Equal(t, tc.qfi, container.QosFlowId.Value)
+		}
+		require.Equal(t, cdrType.NewTimeStamp(tc.openingTime), record.RecordOpeningTime)
+		require.Equal(t, tc.duration, record.Duration.Value)
+		require.Equal(t, int64(i+1), *record.RecordSequenceNumber)
+		require.Equal(t, tc.cause, record.CauseForRecClosing.Value)
+		require.Equal(t, "upf.operator.com", string(record.RoamingQBCInformation.UPFID.Value))
+		require.Len(t, record.RoamingQBCInformation.RoamingChargingProfile.RoamingTriggers, 1)
+	}
+
+	// the records share nothing
+	records[0].SubscriberIdentifier.SubscriptionIDData = "208930000000004"
+	records[0].NFunctionConsumerInformation.NetworkFunctionName.Value = "smf"
+	records[0].RoamingQBCInformation.UPFID.Value = "upf"
+	records[0].RoamingQBCInformation.RoamingChargingProfile.RoamingTriggers[0].TimeLimit.Value = 60
+	records[0].RoamingQBCInformation.MultipleQFIcontainer[0].DataTotalVolume.Value = 0
+	require.Equal(t, "208930000000003", string(records[1].SubscriberIdentifier.SubscriptionIDData))
+	require.Equal(t, "fcd3ee5e-8ec8-4a5a-8da8-c64dc6bd25ab",
+		string(records[1].NFunctionConsumerInformation.NetworkFunctionName.Value))
+	require.Equal(t, "upf.operator.com", string(records[1].RoamingQBCInformation.UPFID.Value))
+	require.Equal(t, int64(600), records[1].RoamingQBCInformation.RoamingChargingProfile.RoamingTriggers[0].TimeLimit.Value)
+	require.Equal(t, int64(2000), records[1].RoamingQBCInformation.MultipleQFIcontainer[0].DataTotalVolume.Value)
+}
+
+func TestRoamingQbcChargingRecordsToCdrInvalid(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name   string
+		modify func(r *models.ChargingDataRequest)
+	}{
+		{"roamingQBCInformation", func(r *models.ChargingDataRequest) { r.RoamingQBCInformation = nil }},
+		{"invocationTimeStamp", func(r *models.ChargingDataRequest) { r.InvocationTimeStamp = nil }},
+		{"reportTime", func(r *models.ChargingDataRequest) {
+			r.RoamingQBCInformation.MultipleQFIcontainer[1].QFIContainerInformation.ReportTime = nil
+		}},
+		{"qFIContainerInformation", func(r *models.ChargingDataRequest) {
+			r.RoamingQBCInformation.MultipleQFIcontainer[2].QFIContainerInformation = nil
+		}},
+		{"uetimeZone", func(r *models.ChargingDataRequest) {
+			r.RoamingQBCInformation.MultipleQFIcontainer[0].QFIContainerInformation.UetimeZone = "+25:00"
+		}},
+		{"3gppPSDataOffStatus", func(r *models.ChargingDataRequest) {
+			r.RoamingQBCInformation.MultipleQFIcontainer[0].QFIContainerInformation.Var3gppPSDataOffStatus = "ON"
+		}},
+		{"partialRecordMethod", func(r *models.ChargingDataRequest) {
+			r.RoamingQBCInformation.RoamingChargingProfile.PartialRecordMethod = "PER_FLOW"
+		}},
+		{"triggerCategory", func(r *models.ChargingDataRequest) {
+			r.RoamingQBCInformation.RoamingChargingProfile.Triggers[0].TriggerCategory = "LATER"
+		}},
+		{"triggerType", func(r *models.ChargingDataRequest) {
+			r.RoamingQBCInformation.RoamingChargingProfile.Triggers[0].TriggerType = "UNKNOWN"
+		}},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := roamingRequest("INDIVIDUAL")
+			tc.modify(&r)
+			_, err := RoamingQbcChargingRecordsToCdr("chf", r)
+			require.Error(t, err)
+		})
+	}
+}
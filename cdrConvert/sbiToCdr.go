@@ -2,6 +2,11 @@ package cdrConvert
 
 import (
 	"encoding/hex"
+	"fmt"
+	"net"
+	"net/netip"
+	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
@@ -10,26 +15,75 @@ import (
 	"github.com/free5gc/openapi/models"
 )
 
-func MultiUnitUsageToCdr(multiUnitUsageList []models.MultipleUnitUsage) []cdrType.MultipleUnitUsage {
+func MultiUnitUsageToCdr(multiUnitUsageList []models.MultipleUnitUsage) ([]cdrType.MultipleUnitUsage, error) {
 	cdrMultiUnitUsageList := make([]cdrType.MultipleUnitUsage, 0, len(multiUnitUsageList))
 
 	for _, multiUnitUsage := range multiUnitUsageList {
 		usedUnitContainer := UsedUnitContainerToCdr(multiUnitUsage.UsedUnitContainer)
+
+		var cdrPduAddress *cdrType.PDUAddress
+		if multiUnitUsage.MultihomedPDUAddress != nil {
+			pduAddress, err := PduAddressToCdr(*multiUnitUsage.MultihomedPDUAddress)
+			if err != nil {
+				return nil, err
+			}
+			cdrPduAddress = &pduAddress
+		}
+
+		var cdrUpfId *cdrType.NetworkFunctionName
+		if multiUnitUsage.UPFID != "" {
+			cdrUpfId = &cdrType.NetworkFunctionName{
+				asn.IA5String(multiUnitUsage.UPFID),
+			}
+		}
+
 		cdrMultiUnitUsage := cdrType.MultipleUnitUsage{
 			cdrType.RatingGroupId{
 				int64(multiUnitUsage.RatingGroup),
 			},
 			usedUnitContainer,
-			&cdrType.NetworkFunctionName{
-				asn.IA5String(multiUnitUsage.UPFID),
-			},
-			// TODO convert PDUAddress, not exist in current spec
-			nil,
+			cdrUpfId,
+			cdrPduAddress,
 		}
 		cdrMultiUnitUsageList = append(cdrMultiUnitUsageList, cdrMultiUnitUsage)
 	}
 
-	return cdrMultiUnitUsageList
+	return cdrMultiUnitUsageList, nil
+}
+
+func PduAddressToCdr(pduAddress models.PduAddress) (cdrType.PDUAddress, error) {
+	var ipv4 netip.Addr
+	if pduAddress.PduIPv4Address != "" {
+		addr, err := netip.ParseAddr(pduAddress.PduIPv4Address)
+		if err != nil {
+			return cdrType.PDUAddress{}, fmt.Errorf("invalid pduIPv4Address: %v", err)
+		}
+		ipv4 = addr
+	}
+
+	var ipv6Prefixes []netip.Prefix
+	if pduAddress.PduIPv6AddresswithPrefix != "" {
+		addr, err := netip.ParseAddr(pduAddress.PduIPv6AddresswithPrefix)
+		if err != nil {
+			return cdrType.PDUAddress{}, fmt.Errorf("invalid pduIPv6AddresswithPrefix: %v", err)
+		}
+		prefixLength := cdrType.DefaultIPv6PrefixLength
+		if pduAddress.PduAddressprefixlength != 0 {
+			prefixLength = int(pduAddress.PduAddressprefixlength)
+		}
+		ipv6Prefixes = append(ipv6Prefixes, netip.PrefixFrom(addr, prefixLength))
+	}
+
+	if pduAddress.AddIpv6AddrPrefixes != "" {
+		prefix, err := netip.ParsePrefix(pduAddress.AddIpv6AddrPrefixes)
+		if err != nil {
+			return cdrType.PDUAddress{}, fmt.Errorf("invalid addIpv6AddrPrefixes: %v", err)
+		}
+		ipv6Prefixes = append(ipv6Prefixes, prefix)
+	}
+
+	return cdrType.NewPDUAddress(ipv4, pduAddress.IPv4dynamicAddressFlag,
+		ipv6Prefixes, pduAddress.IPv6dynamicPrefixFlag)
 }
 
 // TODO: Only convert Local Sequence Number, Uplink, Downlink, Total Volumn,
@@ -60,52 +114,635 @@ func UsedUnitContainerToCdr(usedUnitContainerList []models.UsedUnitContainer) []
 	return cdrUsedUnitContainerList
 }
 
-// TODO convert type Trigger
+var triggerTypeToCdr = map[models.TriggerType]int64{
+	"QUOTA_THRESHOLD":             cdrType.SMFTriggerPresentQuotaThreshold,
+	"QHT":                         cdrType.SMFTriggerPresentQuotaHoldingTime,
+	"FINAL":                       cdrType.SMFTriggerPresentFinal,
+	"QUOTA_EXHAUSTED":             cdrType.SMFTriggerPresentQuotaExhausted,
+	"VALIDITY_TIME":               cdrType.SMFTriggerPresentValidityTime,
+	"OTHER_QUOTA_TYPE":            cdrType.SMFTriggerPresentOtherQuotaType,
+	"FORCED_REAUTHORISATION":      cdrType.SMFTriggerPresentForcedReauthorisation,
+	"UNUSED_QUOTA_TIMER":          cdrType.SMFTriggerPresentUnusedQuotaTimer,
+	"UNIT_COUNT_INACTIVITY_TIMER": cdrType.SMFTriggerPresentUnitCountInactivityTimer,
+	"ABNORMAL_RELEASE":            cdrType.SMFTriggerPresentAbnormalRelease,
+	"QOS_CHANGE":                  cdrType.SMFTriggerPresentQoSChange,
+	"VOLUME_LIMIT":                cdrType.SMFTriggerPresentVolumeLimit,
+	"TIME_LIMIT":                  cdrType.SMFTriggerPresentTimeLimit,
+	"EVENT_LIMIT":                 cdrType.SMFTriggerPresentEventLimit,
+	"PLMN_CHANGE":                 cdrType.SMFTriggerPresentPLMNChange,
+	"USER_LOCATION_CHANGE":        cdrType.SMFTriggerPresentUserLocationChange,
+	"RAT_CHANGE":                  cdrType.SMFTriggerPresentRATChange,
+	"SESSION_AMBR_CHANGE":         cdrType.SMFTriggerPresentSessionAMBRChange,
+	"UE_TIMEZONE_CHANGE":          cdrType.SMFTriggerPresentUETimeZoneChange,
+	"TARIFF_TIME_CHANGE":          cdrType.SMFTriggerPresentTariffTimeChange,
+	"MAX_NUMBER_OF_CHANGES_IN_CHARGING_CONDITIONS":     cdrType.SMFTriggerPresentMaxNumberOfChangesInChargingConditions,
+	"MANAGEMENT_INTERVENTION":                          cdrType.SMFTriggerPresentManagementIntervention,
+	"CHANGE_OF_UE_PRESENCE_IN_PRESENCE_REPORTING_AREA": cdrType.SMFTriggerPresentChangeOfUEPresenceInPresenceReportingArea,
+	"CHANGE_OF_3GPP_PS_DATA_OFF_STATUS":                cdrType.SMFTriggerPresentChangeOf3GPPPSDataOffStatus,
+	"SERVING_NODE_CHANGE":                              cdrType.SMFTriggerPresentServingNodeChange,
+	"REMOVAL_OF_UPF":                                   cdrType.SMFTriggerPresentRemovalOfUPF,
+	"ADDITION_OF_UPF":                                  cdrType.SMFTriggerPresentAdditionOfUPF,
+	"INSERTION_OF_ISMF":                                cdrType.SMFTriggerPresentInsertionOfISMF,
+	"REMOVAL_OF_ISMF":                                  cdrType.SMFTriggerPresentRemovalOfISMF,
+	"CHANGE_OF_ISMF":                                   cdrType.SMFTriggerPresentChangeOfISMF,
+	"START_OF_SERVICE_DATA_FLOW":                       cdrType.SMFTriggerPresentStartOfServiceDataFlow,
+	"ECGI_CHANGE":                                      cdrType.SMFTriggerPresentECGIChange,
+	"TAI_CHANGE":                                       cdrType.SMFTriggerPresentTAIChange,
+	"HANDOVER_CANCEL":                                  cdrType.SMFTriggerPresentHandoverCancel,
+	"HANDOVER_START":                                   cdrType.SMFTriggerPresentHandoverStart,
+	"HANDOVER_COMPLETE":                                cdrType.SMFTriggerPresentHandoverComplete,
+	"GFBR_GUARANTEED_STATUS_CHANGE":                    cdrType.SMFTriggerPresentGFBRGuaranteedStatusChange,
+	"ADDITION_OF_ACCESS":                               cdrType.SMFTriggerPresentAdditionOfAccess,
+	"REMOVAL_OF_ACCESS":                                cdrType.SMFTriggerPresentRemovalOfAccess,
+	"START_OF_SDF_ADDITIONAL_ACCESS":                   cdrType.SMFTriggerPresentStartOfSDFAdditionalAccess,
+	"REDUNDANT_TRANSMISSION_CHANGE":                    cdrType.SMFTriggerPresentRedundantTransmissionChange,
+}
+
+func SmfTriggerToCdr(triggerType models.TriggerType) (cdrType.SMFTrigger, error) {
+	value, ok := triggerTypeToCdr[triggerType]
+	if !ok {
+		return cdrType.SMFTrigger{}, fmt.Errorf("unsupported triggerType %q", triggerType)
+	}
+	return cdrType.SMFTrigger{Value: value}, nil
+}
+
+// TriggersToCdr keeps the type of the triggers, the trigger types unknown to
+// the SMF are left out.
 func TriggersToCdr(triggers []models.Trigger) []cdrType.Trigger {
 	cdrTriggers := make([]cdrType.Trigger, 0, len(triggers))
+	for _, trigger := range triggers {
+		smfTrigger, err := SmfTriggerToCdr(trigger.TriggerType)
+		if err != nil {
+			continue
+		}
+		cdrTriggers = append(cdrTriggers, cdrType.NewTriggerSMFTrigger(smfTrigger))
+	}
 
 	return cdrTriggers
 }
 
-// format: YYMMDDhhmmssShhmm
-// BCD encoded
+// TimeStampToCdr encodes t, see cdrType.NewTimeStamp.
 func TimeStampToCdr(t *time.Time) cdrType.TimeStamp {
-	ts := make(asn.OctetString, 9)
+	return cdrType.NewTimeStamp(*t)
+}
+
+func PlmnIdToCdr(modelsPlmnid models.PlmnId) (cdrType.PLMNId, error) {
+	return cdrType.NewPLMNId(modelsPlmnid.Mcc, modelsPlmnid.Mnc)
+}
+
+func CdrToPlmnId(cdrPlmnId cdrType.PLMNId) (models.PlmnId, error) {
+	mcc, mnc, err := cdrPlmnId.Decode()
+	if err != nil {
+		return models.PlmnId{}, err
+	}
+	return models.PlmnId{
+		Mcc: mcc,
+		Mnc: mnc,
+	}, nil
+}
+
+var ueTimeZoneRegexp = regexp.MustCompile(`^([+-])(\d{2}):([0-5]\d)(?:\+([12]))?$`)
+
+// UeTimeZoneToCdr converts the TimeZone of TS 29.571, e.g. "-08:00+1", where
+// the offset includes the daylight saving adjustment given by the suffix.
+func UeTimeZoneToCdr(ueTimeZone string) (cdrType.MSTimeZone, error) {
+	match := ueTimeZoneRegexp.FindStringSubmatch(ueTimeZone)
+	if match == nil {
+		return cdrType.MSTimeZone{}, fmt.Errorf("invalid ueTimeZone %q", ueTimeZone)
+	}
+
+	hour, _ := strconv.Atoi(match[2])
+	minute, _ := strconv.Atoi(match[3])
+	dst := cdrType.DaylightSavingNoAdjustment
+	if match[4] != "" {
+		dst, _ = strconv.Atoi(match[4])
+	}
+
+	offset := hour*3600 + minute*60
+	if match[1] == "-" {
+		offset = -offset
+	}
+	return cdrType.NewMSTimeZone(offset, dst)
+}
+
+func CdrToUeTimeZone(cdrTimeZone cdrType.MSTimeZone) (string, error) {
+	offset, dst, err := cdrTimeZone.Decode()
+	if err != nil {
+		return "", err
+	}
+
+	sign := '+'
+	if offset < 0 {
+		sign = '-'
+		offset = -offset
+	}
+	ueTimeZone := fmt.Sprintf("%c%02d:%02d", sign, offset/3600, offset%3600/60)
+	if dst != cdrType.DaylightSavingNoAdjustment {
+		ueTimeZone += fmt.Sprintf("+%d", dst)
+	}
+	return ueTimeZone, nil
+}
 
-	_, tz := t.Zone()
-	ts[0] = (byte(t.Year()%100/10) << 4) | (byte(t.Year() % 10))
-	ts[1] = (byte(t.Month()/10) << 4) | (byte(t.Month() % 10))
-	ts[2] = (byte(t.Day()/10) << 4) | (byte(t.Day() % 10))
-	ts[3] = (byte(t.Hour()/10) << 4) | (byte(t.Hour() % 10))
-	ts[4] = (byte(t.Minute()/10) << 4) | (byte(t.Minute() % 10))
-	ts[5] = (byte(t.Second()/10) << 4) | (byte(t.Second() % 10))
-	if tz >= 0 {
-		ts[6] = byte('+')
-	} else {
-		ts[6] = byte('-')
+// TS 29.571 RatType values that have a RAT Type of TS 29.274
+var ratTypeToCdr = map[models.RatType]int64{
+	"UTRA":    cdrType.RATTypePresentUTRAN,
+	"GERA":    cdrType.RATTypePresentGERAN,
+	"WLAN":    cdrType.RATTypePresentWLAN,
+	"EUTRA":   cdrType.RATTypePresentEUTRAN,
+	"VIRTUAL": cdrType.RATTypePresentVirtual,
+	"NBIOT":   cdrType.RATTypePresentEUTRANNBIoT,
+	"LTE-M":   cdrType.RATTypePresentLTEM,
+	"NR":      cdrType.RATTypePresentNR,
+}
+
+func RatTypeToCdr(ratType models.RatType) (cdrType.RATType, error) {
+	value, ok := ratTypeToCdr[ratType]
+	if !ok {
+		return cdrType.RATType{}, fmt.Errorf("unsupported ratType %q", ratType)
+	}
+	return cdrType.RATType{Value: value}, nil
+}
+
+var pduSessionTypeToCdr = map[models.PduSessionType]asn.Enumerated{
+	"IPV4V6":       cdrType.PDUSessionTypePresentIPv4v6,
+	"IPV4":         cdrType.PDUSessionTypePresentIPv4,
+	"IPV6":         cdrType.PDUSessionTypePresentIPv6,
+	"UNSTRUCTURED": cdrType.PDUSessionTypePresentUnstructured,
+	"ETHERNET":     cdrType.PDUSessionTypePresentEthernet,
+}
+
+func PduSessionTypeToCdr(pduSessionType models.PduSessionType) (cdrType.PDUSessionType, error) {
+	value, ok := pduSessionTypeToCdr[pduSessionType]
+	if !ok {
+		return cdrType.PDUSessionType{}, fmt.Errorf("unsupported pduSessionType %q", pduSessionType)
+	}
+	return cdrType.PDUSessionType{Value: value}, nil
+}
+
+var sscModeToCdr = map[models.SscMode]int64{
+	"SSC_MODE_1": cdrType.SSCModePresentSSCMode1,
+	"SSC_MODE_2": cdrType.SSCModePresentSSCMode2,
+	"SSC_MODE_3": cdrType.SSCModePresentSSCMode3,
+}
+
+func SscModeToCdr(sscMode models.SscMode) (cdrType.SSCMode, error) {
+	value, ok := sscModeToCdr[sscMode]
+	if !ok {
+		return cdrType.SSCMode{}, fmt.Errorf("unsupported sscMode %q", sscMode)
+	}
+	return cdrType.SSCMode{Value: value}, nil
+}
+
+// TS 29.571 Tac: 4 hex digits for a 2 octet TAC, 6 for a 3 octet one
+func TaiToCdr(tai models.Tai) (cdrType.TAI, error) {
+	if tai.PlmnId == nil {
+		return cdrType.TAI{}, fmt.Errorf("tai without plmnId")
+	}
+	plmnId, err := PlmnIdToCdr(*tai.PlmnId)
+	if err != nil {
+		return cdrType.TAI{}, err
 	}
-	ts[7] = (byte(tz/3600/10) << 4) | (byte(tz / 3600 % 10))
-	ts[8] = (byte(tz%3600/10) << 4) | (byte(tz % 3600 % 10))
-	cdrTimeStamp := cdrType.TimeStamp{
-		Value: ts,
+	if len(tai.Tac) != 4 && len(tai.Tac) != 6 {
+		return cdrType.TAI{}, fmt.Errorf("invalid tac %q", tai.Tac)
 	}
+	tac, err := hex.DecodeString(tai.Tac)
+	if err != nil {
+		return cdrType.TAI{}, fmt.Errorf("invalid tac %q", tai.Tac)
+	}
+	return cdrType.TAI{
+		PLMNId: plmnId,
+		Tac:    cdrType.TAC{Value: tac},
+	}, nil
+}
 
-	return cdrTimeStamp
+func TaiListToCdr(taiList []models.Tai) ([]cdrType.TAI, error) {
+	cdrTaiList := make([]cdrType.TAI, 0, len(taiList))
+	for _, tai := range taiList {
+		cdrTai, err := TaiToCdr(tai)
+		if err != nil {
+			return nil, err
+		}
+		cdrTaiList = append(cdrTaiList, cdrTai)
+	}
+	return cdrTaiList, nil
 }
 
-func PlmnIdToCdr(modelsPlmnid models.PlmnId) cdrType.PLMNId {
-	var hexString string
-	mcc := strings.Split(modelsPlmnid.Mcc, "")
-	mnc := strings.Split(modelsPlmnid.Mnc, "")
-	if len(modelsPlmnid.Mnc) == 2 {
-		hexString = mcc[1] + mcc[0] + "f" + mcc[2] + mnc[1] + mnc[0]
-	} else {
-		hexString = mcc[1] + mcc[0] + mnc[0] + mcc[2] + mnc[2] + mnc[1]
+func EcgiToCdr(ecgi models.Ecgi) (cdrType.Ecgi, error) {
+	if ecgi.PlmnId == nil {
+		return cdrType.Ecgi{}, fmt.Errorf("ecgi without plmnId")
+	}
+	plmnId, err := PlmnIdToCdr(*ecgi.PlmnId)
+	if err != nil {
+		return cdrType.Ecgi{}, err
 	}
+	return cdrType.Ecgi{
+		PlmnId:      plmnId,
+		EutraCellId: cdrType.EutraCellId{Value: asn.UTF8String(ecgi.EutraCellId)},
+	}, nil
+}
 
-	var cdrPlmnId cdrType.PLMNId
-	if plmnId, err := hex.DecodeString(hexString); err == nil {
-		cdrPlmnId.Value = plmnId
+func NcgiToCdr(ncgi models.Ncgi) (cdrType.Ncgi, error) {
+	if ncgi.PlmnId == nil {
+		return cdrType.Ncgi{}, fmt.Errorf("ncgi without plmnId")
 	}
-	return cdrPlmnId
+	plmnId, err := PlmnIdToCdr(*ncgi.PlmnId)
+	if err != nil {
+		return cdrType.Ncgi{}, err
+	}
+	return cdrType.Ncgi{
+		PlmnId:   plmnId,
+		NrCellId: cdrType.NrCellId{Value: asn.UTF8String(ncgi.NrCellId)},
+	}, nil
+}
+
+func GlobalRanNodeIdToCdr(ranNodeId models.GlobalRanNodeId) (cdrType.GlobalRanNodeId, error) {
+	var cdrRanNodeId cdrType.GlobalRanNodeId
+
+	if ranNodeId.PlmnId != nil {
+		plmnId, err := PlmnIdToCdr(*ranNodeId.PlmnId)
+		if err != nil {
+			return cdrRanNodeId, err
+		}
+		cdrRanNodeId.PLMNId = &plmnId
+	}
+	switch {
+	case ranNodeId.GNbId != nil:
+		cdrRanNodeId.GNbId = &cdrType.GNbId{
+			BitLength: int64(ranNodeId.GNbId.BitLength),
+			GNbValue:  asn.IA5String(ranNodeId.GNbId.GNBValue),
+		}
+	case ranNodeId.NgeNbId != "":
+		cdrRanNodeId.NgeNbId = &cdrType.NgeNbId{Value: asn.IA5String(ranNodeId.NgeNbId)}
+	case ranNodeId.N3IwfId != "":
+		cdrRanNodeId.N3IwfId = &cdrType.N3IwFId{Value: asn.IA5String(ranNodeId.N3IwfId)}
+	default:
+		return cdrRanNodeId, fmt.Errorf("globalRanNodeId without node id")
+	}
+	return cdrRanNodeId, nil
+}
+
+// UserLocationToCdr returns the structured user location and, for E-UTRA and
+// NR locations, its octet string form.
+func UserLocationToCdr(userLocation models.UserLocation) (
+	*cdrType.UserLocationInformation, cdrType.UserLocationInformationStructured, error) {
+	var structured cdrType.UserLocationInformationStructured
+
+	switch {
+	case userLocation.EutraLocation != nil:
+		location := userLocation.EutraLocation
+		eutra := new(cdrType.EutraLocation)
+		if location.Tai != nil {
+			tai, err := TaiToCdr(*location.Tai)
+			if err != nil {
+				return nil, structured, err
+			}
+			eutra.Tai = &tai
+		}
+		if location.Ecgi != nil {
+			ecgi, err := EcgiToCdr(*location.Ecgi)
+			if err != nil {
+				return nil, structured, err
+			}
+			eutra.Ecgi = &ecgi
+		}
+		if location.GlobalNgenbId != nil {
+			ranNodeId, err := GlobalRanNodeIdToCdr(*location.GlobalNgenbId)
+			if err != nil {
+				return nil, structured, err
+			}
+			eutra.GlobalNgenbId = &ranNodeId
+		}
+		if location.AgeOfLocationInformation != 0 {
+			eutra.AgeOfLocationInformation = &cdrType.AgeOfLocationInformation{
+				Value: int64(location.AgeOfLocationInformation),
+			}
+		}
+		if location.UeLocationTimestamp != nil {
+			timeStamp := TimeStampToCdr(location.UeLocationTimestamp)
+			eutra.UeLocationTimestamp = &timeStamp
+		}
+		structured.EutraLocation = eutra
+	case userLocation.NrLocation != nil:
+		location := userLocation.NrLocation
+		nr := new(cdrType.NrLocation)
+		if location.Tai != nil {
+			tai, err := TaiToCdr(*location.Tai)
+			if err != nil {
+				return nil, structured, err
+			}
+			nr.Tai = &tai
+		}
+		if location.Ncgi != nil {
+			ncgi, err := NcgiToCdr(*location.Ncgi)
+			if err != nil {
+				return nil, structured, err
+			}
+			nr.Ncgi = &ncgi
+		}
+		if location.GlobalGnbId != nil {
+			ranNodeId, err := GlobalRanNodeIdToCdr(*location.GlobalGnbId)
+			if err != nil {
+				return nil, structured, err
+			}
+			nr.GlobalGnbId = &ranNodeId
+		}
+		if location.AgeOfLocationInformation != 0 {
+			nr.AgeOfLocationInformation = &cdrType.AgeOfLocationInformation{
+				Value: int64(location.AgeOfLocationInformation),
+			}
+		}
+		if location.UeLocationTimestamp != nil {
+			timeStamp := TimeStampToCdr(location.UeLocationTimestamp)
+			nr.UeLocationTimestamp = &timeStamp
+		}
+		structured.NrLocation = nr
+	case userLocation.N3gaLocation != nil:
+		location := userLocation.N3gaLocation
+		n3ga := new(cdrType.N3gaLocation)
+		if location.N3gppTai != nil {
+			tai, err := TaiToCdr(*location.N3gppTai)
+			if err != nil {
+				return nil, structured, err
+			}
+			n3ga.N3gppTai = &tai
+		}
+		if location.N3IwfId != "" {
+			n3ga.N3IwfId = &cdrType.N3IwFId{Value: asn.IA5String(location.N3IwfId)}
+		}
+		if location.UeIpv4Addr != "" {
+			address, err := cdrType.NewIPAddress(net.ParseIP(location.UeIpv4Addr))
+			if err != nil {
+				return nil, structured, err
+			}
+			n3ga.UeIpv4Addr = &address
+		}
+		if location.UeIpv6Addr != "" {
+			address, err := cdrType.NewIPAddress(net.ParseIP(location.UeIpv6Addr))
+			if err != nil {
+				return nil, structured, err
+			}
+			n3ga.UeIpv6Addr = &address
+		}
+		structured.N3gaLocation = n3ga
+		// N3GA locations have no octet string form
+		return nil, structured, nil
+	default:
+		return nil, structured, fmt.Errorf("userLocation without location")
+	}
+
+	uli, err := cdrType.NewUserLocationInformation(structured)
+	if err != nil {
+		// e.g. a location given by the RAN node only, which the octet
+		// string cannot carry
+		return nil, structured, nil
+	}
+	return &uli, structured, nil
+}
+
+func SnssaiToCdr(snssai models.Snssai) (cdrType.SingleNSSAI, error) {
+	cdrSnssai := cdrType.SingleNSSAI{
+		SST: cdrType.SliceServiceType{Value: int64(snssai.Sst)},
+	}
+	if snssai.Sd != "" {
+		sd, err := hex.DecodeString(snssai.Sd)
+		if err != nil || len(sd) != 3 {
+			return cdrType.SingleNSSAI{}, fmt.Errorf("invalid sd %q", snssai.Sd)
+		}
+		cdrSnssai.SD = &cdrType.SliceDifferentiator{Value: sd}
+	}
+	return cdrSnssai, nil
+}
+
+func SnssaiListToCdr(snssaiList []models.Snssai) ([]cdrType.SingleNSSAI, error) {
+	cdrSnssaiList := make([]cdrType.SingleNSSAI, 0, len(snssaiList))
+	for _, snssai := range snssaiList {
+		cdrSnssai, err := SnssaiToCdr(snssai)
+		if err != nil {
+			return nil, err
+		}
+		cdrSnssaiList = append(cdrSnssaiList, cdrSnssai)
+	}
+	return cdrSnssaiList, nil
+}
+
+// SupiToCdr converts an IMSI or NAI SUPI into the subscriber identifier.
+func SupiToCdr(supi string) (cdrType.SubscriptionID, error) {
+	switch {
+	case strings.HasPrefix(supi, "imsi-"):
+		return cdrType.SubscriptionID{
+			SubscriptionIDType: cdrType.SubscriptionIDType{Value: cdrType.SubscriptionIDTypePresentENDUSERIMSI},
+			SubscriptionIDData: asn.UTF8String(strings.TrimPrefix(supi, "imsi-")),
+		}, nil
+	case strings.HasPrefix(supi, "nai-"):
+		return cdrType.SubscriptionID{
+			SubscriptionIDType: cdrType.SubscriptionIDType{Value: cdrType.SubscriptionIDTypePresentENDUSERNAI},
+			SubscriptionIDData: asn.UTF8String(strings.TrimPrefix(supi, "nai-")),
+		}, nil
+	}
+	return cdrType.SubscriptionID{}, fmt.Errorf("unsupported supi %q", supi)
+}
+
+// GpsiToCdr converts an MSISDN or external identifier GPSI into an involved
+// party.
+func GpsiToCdr(gpsi string) (cdrType.InvolvedParty, error) {
+	switch {
+	case strings.HasPrefix(gpsi, "msisdn-"):
+		return cdrType.NewInvolvedPartyISDNE164(asn.GraphicString(strings.TrimPrefix(gpsi, "msisdn-"))), nil
+	case strings.HasPrefix(gpsi, "extid-"):
+		return cdrType.NewInvolvedPartyExternalId(asn.UTF8String(strings.TrimPrefix(gpsi, "extid-"))), nil
+	}
+	return cdrType.InvolvedParty{}, fmt.Errorf("unsupported gpsi %q", gpsi)
+}
+
+// TS 32.291 NodeFunctionality
+var nodeFunctionalityToCdr = map[models.NodeFunctionality]asn.Enumerated{
+	"AMF":          cdrType.NetworkFunctionalityPresentAMF,
+	"SMF":          cdrType.NetworkFunctionalityPresentSMF,
+	"SMS":          cdrType.NetworkFunctionalityPresentSMSF,
+	"SMSF":         cdrType.NetworkFunctionalityPresentSMSF,
+	"SGW":          cdrType.NetworkFunctionalityPresentSGW,
+	"I_SMF":        cdrType.NetworkFunctionalityPresentISMF,
+	"ePDG":         cdrType.NetworkFunctionalityPresentEPDG,
+	"CEF":          cdrType.NetworkFunctionalityPresentCEF,
+	"NEF":          cdrType.NetworkFunctionalityPresentNEF,
+	"PGW_C_SMF":    cdrType.NetworkFunctionalityPresentPGWCSMF,
+	"MnS_Producer": cdrType.NetworkFunctionalityPresentMnSProducer,
+}
+
+func NfIdentificationToCdr(nf models.NfIdentification) (cdrType.NetworkFunctionInformation, error) {
+	var nfInfo cdrType.NetworkFunctionInformation
+
+	functionality, ok := nodeFunctionalityToCdr[nf.NodeFunctionality]
+	if !ok {
+		return nfInfo, fmt.Errorf("unsupported nodeFunctionality %q", nf.NodeFunctionality)
+	}
+	nfInfo.NetworkFunctionality = cdrType.NetworkFunctionality{Value: functionality}
+
+	if nf.NFName != "" {
+		nfInfo.NetworkFunctionName = &cdrType.NetworkFunctionName{Value: asn.IA5String(nf.NFName)}
+	}
+	if nf.NFIPv4Address != "" {
+		address, err := cdrType.NewIPAddress(net.ParseIP(nf.NFIPv4Address))
+		if err != nil {
+			return nfInfo, err
+		}
+		nfInfo.NetworkFunctionIPv4Address = &address
+	}
+	if nf.NFIPv6Address != "" {
+		address, err := cdrType.NewIPAddress(net.ParseIP(nf.NFIPv6Address))
+		if err != nil {
+			return nfInfo, err
+		}
+		nfInfo.NetworkFunctionIPv6Address = &address
+	}
+	if nf.NFPLMNID != nil {
+		plmnId, err := PlmnIdToCdr(*nf.NFPLMNID)
+		if err != nil {
+			return nfInfo, err
+		}
+		nfInfo.NetworkFunctionPLMNIdentifier = &plmnId
+	}
+	if nf.NFFqdn != "" {
+		fqdn := cdrType.NewNodeAddressDomainName(asn.GraphicString(nf.NFFqdn))
+		nfInfo.NetworkFunctionFQDN = &fqdn
+	}
+	return nfInfo, nil
+}
+
+// OneTimeEventRecordToCdr fills the members common to the records of one-time
+// events reported to the CHF named chfName by a consumer of the given node
+// functionality: the record opens at the invocation time stamp, lasts zero
+// seconds and closes with normalRelease. The consumer identification may omit
+// the node functionality but must not contradict it.
+func OneTimeEventRecordToCdr(chfName string, request models.ChargingDataRequest,
+	nodeFunctionality models.NodeFunctionality) (cdrType.ChargingRecord, error) {
+	record := cdrType.ChargingRecord{
+		RecordType:                 cdrType.RecordType{Value: cdrType.RecordTypePresentChargingFunctionRecord},
+		RecordingNetworkFunctionID: cdrType.NetworkFunctionName{Value: asn.IA5String(chfName)},
+		CauseForRecClosing:         cdrType.CauseForRecClosing{Value: cdrType.CauseForRecClosingPresentNormalRelease},
+	}
+
+	if request.InvocationTimeStamp == nil {
+		return record, fmt.Errorf("missing invocationTimeStamp")
+	}
+	record.RecordOpeningTime = TimeStampToCdr(request.InvocationTimeStamp)
+
+	nfIdentification := models.NfIdentification{NodeFunctionality: nodeFunctionality}
+	if request.NfConsumerIdentification != nil {
+		nfIdentification = *request.NfConsumerIdentification
+		if nfIdentification.NodeFunctionality == "" {
+			nfIdentification.NodeFunctionality = nodeFunctionality
+		}
+	}
+	nfInfo, err := NfIdentificationToCdr(nfIdentification)
+	if err != nil {
+		return record, err
+	}
+	if nfInfo.NetworkFunctionality.Value != nodeFunctionalityToCdr[nodeFunctionality] {
+		return record, fmt.Errorf("nodeFunctionality %q is not %s", nfIdentification.NodeFunctionality, nodeFunctionality)
+	}
+	record.NFunctionConsumerInformation = nfInfo
+
+	if request.SubscriberIdentifier != "" {
+		subscriberIdentifier, err := SupiToCdr(request.SubscriberIdentifier)
+		if err != nil {
+			return record, err
+		}
+		record.SubscriberIdentifier = &subscriberIdentifier
+	}
+	if request.TenantIdentifier != "" {
+		record.TenantIdentifier = &cdrType.TenantIdentifier{Value: asn.OctetString(request.TenantIdentifier)}
+	}
+	if request.ChargingId != 0 {
+		record.ChargingID = &cdrType.ChargingID{Value: int64(request.ChargingId)}
+	}
+
+	return record, nil
+}
+
+// PduSessionChargingInformationToCdr converts the PDU session charging
+// information reported by an SMF, which must tell its PDU session.
+func PduSessionChargingInformationToCdr(pduSession models.PduSessionChargingInformation) (
+	cdrType.PDUSessionChargingInformation, error) {
+	cdrPduSession := cdrType.PDUSessionChargingInformation{
+		PDUSessionChargingID: cdrType.ChargingID{Value: int64(pduSession.ChargingId)},
+	}
+	if pduSession.HomeProvidedChargingId != 0 {
+		cdrPduSession.HomeProvidedChargingID = &cdrType.ChargingID{Value: int64(pduSession.HomeProvidedChargingId)}
+	}
+
+	information := pduSession.PduSessionInformation
+	if information == nil {
+		return cdrPduSession, fmt.Errorf("missing pduSessionInformation")
+	}
+	info, err := amfUserInformationToCdr(pduSession.UserInformation, pduSession.UserLocationinfo,
+		pduSession.UetimeZone, information.RatType)
+	if err != nil {
+		return cdrPduSession, err
+	}
+	cdrPduSession.UserIdentifier = info.userIdentifier
+	cdrPduSession.UserEquipmentInfo = info.userEquipmentInfo
+	cdrPduSession.SUPIunauthenticatedFlag = info.supiUnauthenticatedFlag
+	cdrPduSession.UserRoamerInOut = info.userRoamerInOut
+	cdrPduSession.UserLocationInformation = info.userLocationInformation
+	cdrPduSession.UserLocationInformationASN1 = info.userLocationASN1
+	cdrPduSession.UETimeZone = info.ueTimeZone
+	cdrPduSession.RATType = info.ratType
+
+	cdrPduSession.PDUSessionId = cdrType.PDUSessionId{Value: int64(information.PduSessionID)}
+	if information.NetworkSlicingInfo != nil && information.NetworkSlicingInfo.SNSSAI != nil {
+		snssai, err := SnssaiToCdr(*information.NetworkSlicingInfo.SNSSAI)
+		if err != nil {
+			return cdrPduSession, fmt.Errorf("networkSlicingInfo: %v", err)
+		}
+		cdrPduSession.NetworkSliceInstanceID = &snssai
+	}
+	if information.PduType != "" {
+		pduType, err := PduSessionTypeToCdr(information.PduType)
+		if err != nil {
+			return cdrPduSession, err
+		}
+		cdrPduSession.PDUType = &pduType
+	}
+	if information.SscMode != "" {
+		sscMode, err := SscModeToCdr(information.SscMode)
+		if err != nil {
+			return cdrPduSession, err
+		}
+		cdrPduSession.SSCMode = &sscMode
+	}
+	if information.HPlmnId != nil {
+		plmnId, err := PlmnIdToCdr(*information.HPlmnId)
+		if err != nil {
+			return cdrPduSession, fmt.Errorf("hPlmnId: %v", err)
+		}
+		cdrPduSession.SUPIPLMNIdentifier = &plmnId
+	}
+	if information.ServingCNPlmnId != nil {
+		plmnId, err := PlmnIdToCdr(*information.ServingCNPlmnId)
+		if err != nil {
+			return cdrPduSession, fmt.Errorf("servingCNPlmnId: %v", err)
+		}
+		cdrPduSession.ServingCNPLMNID = &plmnId
+	}
+	if information.DnnId != "" {
+		cdrPduSession.DataNetworkNameIdentifier = &cdrType.DataNetworkNameIdentifier{Value: asn.IA5String(information.DnnId)}
+	}
+	if information.PduAddress != nil {
+		pduAddress, err := PduAddressToCdr(*information.PduAddress)
+		if err != nil {
+			return cdrPduSession, err
+		}
+		cdrPduSession.PDUAddress = &pduAddress
+	}
+	if information.StartTime != nil {
+		startTime := TimeStampToCdr(information.StartTime)
+		cdrPduSession.PDUSessionstartTime = &startTime
+	}
+	if information.StopTime != nil {
+		stopTime := TimeStampToCdr(information.StopTime)
+		cdrPduSession.PDUSessionstopTime = &stopTime
+	}
+
+	return cdrPduSession, nil
 }
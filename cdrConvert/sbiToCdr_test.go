@@ -0,0 +1,88 @@
+package cdrConvert
+
+import (
+	"testing"
+	"time"
+
+	"github.com/free5gc/CDRUtil/cdrType"
+	"github.com/free5gc/openapi/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPduSessionChargingInformationToCdr(t *testing.T) {
+	t.Parallel()
+
+	startTime := time.Date(2022, time.May, 4, 10, 0, 0, 0, time.UTC)
+	pduSession := func() models.PduSessionChargingInformation {
+		return models.PduSessionChargingInformation{
+			ChargingId:             7,
+			HomeProvidedChargingId: 8,
+			UserInformation: &models.UserInformation{
+				ServedGPSI:  "msisdn-886912345678",
+				RoamerInOut: "OUT_BOUND",
+			},
+			UetimeZone: "-08:00+1",
+			PduSessionInformation: &models.PduSessionInformation{
+				PduSessionID: 5,
+				NetworkSlicingInfo: &models.NetworkSlicingInfo{
+					SNSSAI: &models.Snssai{Sst: 1, Sd: "010203"},
+				},
+				PduType:    "IPV4",
+				SscMode:    "SSC_MODE_1",
+				HPlmnId:    &models.PlmnId{Mcc: "208", Mnc: "93"},
+				RatType:    "NR",
+				DnnId:      "internet",
+				PduAddress: &models.PduAddress{PduIPv4Address: "10.60.0.1"},
+				StartTime:  &startTime,
+			},
+		}
+	}
+
+	cdrPduSession, err := PduSessionChargingInformationToCdr(pduSession())
+	require.NoError(t, err)
+	require.Equal(t, int64(7), cdrPduSession.PDUSessionChargingID.Value)
+	require.Equal(t, int64(8), cdrPduSession.HomeProvidedChargingID.Value)
+	require.NotNil(t, cdrPduSession.UserIdentifier)
+	require.Equal(t, cdrType.RoamerInOutPresentRoamerOutBound, cdrPduSession.UserRoamerInOut.Value)
+	offset, dst, err := cdrPduSession.UETimeZone.Decode()
+	require.NoError(t, err)
+	require.Equal(t, -8*3600, offset)
+	require.Equal(t, cdrType.DaylightSavingPlusOneHour, dst)
+	require.Equal(t, int64(5), cdrPduSession.PDUSessionId.Value)
+	require.Equal(t, int64(1), cdrPduSession.NetworkSliceInstanceID.SST.Value)
+	require.NotNil(t, cdrPduSession.PDUType)
+	require.NotNil(t, cdrPduSession.SSCMode)
+	mcc, mnc, err := cdrPduSession.SUPIPLMNIdentifier.Decode()
+	require.NoError(t, err)
+	require.Equal(t, "20893", mcc+mnc)
+	require.Equal(t, cdrType.RATTypePresentNR, cdrPduSession.RATType.Value)
+	require.Equal(t, "internet", string(cdrPduSession.DataNetworkNameIdentifier.Value))
+	require.NotNil(t, cdrPduSession.PDUAddress)
+	require.Equal(t, cdrType.NewTimeStamp(startTime), *cdrPduSession.PDUSessionstartTime)
+	require.Nil(t, cdrPduSession.PDUSessionstopTime)
+
+	testCases := []struct {
+		name   string
+		modify func(p *models.PduSessionChargingInformation)
+	}{
+		{"pduSessionInformation", func(p *models.PduSessionChargingInformation) { p.PduSessionInformation = nil }},
+		{"uetimeZone", func(p *models.PduSessionChargingInformation) { p.UetimeZone = "+25:00" }},
+		{"pduType", func(p *models.PduSessionChargingInformation) { p.PduSessionInformation.PduType = "IPV5" }},
+		{"hPlmnId", func(p *models.PduSessionChargingInformation) { p.PduSessionInformation.HPlmnId.Mcc = "2" }},
+		{"pduAddress", func(p *models.PduSessionChargingInformation) {
+			p.PduSessionInformation.PduAddress.PduIPv4Address = "10.60.0"
+		}},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			p := pduSession()
+			tc.modify(&p)
+			_, err := PduSessionChargingInformationToCdr(p)
+			require.Error(t, err)
+		})
+	}
+}
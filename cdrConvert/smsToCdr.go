@@ -0,0 +1,421 @@
+package cdrConvert
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/free5gc/CDRUtil/asn"
+	"github.com/free5gc/CDRUtil/cdrType"
+	"github.com/free5gc/openapi/models"
+)
+
+var smAddressTypeToCdr = map[models.SmAddressType]asn.Enumerated{
+	"EMAIL_ADDRESS":          cdrType.SMAddressTypePresentEmailAddress,
+	"MSISDN":                 cdrType.SMAddressTypePresentMSISDN,
+	"IPV4_ADDRESS":           cdrType.SMAddressTypePresentIPv4Address,
+	"IPV6_ADDRESS":           cdrType.SMAddressTypePresentIPv6Address,
+	"NUMERIC_SHORTCODE":      cdrType.SMAddressTypePresentNumericShortCode,
+	"ALPHANUMERIC_SHORTCODE": cdrType.SMAddressTypePresentAlphanumericShortCode,
+	"OTHER":                  cdrType.SMAddressTypePresentOther,
+	"IMSI":                   cdrType.SMAddressTypePresentIMSI,
+	"NAI":                    cdrType.SMAddressTypePresentNAI,
+	"EXTERNAL_ID":            cdrType.SMAddressTypePresentExternalId,
+}
+
+var smInterfaceTypeToCdr = map[models.InterfaceType]asn.Enumerated{
+	"UNKNOWN":                 cdrType.SMInterfaceTypePresentUnkown,
+	"MOBILE_ORIGINATING":      cdrType.SMInterfaceTypePresentMobileOriginating,
+	"MOBILE_TERMINATING":      cdrType.SMInterfaceTypePresentMobileTerminating,
+	"APPLICATION_ORIGINATING": cdrType.SMInterfaceTypePresentApplicationOriginating,
+	"APPLICATION_TERMINATING": cdrType.SMInterfaceTypePresentApplicationTerminating,
+	"DEVICE_TRIGGERING":       cdrType.SMInterfaceTypePresentDeviceTrigger,
+}
+
+var smMessageTypeToCdr = map[models.SmMessageType]asn.Enumerated{
+	"SUBMISSION":         cdrType.SMMessageTypePresentSubmission,
+	"DELIVERY_REPORT":    cdrType.SMMessageTypePresentDeliveryReport,
+	"SM_SERVICE_REQUEST": cdrType.SMMessageTypePresentSMServiceRequest,
+	"DELIVERY":           cdrType.SMMessageTypePresentDelivery,
+	"T4_DEVICE_TRIGGER":  cdrType.SMMessageTypePresentT4DeviceTrigger,
+	"SM_DEVICE_TRIGGER":  cdrType.SMMessageTypePresentSMDeviceTrigger,
+}
+
+var replyPathRequestedToCdr = map[models.ReplyPathRequested]asn.Enumerated{
+	"NO_REPLY_PATH_SET": cdrType.SMReplyPathRequestedPresentNoReplyPathSet,
+	"REPLY_PATH_SET":    cdrType.SMReplyPathRequestedPresentReplyPathSet,
+}
+
+var smServiceTypeToCdr = map[models.SmServiceType]int64{
+	"VAS4SMS_SHORT_MESSAGE_CONTENT_PROCESSING":                cdrType.SMServiceTypePresentVAS4SMSShortMessageContentProcessing,
+	"VAS4SMS_SHORT_MESSAGE_FORWARDING":                        cdrType.SMServiceTypePresentVAS4SMSShortMessageForwarding,
+	"VAS4SMS_SHORT_MESSAGE_FORWARDING_MULTIPLE_SUBSCRIPTIONS": cdrType.SMServiceTypePresentVAS4SMSShortMessageForwardingMultipleSubscriptions,
+	"VAS4SMS_SHORT_MESSAGE_FILTERING":                         cdrType.SMServiceTypePresentVAS4SMSShortMessageFiltering,
+	"VAS4SMS_SHORT_MESSAGE_RECEIPT":                           cdrType.SMServiceTypePresentVAS4SMSShortMessageReceipt,
+	"VAS4SMS_SHORT_MESSAGE_NETWORK_STORAGE":                   cdrType.SMServiceTypePresentVAS4SMSShortMessageNetworkStorage,
+	"VAS4SMS_SHORT_MESSAGE_TO_MULTIPLE_DESTINATIONS":          cdrType.SMServiceTypePresentVAS4SMSShortMessageToMultipleDestinations,
+	"VAS4SMS_SHORT_MESSAGE_VIRTUAL_PRIVATE_NETWORK":           cdrType.SMServiceTypePresentVAS4SMSShortMessageVirtualPrivateNetwork,
+	"VAS4SMS_SHORT_MESSAGE_AUTO_REPLY":                        cdrType.SMServiceTypePresentVAS4SMSShortMessageAutoReply,
+	"VAS4SMS_SHORT_MESSAGE_PERSONAL_SIGNATURE":                cdrType.SMServiceTypePresentVAS4SMSShortMessagePersonalSignature,
+	"VAS4SMS_SHORT_MESSAGE_DEFERRED_DELIVERY":                 cdrType.SMServiceTypePresentVAS4SMSShortMessageDeferredDelivery,
+}
+
+var smPriorityToCdr = map[models.SmPriority]asn.Enumerated{
+	"LOW":    cdrType.PriorityTypePresentLow,
+	"NORMAL": cdrType.PriorityTypePresentNormal,
+	"HIGH":   cdrType.PriorityTypePresentHigh,
+}
+
+var classIdentifierToCdr = map[models.ClassIdentifier]asn.Enumerated{
+	"PERSONAL":      cdrType.MessageClassPresentPersonal,
+	"ADVERTISEMENT": cdrType.MessageClassPresentAdvertisement,
+	"INFORMATIONAL": cdrType.MessageClassPresentInformationService,
+	"AUTO":          cdrType.MessageClassPresentAuto,
+}
+
+var deliveryReportRequestedToCdr = map[models.DeliveryReportRequested]asn.Enumerated{
+	"YES": cdrType.SMdeliveryReportRequestedPresentYes,
+	"NO":  cdrType.SMdeliveryReportRequestedPresentNo,
+}
+
+// E164AddressToCdr encodes an international E.164 number, such as an SMSC or
+// SCCP address, with or without a leading "+".
+func E164AddressToCdr(address string) (cdrType.AddressString, error) {
+	return cdrType.NewAddressString(cdrType.AddressNatureInternational, cdrType.NumberingPlanISDN,
+		strings.TrimPrefix(address, "+"))
+}
+
+// MessageReferenceToCdr encodes the TP-Message-Reference of TS 23.040, an
+// integer 0..255 carried as a decimal string, into its single octet. Other
+// references are kept as they are.
+func MessageReferenceToCdr(messageReference string) cdrType.MessageReference {
+	if reference, err := strconv.ParseUint(messageReference, 10, 8); err == nil {
+		return cdrType.MessageReference{Value: asn.OctetString{byte(reference)}}
+	}
+	return cdrType.MessageReference{Value: asn.OctetString(messageReference)}
+}
+
+func SmAddressInfoToCdr(addressInfo models.SmAddressInfo) (cdrType.SMAddressInfo, error) {
+	var cdrAddressInfo cdrType.SMAddressInfo
+
+	if addressInfo.SMaddressType != "" {
+		value, ok := smAddressTypeToCdr[addressInfo.SMaddressType]
+		if !ok {
+			return cdrAddressInfo, fmt.Errorf("unsupported sMaddressType %q", addressInfo.SMaddressType)
+		}
+		cdrAddressInfo.SMAddressType = &cdrType.SMAddressType{Value: value}
+	}
+	if addressInfo.SMaddressData != "" {
+		data := asn.GraphicString(addressInfo.SMaddressData)
+		cdrAddressInfo.SMAddressData = &data
+	}
+	if domain := addressInfo.SMaddressDomain; domain != nil {
+		cdrDomain := new(cdrType.SMAddressDomain)
+		if domain.DomainName != "" {
+			domainName := asn.GraphicString(domain.DomainName)
+			cdrDomain.SMDomainName = &domainName
+		}
+		// MCC followed by a 2 or 3 digit MNC
+		if mccMnc := domain.Var3GPPIMSIMCCMNC; mccMnc != "" {
+			if len(mccMnc) != 5 && len(mccMnc) != 6 {
+				return cdrAddressInfo, fmt.Errorf("invalid 3GPPIMSIMCCMNC %q", mccMnc)
+			}
+			plmnId, err := cdrType.NewPLMNId(mccMnc[:3], mccMnc[3:])
+			if err != nil {
+				return cdrAddressInfo, err
+			}
+			cdrDomain.ThreeGPPIMSIMCCMNC = &plmnId
+		}
+		cdrAddressInfo.SMAddressDomain = cdrDomain
+	}
+	return cdrAddressInfo, nil
+}
+
+func SmInterfaceToCdr(smInterface models.SmInterface) (cdrType.SMInterface, error) {
+	var cdrInterface cdrType.SMInterface
+
+	if smInterface.InterfaceId != "" {
+		interfaceId := asn.GraphicString(smInterface.InterfaceId)
+		cdrInterface.InterfaceId = &interfaceId
+	}
+	if smInterface.InterfaceText != "" {
+		interfaceText := asn.GraphicString(smInterface.InterfaceText)
+		cdrInterface.InterfaceText = &interfaceText
+	}
+	if smInterface.InterfacePort != "" {
+		interfacePort := asn.GraphicString(smInterface.InterfacePort)
+		cdrInterface.InterfacePort = &interfacePort
+	}
+	if smInterface.InterfaceType != "" {
+		value, ok := smInterfaceTypeToCdr[smInterface.InterfaceType]
+		if !ok {
+			return cdrInterface, fmt.Errorf("unsupported interfaceType %q", smInterface.InterfaceType)
+		}
+		cdrInterface.InterfaceType = &cdrType.SMInterfaceType{Value: value}
+	}
+	return cdrInterface, nil
+}
+
+// The addressing members shared by the originator and the recipients
+type smParty struct {
+	imsi            *cdrType.IMSI
+	msisdn          *cdrType.MSISDN
+	otherAddress    *cdrType.SMAddressInfo
+	sccpAddress     *cdrType.AddressString
+	receivedAddress *cdrType.SMAddressInfo
+	smInterface     *cdrType.SMInterface
+	protocolID      *asn.OctetString
+}
+
+func smPartyToCdr(supi, gpsi string, otherAddress, receivedAddress *models.SmAddressInfo, sccpAddress string,
+	smInterface *models.SmInterface, protocolID string) (smParty, error) {
+	var party smParty
+
+	if supi != "" {
+		imsi, err := cdrType.NewIMSIFromSupi(supi)
+		if err != nil {
+			return party, err
+		}
+		party.imsi = &imsi
+	}
+	if gpsi != "" {
+		msisdn, err := cdrType.NewMSISDNFromGpsi(gpsi)
+		if err != nil {
+			return party, err
+		}
+		party.msisdn = &msisdn
+	}
+	if otherAddress != nil {
+		addressInfo, err := SmAddressInfoToCdr(*otherAddress)
+		if err != nil {
+			return party, err
+		}
+		party.otherAddress = &addressInfo
+	}
+	if sccpAddress != "" {
+		address, err := E164AddressToCdr(sccpAddress)
+		if err != nil {
+			return party, err
+		}
+		party.sccpAddress = &address
+	}
+	if receivedAddress != nil {
+		addressInfo, err := SmAddressInfoToCdr(*receivedAddress)
+		if err != nil {
+			return party, err
+		}
+		party.receivedAddress = &addressInfo
+	}
+	if smInterface != nil {
+		cdrInterface, err := SmInterfaceToCdr(*smInterface)
+		if err != nil {
+			return party, err
+		}
+		party.smInterface = &cdrInterface
+	}
+	if protocolID != "" {
+		id, err := hex.DecodeString(protocolID)
+		if err != nil {
+			return party, fmt.Errorf("invalid protocol ID %q", protocolID)
+		}
+		value := asn.OctetString(id)
+		party.protocolID = &value
+	}
+	return party, nil
+}
+
+func OriginatorInfoToCdr(originator models.OriginatorInfo) (cdrType.OriginatorInfo, error) {
+	party, err := smPartyToCdr(originator.OriginatorSUPI, originator.OriginatorGPSI,
+		originator.OriginatorOtherAddress, originator.OriginatorReceivedAddress, originator.OriginatorSCCPAddress,
+		originator.SMOriginatorInterface, originator.SMOriginatorProtocolId)
+	if err != nil {
+		return cdrType.OriginatorInfo{}, fmt.Errorf("originatorInfo: %v", err)
+	}
+	return cdrType.OriginatorInfo{
+		OriginatorIMSI:            party.imsi,
+		OriginatorMSISDN:          party.msisdn,
+		OriginatorOtherAddress:    party.otherAddress,
+		OriginatorSCCPAddress:     party.sccpAddress,
+		OriginatorReceivedAddress: party.receivedAddress,
+		SMOriginatorInterface:     party.smInterface,
+		SMOriginatorProtocolID:    party.protocolID,
+	}, nil
+}
+
+func RecipientInfoToCdr(recipient models.RecipientInfo) (cdrType.RecipientInfo, error) {
+	party, err := smPartyToCdr(recipient.RecipientSUPI, recipient.RecipientGPSI,
+		recipient.RecipientOtherAddress, recipient.RecipientReceivedAddress, recipient.RecipientSCCPAddress,
+		recipient.SMDestinationInterface, recipient.SMrecipientProtocolId)
+	if err != nil {
+		return cdrType.RecipientInfo{}, fmt.Errorf("recipientInfo: %v", err)
+	}
+	return cdrType.RecipientInfo{
+		RecipientIMSI:            party.imsi,
+		RecipientMSISDN:          party.msisdn,
+		RecipientOtherAddress:    party.otherAddress,
+		RecipientSCCPAddress:     party.sccpAddress,
+		RecipientReceivedAddress: party.receivedAddress,
+		SMDestinationInterface:   party.smInterface,
+		SMRecipientProtocolID:    party.protocolID,
+	}, nil
+}
+
+// SmsChargingInformationToCdr converts the SMS charging information of an
+// event that happened at eventTime.
+func SmsChargingInformationToCdr(sms models.SmsChargingInformation, eventTime *time.Time) (
+	cdrType.SMSChargingInformation, error) {
+	var cdrSms cdrType.SMSChargingInformation
+
+	if eventTime == nil {
+		return cdrSms, fmt.Errorf("missing event time stamp")
+	}
+	cdrSms.Eventtimestamp = TimeStampToCdr(eventTime)
+
+	if sms.OriginatorInfo != nil {
+		originator, err := OriginatorInfoToCdr(*sms.OriginatorInfo)
+		if err != nil {
+			return cdrSms, err
+		}
+		cdrSms.OriginatorInfo = &originator
+	}
+	for _, recipient := range sms.RecipientInfo {
+		cdrRecipient, err := RecipientInfoToCdr(recipient)
+		if err != nil {
+			return cdrSms, err
+		}
+		cdrSms.RecipientInfos = append(cdrSms.RecipientInfos, cdrRecipient)
+	}
+
+	info, err := amfUserInformationToCdr(&models.UserInformation{
+		ServedPEI:   sms.UserEquipmentInfo,
+		RoamerInOut: sms.RoamerInOut,
+	}, sms.UserLocationinfo, sms.UetimeZone, sms.RATType)
+	if err != nil {
+		return cdrSms, err
+	}
+	cdrSms.UserEquipmentInfo = info.userEquipmentInfo
+	cdrSms.UserRoamerInOut = info.userRoamerInOut
+	cdrSms.UserLocationInformation = info.userLocationInformation
+	cdrSms.UserLocationInformationASN1 = info.userLocationASN1
+	cdrSms.UETimeZone = info.ueTimeZone
+	cdrSms.RATType = info.ratType
+
+	if sms.SMSCAddress != "" {
+		address, err := E164AddressToCdr(sms.SMSCAddress)
+		if err != nil {
+			return cdrSms, fmt.Errorf("sMSCAddress: %v", err)
+		}
+		cdrSms.SMSCAddress = &address
+	}
+	if sms.SMDataCodingScheme != 0 {
+		codingScheme := int64(sms.SMDataCodingScheme)
+		cdrSms.SMDataCodingScheme = &codingScheme
+	}
+	if sms.SMMessageType != "" {
+		value, ok := smMessageTypeToCdr[sms.SMMessageType]
+		if !ok {
+			return cdrSms, fmt.Errorf("unsupported sMMessageType %q", sms.SMMessageType)
+		}
+		cdrSms.SMMessageType = &cdrType.SMMessageType{Value: value}
+	}
+	if sms.SMReplyPathRequested != "" {
+		value, ok := replyPathRequestedToCdr[sms.SMReplyPathRequested]
+		if !ok {
+			return cdrSms, fmt.Errorf("unsupported sMReplyPathRequested %q", sms.SMReplyPathRequested)
+		}
+		cdrSms.SMReplyPathRequested = &cdrType.SMReplyPathRequested{Value: value}
+	}
+	if sms.SMUserDataHeader != "" {
+		header, err := hex.DecodeString(sms.SMUserDataHeader)
+		if err != nil {
+			return cdrSms, fmt.Errorf("invalid sMUserDataHeader %q", sms.SMUserDataHeader)
+		}
+		value := asn.OctetString(header)
+		cdrSms.SMUserDataHeader = &value
+	}
+	if sms.SMStatus != "" {
+		status, err := hex.DecodeString(sms.SMStatus)
+		if err != nil {
+			return cdrSms, fmt.Errorf("invalid sMStatus %q", sms.SMStatus)
+		}
+		cdrSms.SMSStatus = &cdrType.SMSStatus{Value: status}
+	}
+	if sms.SMDischargeTime != nil {
+		dischargeTime := TimeStampToCdr(sms.SMDischargeTime)
+		cdrSms.SMDischargeTime = &dischargeTime
+	}
+	if sms.NumberofMessagesSent != 0 {
+		totalNumber := int64(sms.NumberofMessagesSent)
+		cdrSms.SMTotalNumber = &totalNumber
+	}
+	if sms.SMServiceType != "" {
+		value, ok := smServiceTypeToCdr[sms.SMServiceType]
+		if !ok {
+			return cdrSms, fmt.Errorf("unsupported sMServiceType %q", sms.SMServiceType)
+		}
+		cdrSms.SMServiceType = &cdrType.SMServiceType{Value: value}
+	}
+	if sms.SubmissionTime != nil {
+		submissionTime := TimeStampToCdr(sms.SubmissionTime)
+		cdrSms.SubmissionTime = &submissionTime
+	}
+	if sms.SMPriority != "" {
+		value, ok := smPriorityToCdr[sms.SMPriority]
+		if !ok {
+			return cdrSms, fmt.Errorf("unsupported sMPriority %q", sms.SMPriority)
+		}
+		cdrSms.SMPriority = &cdrType.PriorityType{Value: value}
+	}
+	if sms.MessageReference != "" {
+		messageReference := MessageReferenceToCdr(sms.MessageReference)
+		cdrSms.MessageReference = &messageReference
+	}
+	if sms.MessageSize != 0 {
+		messageSize := int64(sms.MessageSize)
+		cdrSms.MessageSize = &messageSize
+	}
+	if messageClass := sms.MessageClass; messageClass != nil {
+		if messageClass.ClassIdentifier != "" {
+			value, ok := classIdentifierToCdr[messageClass.ClassIdentifier]
+			if !ok {
+				return cdrSms, fmt.Errorf("unsupported classIdentifier %q", messageClass.ClassIdentifier)
+			}
+			cdrSms.MessageClass = &cdrType.MessageClass{Value: value}
+		}
+		if messageClass.TokenText != "" {
+			tokenText := asn.UTF8String(messageClass.TokenText)
+			cdrSms.MessageClassTokenText = &tokenText
+		}
+	}
+	if sms.DeliveryReportRequested != "" {
+		value, ok := deliveryReportRequestedToCdr[sms.DeliveryReportRequested]
+		if !ok {
+			return cdrSms, fmt.Errorf("unsupported deliveryReportRequested %q", sms.DeliveryReportRequested)
+		}
+		cdrSms.SMdeliveryReportRequested = &cdrType.SMdeliveryReportRequested{Value: value}
+	}
+
+	return cdrSms, nil
+}
+
+// SmsChargingRecordToCdr builds the record of an SMS event reported by an
+// SMSF to the CHF named chfName, see OneTimeEventRecordToCdr.
+func SmsChargingRecordToCdr(chfName string, request models.ChargingDataRequest) (cdrType.ChargingRecord, error) {
+	if request.SMSChargingInformation == nil {
+		return cdrType.ChargingRecord{}, fmt.Errorf("missing sMSChargingInformation")
+	}
+	record, err := OneTimeEventRecordToCdr(chfName, request, "SMSF")
+	if err != nil {
+		return record, err
+	}
+
+	sms, err := SmsChargingInformationToCdr(*request.SMSChargingInformation, request.InvocationTimeStamp)
+	if err != nil {
+		return record, err
+	}
+	record.SMSChargingInformation = &sms
+
+	return record, nil
+}
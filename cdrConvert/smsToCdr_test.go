@@ -0,0 +1,177 @@
+package cdrConvert
+
+import (
+	"testing"
+	"time"
+
+	"github.com/free5gc/CDRUtil/asn"
+	"github.com/free5gc/CDRUtil/cdrType"
+	"github.com/free5gc/openapi/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessageReferenceToCdr(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		messageReference string
+		expected         asn.OctetString
+	}{
+		{"0", asn.OctetString{0}},
+		{"255", asn.OctetString{255}},
+		{"256", asn.OctetString("256")},
+		{"ref", asn.OctetString("ref")},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.messageReference, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tc.expected, MessageReferenceToCdr(tc.messageReference).Value)
+		})
+	}
+}
+
+func smsRequest() models.ChargingDataRequest {
+	invocationTime := time.Date(2022, time.May, 4, 10, 0, 0, 0, time.UTC)
+	submissionTime := invocationTime.Add(-time.Second)
+	return models.ChargingDataRequest{
+		InvocationTimeStamp:  &invocationTime,
+		SubscriberIdentifier: "imsi-208930000000003",
+		SMSChargingInformation: &models.SmsChargingInformation{
+			OriginatorInfo: &models.OriginatorInfo{
+				OriginatorSUPI:        "imsi-208930000000003",
+				OriginatorGPSI:        "msisdn-886912345678",
+				OriginatorSCCPAddress: "+886935000001",
+				SMOriginatorInterface: &models.SmInterface{InterfaceId: "smsf", InterfaceType: "MOBILE_ORIGINATING"},
+			},
+			RecipientInfo: []models.RecipientInfo{
+				{RecipientGPSI: "msisdn-886912345679"},
+				{RecipientOtherAddress: &models.SmAddressInfo{
+					SMaddressType:   "EMAIL_ADDRESS",
+					SMaddressData:   "user@example.com",
+					SMaddressDomain: &models.SmAddressDomain{Var3GPPIMSIMCCMNC: "20893"},
+				}},
+			},
+			UserEquipmentInfo:       "imei-4370816125816151",
+			RoamerInOut:             "IN_BOUND",
+			UetimeZone:              "+08:00",
+			RATType:                 "NR",
+			SMSCAddress:             "+886935000000",
+			SMDataCodingScheme:      8,
+			SMMessageType:           "SUBMISSION",
+			SMReplyPathRequested:    "NO_REPLY_PATH_SET",
+			SMUserDataHeader:        "050003000201",
+			SMStatus:                "00",
+			NumberofMessagesSent:    2,
+			SMServiceType:           "VAS4SMS_SHORT_MESSAGE_AUTO_REPLY",
+			SubmissionTime:          &submissionTime,
+			SMPriority:              "HIGH",
+			MessageReference:        "7",
+			MessageSize:             140,
+			MessageClass:            &models.MessageClass{ClassIdentifier: "PERSONAL", TokenText: "token"},
+			DeliveryReportRequested: "YES",
+		},
+	}
+}
+
+func TestSmsChargingRecordToCdr(t *testing.T) {
+	t.Parallel()
+
+	invocationTime := time.Date(2022, time.May, 4, 10, 0, 0, 0, time.UTC)
+
+	record, err := SmsChargingRecordToCdr("chf", smsRequest())
+	require.NoError(t, err)
+	require.Equal(t, cdrType.NetworkFunctionalityPresentSMSF, record.NFunctionConsumerInformation.NetworkFunctionality.Value)
+
+	sms := record.SMSChargingInformation
+	require.Equal(t, cdrType.NewTimeStamp(invocationTime), sms.Eventtimestamp)
+	imsi, err := sms.OriginatorInfo.OriginatorIMSI.Digits()
+	require.NoError(t, err)
+	require.Equal(t, "208930000000003", imsi)
+	msisdn, err := sms.OriginatorInfo.OriginatorMSISDN.Digits()
+	require.NoError(t, err)
+	require.Equal(t, "886912345678", msisdn)
+	sccpAddress, err := sms.OriginatorInfo.OriginatorSCCPAddress.Digits()
+	require.NoError(t, err)
+	require.Equal(t, "886935000001", sccpAddress)
+	require.Equal(t, cdrType.SMInterfaceTypePresentMobileOriginating,
+		sms.OriginatorInfo.SMOriginatorInterface.InterfaceType.Value)
+	require.Len(t, sms.RecipientInfos, 2)
+	require.NotNil(t, sms.RecipientInfos[0].RecipientMSISDN)
+	otherAddress := sms.RecipientInfos[1].RecipientOtherAddress
+	require.Equal(t, cdrType.SMAddressTypePresentEmailAddress, otherAddress.SMAddressType.Value)
+	require.Equal(t, asn.GraphicString("user@example.com"), *otherAddress.SMAddressData)
+	require.NotNil(t, otherAddress.SMAddressDomain.ThreeGPPIMSIMCCMNC)
+
+	require.NotNil(t, sms.UserEquipmentInfo)
+	require.Equal(t, cdrType.RoamerInOutPresentRoamerInBound, sms.UserRoamerInOut.Value)
+	require.NotNil(t, sms.UETimeZone)
+	require.Equal(t, cdrType.RATTypePresentNR, sms.RATType.Value)
+	smscAddress, err := sms.SMSCAddress.Digits()
+	require.NoError(t, err)
+	require.Equal(t, "886935000000", smscAddress)
+	require.Equal(t, int64(8), *sms.SMDataCodingScheme)
+	require.Equal(t, cdrType.SMMessageTypePresentSubmission, sms.SMMessageType.Value)
+	require.Equal(t, cdrType.SMReplyPathRequestedPresentNoReplyPathSet, sms.SMReplyPathRequested.Value)
+	require.Equal(t, asn.OctetString{5, 0, 3, 0, 2, 1}, *sms.SMUserDataHeader)
+	require.Equal(t, []byte{0}, []byte(sms.SMSStatus.Value))
+	require.Equal(t, int64(2), *sms.SMTotalNumber)
+	require.Equal(t, cdrType.SMServiceTypePresentVAS4SMSShortMessageAutoReply, sms.SMServiceType.Value)
+	require.Equal(t, cdrType.NewTimeStamp(invocationTime.Add(-time.Second)), *sms.SubmissionTime)
+	require.Equal(t, cdrType.PriorityTypePresentHigh, sms.SMPriority.Value)
+	require.Equal(t, asn.OctetString{7}, sms.MessageReference.Value)
+	require.Equal(t, int64(140), *sms.MessageSize)
+	require.Equal(t, cdrType.MessageClassPresentPersonal, sms.MessageClass.Value)
+	require.Equal(t, asn.UTF8String("token"), *sms.MessageClassTokenText)
+	require.Equal(t, cdrType.SMdeliveryReportRequestedPresentYes, sms.SMdeliveryReportRequested.Value)
+
+	testCases := []struct {
+		name   string
+		modify func(sms *models.SmsChargingInformation)
+	}{
+		{"originatorSUPI", func(sms *models.SmsChargingInformation) { sms.OriginatorInfo.OriginatorSUPI = "imsi-2089" }},
+		{"originatorSCCPAddress", func(sms *models.SmsChargingInformation) {
+			sms.OriginatorInfo.OriginatorSCCPAddress = "+8869350000x1"
+		}},
+		{"interfaceType", func(sms *models.SmsChargingInformation) {
+			sms.OriginatorInfo.SMOriginatorInterface.InterfaceType = "MOBILE"
+		}},
+		{"sMOriginatorProtocolId", func(sms *models.SmsChargingInformation) {
+			sms.OriginatorInfo.SMOriginatorProtocolId = "x"
+		}},
+		{"recipientGPSI", func(sms *models.SmsChargingInformation) { sms.RecipientInfo[0].RecipientGPSI = "886912345679" }},
+		{"sMaddressType", func(sms *models.SmsChargingInformation) {
+			sms.RecipientInfo[1].RecipientOtherAddress.SMaddressType = "URL"
+		}},
+		{"3GPPIMSIMCCMNC", func(sms *models.SmsChargingInformation) {
+			sms.RecipientInfo[1].RecipientOtherAddress.SMaddressDomain.Var3GPPIMSIMCCMNC = "2089"
+		}},
+		{"userEquipmentInfo", func(sms *models.SmsChargingInformation) { sms.UserEquipmentInfo = "4370816125816151" }},
+		{"sMSCAddress", func(sms *models.SmsChargingInformation) { sms.SMSCAddress = "smsc" }},
+		{"sMMessageType", func(sms *models.SmsChargingInformation) { sms.SMMessageType = "DELIVERY_FAILURE" }},
+		{"sMReplyPathRequested", func(sms *models.SmsChargingInformation) { sms.SMReplyPathRequested = "YES" }},
+		{"sMUserDataHeader", func(sms *models.SmsChargingInformation) { sms.SMUserDataHeader = "05000" }},
+		{"sMStatus", func(sms *models.SmsChargingInformation) { sms.SMStatus = "ok" }},
+		{"sMServiceType", func(sms *models.SmsChargingInformation) { sms.SMServiceType = "VAS4SMS" }},
+		{"sMPriority", func(sms *models.SmsChargingInformation) { sms.SMPriority = "URGENT" }},
+		{"classIdentifier", func(sms *models.SmsChargingInformation) { sms.MessageClass.ClassIdentifier = "PUBLIC" }},
+		{"deliveryReportRequested", func(sms *models.SmsChargingInformation) { sms.DeliveryReportRequested = "MAYBE" }},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := smsRequest()
+			tc.modify(r.SMSChargingInformation)
+			_, err := SmsChargingRecordToCdr("chf", r)
+			require.Error(t, err)
+		})
+	}
+
+	_, err = SmsChargingRecordToCdr("chf", models.ChargingDataRequest{InvocationTimeStamp: &invocationTime})
+	require.Error(t, err)
+}
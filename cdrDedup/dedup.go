@@ -0,0 +1,438 @@
+// Package cdrDedup detects the charging records delivered more than once,
+// possibly in different CDR files.
+package cdrDedup
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/free5gc/CDRUtil/asn"
+	"github.com/free5gc/CDRUtil/cdrFile"
+	"github.com/free5gc/CDRUtil/cdrType"
+	"github.com/free5gc/CDRUtil/internal/safeFile"
+)
+
+// Fingerprint identifies a charging record.
+type Fingerprint [sha256.Size]byte
+
+// NewFingerprint returns the fingerprint of the recording network function,
+// charging session identifier, RecordSequenceNumber and RecordOpeningTime of
+// record. Records without charging session identifier, as those of one-time
+// events, are told apart by their LocalRecordSequenceNumber as well.
+func NewFingerprint(record cdrType.ChargingRecord) Fingerprint {
+	hash := sha256.New()
+	writeField := func(field []byte) {
+		var length [4]byte
+		binary.BigEndian.PutUint32(length[:], uint32(len(field)))
+		hash.Write(length[:])
+		hash.Write(field)
+	}
+	writeNumber := func(n *int64) {
+		if n == nil {
+			writeField(nil)
+			return
+		}
+		var field [8]byte
+		binary.BigEndian.PutUint64(field[:], uint64(*n))
+		writeField(field[:])
+	}
+
+	writeField([]byte(record.RecordingNetworkFunctionID.Value))
+	if record.ChargingSessionIdentifier != nil {
+		writeField(record.ChargingSessionIdentifier.Value)
+	} else {
+		writeField(nil)
+		if record.LocalRecordSequenceNumber != nil {
+			writeNumber(&record.LocalRecordSequenceNumber.Value)
+		} else {
+			writeNumber(nil)
+		}
+	}
+	writeNumber(record.RecordSequenceNumber)
+	writeField(record.RecordOpeningTime.Value)
+
+	var fingerprint Fingerprint
+	hash.Sum(fingerprint[:0])
+	return fingerprint
+}
+
+// Index holds the fingerprints of the last records seen, up to its capacity,
+// the oldest ones being forgotten first. An index opened from a file keeps
+// its fingerprints in it.
+//
+// It is safe for concurrent use.
+type Index struct {
+	capacity int
+	path     string
+
+	mu           sync.Mutex
+	fingerprints map[Fingerprint]struct{}
+	// ring of the fingerprints, next being the oldest once it is full
+	order []Fingerprint
+	next  int
+	file  *os.File
+	// fingerprints in the file
+	written int
+}
+
+// NewIndex returns an index in memory of the given capacity.
+func NewIndex(capacity int) (*Index, error) {
+	if capacity <= 0 {
+		return nil, fmt.Errorf("capacity %d is not positive", capacity)
+	}
+	return &Index{
+		capacity:     capacity,
+		fingerprints: make(map[Fingerprint]struct{}, capacity),
+		order:        make([]Fingerprint, 0, capacity),
+	}, nil
+}
+
+// OpenIndex opens the index of the given capacity kept in the file at path,
+// created if it does not exist. A fingerprint cut by a crash while it was
+// being written is dropped.
+func OpenIndex(path string, capacity int) (*Index, error) {
+	index, err := NewIndex(capacity)
+	if err != nil {
+		return nil, err
+	}
+	index.path = path
+
+	file, err := os.OpenFile(path, os.O_RDONLY|os.O_CREATE, 0o666)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		var fingerprint Fingerprint
+		if _, err := io.ReadFull(file, fingerprint[:]); err != nil {
+			if err == io.EOF || err == io.ErrUnexpectedEOF {
+				break
+			}
+			file.Close()
+			return nil, err
+		}
+		index.add(fingerprint)
+	}
+	file.Close()
+
+	if err := index.rewrite(); err != nil {
+		return nil, err
+	}
+	return index, nil
+}
+
+// Add records fingerprint, returning whether it was already in the index. The
+// fingerprints added to the file of the index are only synced by Sync. If the
+// file cannot be written, the fingerprint is not recorded.
+func (x *Index) Add(fingerprint Fingerprint) (bool, error) {
+	seen, err := x.addAll([]Fingerprint{fingerprint}, false)
+	if err != nil {
+		return false, err
+	}
+	return seen[0], nil
+}
+
+// addAll records the fingerprints in order, returning whether each was
+// already in the index, the earlier ones included, and then syncs the file if
+// sync is set. On an error, the index is left as it was.
+func (x *Index) addAll(fingerprints []Fingerprint, sync bool) ([]bool, error) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	seen := make([]bool, len(fingerprints))
+	var added []Fingerprint
+	var additions []addition
+	for i, fingerprint := range fingerprints {
+		if _, ok := x.fingerprints[fingerprint]; ok {
+			seen[i] = true
+			continue
+		}
+		additions = append(additions, x.add(fingerprint))
+		added = append(added, fingerprint)
+	}
+	if x.path == "" {
+		return seen, nil
+	}
+
+	if err := x.store(added, sync); err != nil {
+		x.undo(added, additions)
+		return nil, err
+	}
+	return seen, nil
+}
+
+// store writes the fingerprints just added to the file, or rewrites the file
+// once it would hold more than twice the capacity. A failed write is cut off
+// the file.
+func (x *Index) store(added []Fingerprint, sync bool) error {
+	if x.written+len(added) > 2*x.capacity {
+		return x.rewrite()
+	}
+
+	buf := make([]byte, 0, len(added)*len(Fingerprint{}))
+	for _, fingerprint := range added {
+		buf = append(buf, fingerprint[:]...)
+	}
+	_, err := x.file.Write(buf)
+	if err == nil && sync {
+		err = x.file.Sync()
+	}
+	if err != nil {
+		if truncateErr := x.file.Truncate(int64(x.written * len(Fingerprint{}))); truncateErr != nil {
+			return fmt.Errorf("%v, and truncating the index: %v", err, truncateErr)
+		}
+		return err
+	}
+	x.written += len(added)
+	return nil
+}
+
+// Contains returns whether fingerprint is in the index.
+func (x *Index) Contains(fingerprint Fingerprint) bool {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	_, ok := x.fingerprints[fingerprint]
+	return ok
+}
+
+// Len returns the number of fingerprints in the index.
+func (x *Index) Len() int {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	return len(x.fingerprints)
+}
+
+// Sync commits the fingerprints added to the file of the index.
+func (x *Index) Sync() error {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	if x.file == nil {
+		return nil
+	}
+	return x.file.Sync()
+}
+
+// Close syncs and closes the file of the index, if any.
+func (x *Index) Close() error {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	if x.file == nil {
+		return nil
+	}
+	if err := x.file.Sync(); err != nil {
+		x.file.Close()
+		return err
+	}
+	return x.file.Close()
+}
+
+// addition is what adding a fingerprint to a full index evicted, to undo it.
+type addition struct {
+	evicts  bool
+	evicted Fingerprint
+}
+
+func (x *Index) add(fingerprint Fingerprint) addition {
+	var a addition
+	if _, ok := x.fingerprints[fingerprint]; ok {
+		return a
+	}
+	if len(x.order) < x.capacity {
+		x.order = append(x.order, fingerprint)
+	} else {
+		a = addition{evicts: true, evicted: x.order[x.next]}
+		delete(x.fingerprints, a.evicted)
+		x.order[x.next] = fingerprint
+		x.next = (x.next + 1) % x.capacity
+	}
+	x.fingerprints[fingerprint] = struct{}{}
+	return a
+}
+
+// undo removes the fingerprints added, last first, restoring those they
+// evicted.
+func (x *Index) undo(added []Fingerprint, additions []addition) {
+	for i := len(added) - 1; i >= 0; i-- {
+		delete(x.fingerprints, added[i])
+		if !additions[i].evicts {
+			x.order = x.order[:len(x.order)-1]
+			continue
+		}
+		x.next = (x.next + x.capacity - 1) % x.capacity
+		x.order[x.next] = additions[i].evicted
+		x.fingerprints[additions[i].evicted] = struct{}{}
+	}
+}
+
+// rewrite rewrites the file with the fingerprints of the index only, oldest
+// first. The new file replaces the old one once synced.
+func (x *Index) rewrite() error {
+	buf := make([]byte, 0, len(x.order)*len(Fingerprint{}))
+	for i := range x.order {
+		fingerprint := x.order[(x.next+i)%len(x.order)]
+		buf = append(buf, fingerprint[:]...)
+	}
+
+	if err := safeFile.Replace(x.path, buf); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(x.path, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		return err
+	}
+	if x.file != nil {
+		x.file.Close()
+	}
+	x.file = file
+	x.written = len(x.order)
+	return nil
+}
+
+// Action is what a Filter does with the duplicates.
+type Action int
+
+const (
+	// Drop removes the duplicates from the file.
+	Drop Action = iota
+	// Flag keeps the duplicates, only reporting them.
+	Flag
+)
+
+// Filter checks the CDRs of CDR files against an index, each CDR being a
+// duplicate if its record was seen before, in the same file or not.
+type Filter struct {
+	index  *Index
+	action Action
+}
+
+// NewFilter returns a filter recording the CDRs in index.
+func NewFilter(index *Index, action Action) *Filter {
+	return &Filter{index: index, action: action}
+}
+
+// CDRReader reads CDRs one at a time, returning io.EOF after the last one.
+type CDRReader interface {
+	ReadCDR() (cdrFile.CDR, error)
+}
+
+// CDRWriter writes the CDRs kept by a Filter.
+type CDRWriter interface {
+	WriteCDR(cdr cdrFile.CDR) error
+}
+
+// Check decodes the charging record of cdr and returns whether it is a
+// duplicate, recording it otherwise.
+func (f *Filter) Check(cdr cdrFile.CDR) (bool, error) {
+	fingerprint, err := fingerprintOf(cdr)
+	if err != nil {
+		return false, err
+	}
+	return f.index.Add(fingerprint)
+}
+
+// FilterFile checks the CDRs of file in order and returns the file filtered by
+// the action of f along with the positions of the duplicates in file. When
+// they are dropped, the CDR count and length in the file header are updated.
+// The CDRs are recorded all at once and the index is synced before FilterFile
+// returns, so that the index is left as it is if one of them cannot be
+// decoded or the index cannot be written.
+func (f *Filter) FilterFile(file cdrFile.CDRFile) (cdrFile.CDRFile, []int, error) {
+	fingerprints := make([]Fingerprint, len(file.CdrList))
+	for i, cdr := range file.CdrList {
+		var err error
+		if fingerprints[i], err = fingerprintOf(cdr); err != nil {
+			return file, nil, fmt.Errorf("cdr %d: %v", i, err)
+		}
+	}
+	seen, err := f.index.addAll(fingerprints, true)
+	if err != nil {
+		return file, nil, err
+	}
+
+	var duplicates []int
+	filtered := file
+	filtered.CdrList = make([]cdrFile.CDR, 0, len(file.CdrList))
+
+	for i, cdr := range file.CdrList {
+		if !seen[i] {
+			filtered.CdrList = append(filtered.CdrList, cdr)
+			continue
+		}
+		duplicates = append(duplicates, i)
+		if f.action == Flag {
+			filtered.CdrList = append(filtered.CdrList, cdr)
+			continue
+		}
+		filtered.Hdr.NumberOfCdrsInFile--
+		if filtered.Hdr.FileLength != 0xffffffff {
+			filtered.Hdr.FileLength -= uint32(len(cdr.Hdr.Encoding()) + len(cdr.CdrByte))
+		}
+	}
+	return filtered, duplicates, nil
+}
+
+// FilterStream checks the CDRs read from r in order, writes to w those kept
+// by the action of f and returns the positions of the duplicates, without
+// holding the CDRs in memory. A CDR is recorded once written, so that on an
+// error the CDRs before it have been written and recorded, and it has not.
+// The index is synced before FilterStream returns.
+func (f *Filter) FilterStream(r CDRReader, w CDRWriter) ([]int, error) {
+	duplicates, err := f.filterStream(r, w)
+	if syncErr := f.index.Sync(); err == nil {
+		err = syncErr
+	}
+	return duplicates, err
+}
+
+func (f *Filter) filterStream(r CDRReader, w CDRWriter) ([]int, error) {
+	var duplicates []int
+	for i := 0; ; i++ {
+		cdr, err := r.ReadCDR()
+		if err == io.EOF {
+			return duplicates, nil
+		}
+		if err != nil {
+			return duplicates, fmt.Errorf("cdr %d: %v", i, err)
+		}
+		fingerprint, err := fingerprintOf(cdr)
+		if err != nil {
+			return duplicates, fmt.Errorf("cdr %d: %v", i, err)
+		}
+
+		duplicate := f.index.Contains(fingerprint)
+		if duplicate {
+			duplicates = append(duplicates, i)
+		}
+		if !duplicate || f.action == Flag {
+			if err := w.WriteCDR(cdr); err != nil {
+				return duplicates, fmt.Errorf("cdr %d: %v", i, err)
+			}
+		}
+		if !duplicate {
+			if _, err := f.index.Add(fingerprint); err != nil {
+				return duplicates, fmt.Errorf("cdr %d: %v", i, err)
+			}
+		}
+	}
+}
+
+// fingerprintOf decodes the charging record of cdr and returns its
+// fingerprint.
+func fingerprintOf(cdr cdrFile.CDR) (Fingerprint, error) {
+	if cdr.Hdr.DataRecordFormat != cdrFile.BasicEncodingRules {
+		return Fingerprint{}, fmt.Errorf("unsupported data record format %d", cdr.Hdr.DataRecordFormat)
+	}
+	var record cdrType.ChargingRecord
+	if err := asn.UnmarshalWithParams(cdr.CdrByte, &record, ""); err != nil {
+		return Fingerprint{}, err
+	}
+	return NewFingerprint(record), nil
+}
@@ -0,0 +1,290 @@
+package cdrDedup
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/free5gc/CDRUtil/asn"
+	"github.com/free5gc/CDRUtil/cdrFile"
+	"github.com/free5gc/CDRUtil/cdrType"
+	"github.com/free5gc/CDRUtil/internal/cdrTest"
+	"github.com/stretchr/testify/require"
+)
+
+func cdrFileOf(t *testing.T, records ...cdrType.ChargingRecord) cdrFile.CDRFile {
+	var file cdrFile.CDRFile
+	file.Hdr.FileLength = 100
+	for _, record := range records {
+		b, err := asn.BerMarshalWithParams(record, "")
+		require.NoError(t, err)
+		cdr := cdrFile.CDR{
+			Hdr: cdrFile.CdrHeader{
+				CdrLength:        uint16(len(b)),
+				DataRecordFormat: cdrFile.BasicEncodingRules,
+			},
+			CdrByte: b,
+		}
+		file.CdrList = append(file.CdrList, cdr)
+		file.Hdr.NumberOfCdrsInFile++
+		file.Hdr.FileLength += uint32(len(cdr.Hdr.Encoding()) + len(b))
+	}
+	return file
+}
+
+func TestFingerprint(t *testing.T) {
+	t.Parallel()
+
+	record := cdrTest.ChargingRecord("session1", 1)
+	require.Equal(t, NewFingerprint(record), NewFingerprint(cdrTest.ChargingRecord("session1", 1)))
+
+	other := cdrTest.ChargingRecord("session1", 1)
+	other.RecordOpeningTime = cdrType.TimeStamp{Value: asn.OctetString{0x22, 0x05, 0x04, 0x10, 0x00, 0x01, '+', 0x00, 0x00}}
+	require.NotEqual(t, NewFingerprint(record), NewFingerprint(other))
+	require.NotEqual(t, NewFingerprint(record), NewFingerprint(cdrTest.ChargingRecord("session1", 2)))
+	require.NotEqual(t, NewFingerprint(record), NewFingerprint(cdrTest.ChargingRecord("session2", 1)))
+
+	// one-time events
+	record.ChargingSessionIdentifier = nil
+	record.RecordSequenceNumber = nil
+	other = record
+	record.LocalRecordSequenceNumber = &cdrType.LocalSequenceNumber{Value: 1}
+	other.LocalRecordSequenceNumber = &cdrType.LocalSequenceNumber{Value: 2}
+	require.NotEqual(t, NewFingerprint(record), NewFingerprint(other))
+}
+
+func TestIndex(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "index")
+	index, err := OpenIndex(path, 3)
+	require.NoError(t, err)
+
+	var fingerprints []Fingerprint
+	for i := int64(1); i <= 10; i++ {
+		fingerprints = append(fingerprints, NewFingerprint(cdrTest.ChargingRecord("session1", i)))
+	}
+	for _, fingerprint := range fingerprints[:4] {
+		duplicate, err := index.Add(fingerprint)
+		require.NoError(t, err)
+		require.False(t, duplicate)
+	}
+	// the first one was forgotten
+	require.Equal(t, 3, index.Len())
+	require.False(t, index.Contains(fingerprints[0]))
+	duplicate, err := index.Add(fingerprints[3])
+	require.NoError(t, err)
+	require.True(t, duplicate)
+	require.NoError(t, index.Close())
+
+	index, err = OpenIndex(path, 3)
+	require.NoError(t, err)
+	require.Equal(t, 3, index.Len())
+	for _, fingerprint := range fingerprints[1:4] {
+		require.True(t, index.Contains(fingerprint))
+	}
+	// the file is rewritten as it grows
+	for _, fingerprint := range fingerprints[4:] {
+		_, err := index.Add(fingerprint)
+		require.NoError(t, err)
+	}
+	require.LessOrEqual(t, index.written, 2*3)
+	require.NoError(t, index.Close())
+
+	index, err = OpenIndex(path, 3)
+	require.NoError(t, err)
+	for _, fingerprint := range fingerprints[7:] {
+		require.True(t, index.Contains(fingerprint))
+	}
+	require.NoError(t, index.Close())
+
+	_, err = NewIndex(0)
+	require.Error(t, err)
+}
+
+func TestFilterFile(t *testing.T) {
+	t.Parallel()
+
+	file1 := cdrFileOf(t, cdrTest.ChargingRecord("session1", 1), cdrTest.ChargingRecord("session1", 2))
+	file2 := cdrFileOf(t, cdrTest.ChargingRecord("session1", 2), cdrTest.ChargingRecord("session2", 1),
+		cdrTest.ChargingRecord("session1", 3), cdrTest.ChargingRecord("session2", 1))
+
+	testCases := []struct {
+		name       string
+		action     Action
+		cdrs       int
+		duplicates []int
+	}{
+		{"drop", Drop, 2, []int{0, 3}},
+		{"flag", Flag, 4, []int{0, 3}},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			index, err := NewIndex(10)
+			require.NoError(t, err)
+			filter := NewFilter(index, tc.action)
+
+			filtered, duplicates, err := filter.FilterFile(file1)
+			require.NoError(t, err)
+			require.Empty(t, duplicates)
+			require.Equal(t, file1, filtered)
+
+			filtered, duplicates, err = filter.FilterFile(file2)
+			require.NoError(t, err)
+			require.Equal(t, tc.duplicates, duplicates)
+			require.Len(t, filtered.CdrList, tc.cdrs)
+			require.Equal(t, uint32(tc.cdrs), filtered.Hdr.NumberOfCdrsInFile)
+
+			length := uint32(100)
+			for _, cdr := range filtered.CdrList {
+				length += uint32(len(cdr.Hdr.Encoding()) + len(cdr.CdrByte))
+			}
+			require.Equal(t, length, filtered.Hdr.FileLength)
+		})
+	}
+}
+
+func TestFilterFileInvalidCdr(t *testing.T) {
+	t.Parallel()
+
+	index, err := NewIndex(10)
+	require.NoError(t, err)
+	filter := NewFilter(index, Drop)
+
+	file := cdrFileOf(t, cdrTest.ChargingRecord("session1", 1), cdrTest.ChargingRecord("session1", 2))
+	file.CdrList[1].CdrByte = []byte{0xff}
+	_, _, err = filter.FilterFile(file)
+	require.Error(t, err)
+	// the first CDR is not recorded, to be delivered again with the fixed file
+	require.Equal(t, 0, index.Len())
+}
+
+func TestFilterFileIndexFailure(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "index")
+	index, err := OpenIndex(path, 3)
+	require.NoError(t, err)
+	filter := NewFilter(index, Drop)
+
+	file1 := cdrFileOf(t, cdrTest.ChargingRecord("session1", 1), cdrTest.ChargingRecord("session1", 2))
+	file2 := cdrFileOf(t, cdrTest.ChargingRecord("session1", 2), cdrTest.ChargingRecord("session2", 1),
+		cdrTest.ChargingRecord("session1", 3))
+	_, _, err = filter.FilterFile(file1)
+	require.NoError(t, err)
+
+	// an index file that cannot be written
+	writable := index.file
+	index.file, err = os.Open(path)
+	require.NoError(t, err)
+
+	_, _, err = filter.FilterFile(file2)
+	require.Error(t, err)
+	_, err = index.Add(NewFingerprint(cdrTest.ChargingRecord("session3", 1)))
+	require.Error(t, err)
+
+	// the index is left as it was, the evicted fingerprint included
+	require.Equal(t, 2, index.Len())
+	require.True(t, index.Contains(NewFingerprint(cdrTest.ChargingRecord("session1", 1))))
+	require.False(t, index.Contains(NewFingerprint(cdrTest.ChargingRecord("session2", 1))))
+	require.False(t, index.Contains(NewFingerprint(cdrTest.ChargingRecord("session3", 1))))
+	require.NoError(t, index.file.Close())
+	index.file = writable
+
+	filtered, duplicates, err := filter.FilterFile(file2)
+	require.NoError(t, err)
+	require.Equal(t, []int{0}, duplicates)
+	require.Len(t, filtered.CdrList, 2)
+	require.NoError(t, index.Close())
+
+	index, err = OpenIndex(path, 3)
+	require.NoError(t, err)
+	require.Equal(t, 3, index.Len())
+	require.False(t, index.Contains(NewFingerprint(cdrTest.ChargingRecord("session1", 1))))
+	require.True(t, index.Contains(NewFingerprint(cdrTest.ChargingRecord("session1", 3))))
+	require.NoError(t, index.Close())
+}
+
+// cdrStream reads its CDRs and writes the CDRs to written, failing while err
+// is set.
+type cdrStream struct {
+	cdrs    []cdrFile.CDR
+	written []cdrFile.CDR
+	err     error
+}
+
+func (s *cdrStream) ReadCDR() (cdrFile.CDR, error) {
+	if len(s.cdrs) == 0 {
+		return cdrFile.CDR{}, io.EOF
+	}
+	cdr := s.cdrs[0]
+	s.cdrs = s.cdrs[1:]
+	return cdr, nil
+}
+
+func (s *cdrStream) WriteCDR(cdr cdrFile.CDR) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.written = append(s.written, cdr)
+	return nil
+}
+
+func TestFilterStream(t *testing.T) {
+	t.Parallel()
+
+	file := cdrFileOf(t, cdrTest.ChargingRecord("session1", 1), cdrTest.ChargingRecord("session2", 1),
+		cdrTest.ChargingRecord("session1", 1), cdrTest.ChargingRecord("session1", 2))
+
+	testCases := []struct {
+		name    string
+		action  Action
+		written int
+	}{
+		{"drop", Drop, 3},
+		{"flag", Flag, 4},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			index, err := NewIndex(10)
+			require.NoError(t, err)
+			filter := NewFilter(index, tc.action)
+
+			stream := &cdrStream{cdrs: file.CdrList}
+			duplicates, err := filter.FilterStream(stream, stream)
+			require.NoError(t, err)
+			require.Equal(t, []int{2}, duplicates)
+			require.Len(t, stream.written, tc.written)
+			require.Equal(t, 3, index.Len())
+		})
+	}
+
+	index, err := NewIndex(10)
+	require.NoError(t, err)
+	filter := NewFilter(index, Drop)
+
+	// the CDRs before an invalid one are written and recorded
+	invalid := append([]cdrFile.CDR(nil), file.CdrList...)
+	invalid[1].CdrByte = []byte{0xff}
+	stream := &cdrStream{cdrs: invalid}
+	_, err = filter.FilterStream(stream, stream)
+	require.Error(t, err)
+	require.Len(t, stream.written, 1)
+	require.Equal(t, 1, index.Len())
+
+	// a CDR that cannot be written is not recorded
+	stream = &cdrStream{cdrs: file.CdrList[1:2], err: errors.New("disk full")}
+	_, err = filter.FilterStream(stream, stream)
+	require.Error(t, err)
+	require.Equal(t, 1, index.Len())
+}
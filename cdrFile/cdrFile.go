@@ -6,6 +6,7 @@ import (
 	"fmt"
 	// "os"
 	"io/ioutil"
+	"time"
 )
 
 type CDRFile struct {
@@ -60,6 +61,25 @@ type CdrHdrTimeStamp struct {
 	MinuteDeviation 						uint8
 }
 
+// NewCdrHdrTimeStamp returns the time stamp of t in its zone, to the minute.
+func NewCdrHdrTimeStamp(t time.Time) CdrHdrTimeStamp {
+	_, offset := t.Zone()
+	var sign uint8 = 1
+	if offset < 0 {
+		sign = 0
+		offset = -offset
+	}
+	return CdrHdrTimeStamp{
+		MonthLocal:                            uint8(t.Month()),
+		DateLocal:                             uint8(t.Day()),
+		HourLocal:                             uint8(t.Hour()),
+		MinuteLocal:                           uint8(t.Minute()),
+		SignOfTheLocalTimeDifferentialFromUtc: sign,
+		HourDeviation:                         uint8(offset / 3600),
+		MinuteDeviation:                       uint8(offset / 60 % 60),
+	}
+}
+
 type FileClosureTriggerReasonType uint8
 
 const (
@@ -126,6 +146,21 @@ const (
 	TS28202 TsNumberIdentifier = 24
 )
 
+// SetLostCdrs sets the LostCdrIndicator for n CDRs lost before the file,
+// TS 32.297: bit 8 flags the loss and bits 1..7 count the lost CDRs, 127
+// standing for 127 or more. A negative n is an unknown number of lost CDRs,
+// counted as 127.
+func (cdrf *CdrFileHeader) SetLostCdrs(n int) {
+	switch {
+	case n == 0:
+		cdrf.LostCdrIndicator = 0
+	case n < 0 || n > 127:
+		cdrf.LostCdrIndicator = 0x80 | 127
+	default:
+		cdrf.LostCdrIndicator = 0x80 | uint8(n)
+	}
+}
+
 func (cdrf CdrFileHeader) Encoding() []byte{
 	buf := new(bytes.Buffer)
 
@@ -345,7 +380,8 @@ func (header CdrHeader) Encoding() []byte{
 	return buf.Bytes()
 }
 
-func (cdfFile CDRFile) Encoding(fileName string) {
+// Bytes returns the encoding of the file written by Encoding.
+func (cdfFile CDRFile) Bytes() []byte {
 	buf := new(bytes.Buffer)
 
 	// Cdr File Header
@@ -377,7 +413,11 @@ func (cdfFile CDRFile) Encoding(fileName string) {
 	}
 
 	// fmt.Printf("Encoded: %b\n", buf.Bytes())
-	err := ioutil.WriteFile(fileName, buf.Bytes(), 0666) 
+	return buf.Bytes()
+}
+
+func (cdfFile CDRFile) Encoding(fileName string) {
+	err := ioutil.WriteFile(fileName, cdfFile.Bytes(), 0666) 
 	if err != nil {
 		panic(err)
 	}
@@ -131,3 +131,24 @@ func TestCdrFile(t *testing.T) {
 		})
 	}
 }
+
+func TestSetLostCdrs(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		lost      int
+		indicator uint8
+	}{
+		{0, 0},
+		{1, 0x81},
+		{127, 0xff},
+		{300, 0xff},
+		{-1, 0xff},
+	}
+
+	for _, tc := range testCases {
+		var hdr CdrFileHeader
+		hdr.SetLostCdrs(tc.lost)
+		require.Equal(t, tc.indicator, hdr.LostCdrIndicator, "%d lost", tc.lost)
+	}
+}
@@ -0,0 +1,237 @@
+package main
+
+import (
+	"archive/zip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"unicode"
+
+	"github.com/stretchr/testify/require"
+)
+
+const cdrTypeDir = "../cdrType"
+
+// TestGolden regenerates the cdrType package from the modules in asn1 and
+// compares it to the files in the tree. Hand-written files start with a
+// lower-case letter.
+func TestGolden(t *testing.T) {
+	t.Parallel()
+
+	asnFiles, err := filepath.Glob("asn1/*.asn")
+	require.NoError(t, err)
+	files, err := generateFiles(asnFiles, "cdrType")
+	require.NoError(t, err)
+
+	entries, err := os.ReadDir(cdrTypeDir)
+	require.NoError(t, err)
+	existing := 0
+	for _, e := range entries {
+		name := e.Name()
+		if !strings.HasSuffix(name, ".go") || !unicode.IsUpper(rune(name[0])) {
+			continue
+		}
+		existing++
+		want, err := os.ReadFile(filepath.Join(cdrTypeDir, name))
+		require.NoError(t, err)
+		got, ok := files[name]
+		require.True(t, ok, "%s is not generated", name)
+		require.Equal(t, string(want), string(got), name)
+	}
+	require.Equal(t, existing, len(files))
+}
+
+func TestParseModule(t *testing.T) {
+	t.Parallel()
+
+	m, err := parseModule(`
+Test {itu-t(0) test(1)} DEFINITIONS IMPLICIT TAGS ::=
+BEGIN
+maxLength INTEGER ::= 8 -- value assignments are skipped
+Flag ::= ENUMERATED { off (0), on, ..., auto (5) }
+Level ::= INTEGER { low (-1), high (10) } (-1..10)
+List ::= SEQUENCE SIZE (1..maxLength) OF Flag
+Rec ::= SET
+{
+	id [0] OCTET STRING (SIZE(4)),
+	count [1] EXPLICIT INTEGER (0..255) OPTIONAL, -- inline -- flag [2] Flag DEFAULT on,
+	...
+}
+END
+`)
+	require.NoError(t, err)
+	require.Len(t, m.assignments, 4)
+
+	flag := m.assignments[0].typ
+	require.Equal(t, []enumItem{{"off", 0}, {"on", 1}, {"auto", 5}}, flag.items)
+	level := m.assignments[1].typ
+	require.Equal(t, kindPrimitive, level.kind)
+	require.Equal(t, []enumItem{{"low", -1}, {"high", 10}}, level.items)
+	require.Equal(t, kindSequenceOf, m.assignments[2].typ.kind)
+
+	files, err := generate(m, "test", nil)
+	require.NoError(t, err)
+	require.Equal(t, "package test\n\n"+asnImport+aperComment+"\n"+
+		"type Rec struct {\t/* Set Type */\n"+
+		"\tId\tasn.OctetString `ber:\"tagNum:0\"`\n"+
+		"\tCount\t*int64 `ber:\"tagNum:1,explicit,optional\"`\n"+
+		"\tFlag\t*Flag `ber:\"tagNum:2,optional,default:on\"`\n"+
+		"}\n\n", string(files["Rec.go"]))
+
+	for _, src := range []string{
+		"T DEFINITIONS ::= BEGIN A ::= B END",
+		"T DEFINITIONS ::= BEGIN A ::= INTEGER A ::= BOOLEAN END",
+		"T DEFINITIONS ::= BEGIN A ::= INTEGER { a (1), b } END",
+		"T DEFINITIONS ::= BEGIN A ::= ENUMERATED { a (1), b (1) } END",
+		"T DEFINITIONS ::= BEGIN A ::= SEQUENCE { b CHOICE { c INTEGER } } AB ::= INTEGER END",
+		"T DEFINITIONS ::= BEGIN IMPORTS FROM Other; END",
+		"T DEFINITIONS ::= BEGIN IMPORTS B FROM Other; B ::= INTEGER END",
+	} {
+		m, err := parseModule(src)
+		if err == nil {
+			_, err = generate(m, "test", nil)
+		}
+		require.Error(t, err, src)
+	}
+}
+
+func TestInlineTypes(t *testing.T) {
+	t.Parallel()
+
+	m, err := parseModule(`
+T DEFINITIONS ::= BEGIN
+A ::= SEQUENCE
+{
+	b [0] CHOICE { c [0] INTEGER, d [1] SEQUENCE { e BOOLEAN } },
+	f [1] SEQUENCE OF ENUMERATED { g (0) } OPTIONAL
+}
+H ::= SET OF SEQUENCE { i INTEGER }
+END
+`)
+	require.NoError(t, err)
+	files, err := generate(m, "test", nil)
+	require.NoError(t, err)
+
+	names := make([]string, 0, len(m.assignments))
+	for _, a := range m.assignments {
+		names = append(names, a.name)
+	}
+	require.Equal(t, []string{"A", "H", "AB", "ABD", "AF", "HMember"}, names)
+	require.Len(t, files, len(names))
+	require.Contains(t, string(files["A.go"]), "\tB\tAB `ber:\"tagNum:0\"`\n")
+	require.Contains(t, string(files["A.go"]), "\tF []AF `ber:\"tagNum:1,optional\"`\n")
+	require.Contains(t, string(files["AB.go"]), "\tD\t*ABD `ber:\"tagNum:1\"`\n")
+	require.Contains(t, string(files["H.go"]), "\tList []HMember \n")
+	require.Contains(t, string(files["AF.go"]), "\tAFPresentG\tasn.Enumerated = 0\n")
+}
+
+func TestImports(t *testing.T) {
+	t.Parallel()
+
+	parse := func(src string) *module {
+		m, err := parseModule(src)
+		require.NoError(t, err, src)
+		return m
+	}
+	other := parse(`Other DEFINITIONS ::= BEGIN
+EXPORTS Flag, Rec, Size;
+Flag ::= BOOLEAN
+Rec ::= SEQUENCE { size Size }
+Size ::= INTEGER
+Hidden ::= INTEGER
+END`)
+	require.Equal(t, []string{"Flag", "Rec", "Size"}, other.exports)
+	all := parse("All DEFINITIONS ::= BEGIN EXPORTS ALL; Any ::= NULL END")
+	require.Nil(t, all.exports)
+
+	m := parse(`T DEFINITIONS ::= BEGIN
+IMPORTS
+	Flag, Rec, maxSize FROM Other {itu-t (0) other (1)}
+	Any FROM All WITH SUCCESSORS;
+A ::= SEQUENCE { flag Flag, rec Rec OPTIONAL, any [0] Any } (SIZE (1..maxSize))
+END`)
+	require.Equal(t, map[string]string{"Flag": "Other", "Rec": "Other", "maxSize": "Other", "Any": "All"},
+		m.imports)
+	modules := map[string]*module{"T": m, "Other": other, "All": all}
+	files, err := generate(m, "test", modules)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	require.Contains(t, string(files["A.go"]), "\tRec\t*Rec `ber:\"optional\"`\n")
+
+	for _, src := range []string{
+		"T DEFINITIONS ::= BEGIN IMPORTS A FROM Missing; END",
+		"T DEFINITIONS ::= BEGIN IMPORTS Hidden FROM Other; END",
+		"T DEFINITIONS ::= BEGIN IMPORTS Undefined FROM All; END",
+	} {
+		_, err := generate(parse(src), "test", modules)
+		require.Error(t, err, src)
+	}
+}
+
+func TestVarName(t *testing.T) {
+	t.Parallel()
+
+	for in, out := range map[string]string{
+		"RATType":            "ratType",
+		"CauseForRecClosing": "causeForRecClosing",
+		"SSCMode":            "sscMode",
+		"APN":                "apn",
+	} {
+		require.Equal(t, out, varName(in), in)
+	}
+}
+
+func TestExtract(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	spec := filepath.Join(dir, "32298-x00.docx")
+	f, err := os.Create(spec)
+	require.NoError(t, err)
+	zw := zip.NewWriter(f)
+	w, err := zw.Create("word/document.xml")
+	require.NoError(t, err)
+	paragraphs := []string{
+		"5.2.5\tASN.1 definitions",
+		"TestDataTypes {itu-t (0) identified-organization (4)} DEFINITIONS IMPLICIT TAGS ::=",
+		"BEGIN",
+		"Counter ::= INTEGER (0..4294967295)",
+		"END",
+		"Some text between the modules.",
+		"OtherDataTypes DEFINITIONS IMPLICIT TAGS ::=",
+		"BEGIN",
+		"Flag ::= BOOLEAN",
+		"END",
+	}
+	_, err = io.WriteString(w, `<?xml version="1.0"?><w:document xmlns:w="w"><w:body>`)
+	require.NoError(t, err)
+	for _, p := range paragraphs {
+		runs := strings.ReplaceAll(p, "\t", "</w:t><w:tab/><w:t>")
+		_, err = io.WriteString(w, "<w:p><w:r><w:t>"+runs+"</w:t></w:r></w:p>")
+		require.NoError(t, err)
+	}
+	_, err = io.WriteString(w, `</w:body></w:document>`)
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+	require.NoError(t, f.Close())
+
+	names, err := extract(spec, dir)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		filepath.Join(dir, "TestDataTypes.asn"),
+		filepath.Join(dir, "OtherDataTypes.asn"),
+	}, names)
+
+	src, err := os.ReadFile(names[0])
+	require.NoError(t, err)
+	require.Equal(t, "-- TestDataTypes, extracted from 32298-x00.docx by cdrGen -extract.\n\n"+
+		strings.Join(paragraphs[1:5], "\n")+"\n", string(src))
+	m, err := parseModule(string(src))
+	require.NoError(t, err)
+	require.Equal(t, "TestDataTypes", m.name)
+
+	_, err = extractModules("Broken DEFINITIONS ::=\nBEGIN\n")
+	require.Error(t, err)
+}
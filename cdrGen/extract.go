@@ -0,0 +1,143 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// moduleStart matches the first line of an ASN.1 module definition.
+var moduleStart = regexp.MustCompile(`^([A-Z][A-Za-z0-9-]*)\s*(\{.*\})?\s*DEFINITIONS\b`)
+
+// extract writes the ASN.1 modules found in the specification document spec,
+// a .docx file as published in the 3GPP archive, to dir. Each module is
+// written to a file named after it and headed by the name of the document, so
+// that the release the modules come from stays recorded next to them.
+func extract(spec, dir string) ([]string, error) {
+	text, err := docxText(spec)
+	if err != nil {
+		return nil, err
+	}
+	modules, err := extractModules(text)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", spec, err)
+	}
+	if len(modules) == 0 {
+		return nil, fmt.Errorf("%s: no ASN.1 module found", spec)
+	}
+
+	names := make([]string, 0, len(modules))
+	for _, m := range modules {
+		header := fmt.Sprintf("-- %s, extracted from %s by cdrGen -extract.\n\n",
+			m.name, filepath.Base(spec))
+		name := filepath.Join(dir, m.name+".asn")
+		if err := os.WriteFile(name, []byte(header+m.src), 0o644); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+type moduleText struct {
+	name string
+	src  string
+}
+
+// extractModules returns the modules of text, one line per paragraph, in the
+// order they appear. A module runs from its DEFINITIONS line to the first line
+// reading END.
+func extractModules(text string) ([]moduleText, error) {
+	var modules []moduleText
+	var cur *moduleText
+	var b strings.Builder
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimRight(line, " \t")
+		if cur == nil {
+			if m := moduleStart.FindStringSubmatch(line); m != nil {
+				cur = &moduleText{name: m[1]}
+				b.Reset()
+			} else {
+				continue
+			}
+		}
+		b.WriteString(line)
+		b.WriteByte('\n')
+		if strings.TrimSpace(line) == "END" {
+			cur.src = b.String()
+			modules = append(modules, *cur)
+			cur = nil
+		}
+	}
+	if cur != nil {
+		return nil, fmt.Errorf("module %s has no END", cur.name)
+	}
+	return modules, nil
+}
+
+// docxText returns the text of the body of a .docx document, one line per
+// paragraph, with tabs and line breaks kept.
+func docxText(name string) (string, error) {
+	r, err := zip.OpenReader(name)
+	if err != nil {
+		return "", err
+	}
+	defer r.Close()
+
+	for _, f := range r.File {
+		if f.Name != "word/document.xml" {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return "", err
+		}
+		defer rc.Close()
+		return documentText(rc)
+	}
+	return "", fmt.Errorf("%s: not a .docx document", name)
+}
+
+// documentText converts the WordprocessingML of r to plain text.
+func documentText(r io.Reader) (string, error) {
+	var buf bytes.Buffer
+	d := xml.NewDecoder(r)
+	inText := false
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			return buf.String(), nil
+		}
+		if err != nil {
+			return "", err
+		}
+		switch tok := tok.(type) {
+		case xml.StartElement:
+			switch tok.Name.Local {
+			case "t":
+				inText = true
+			case "tab":
+				buf.WriteByte('\t')
+			case "br", "cr":
+				buf.WriteByte('\n')
+			}
+		case xml.EndElement:
+			switch tok.Name.Local {
+			case "t":
+				inText = false
+			case "p":
+				buf.WriteByte('\n')
+			}
+		case xml.CharData:
+			if inText {
+				buf.Write(tok)
+			}
+		}
+	}
+}
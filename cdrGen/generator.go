@@ -0,0 +1,379 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// The generated files keep the layout of the files this package was first
+// generated with, so that regenerating does not touch unchanged types.
+const (
+	asnImport   = "import \"github.com/free5gc/CDRUtil/asn\"\n"
+	aperComment = "// Need to import \"gofree5gc/lib/aper\" if it uses \"aper\"\n"
+)
+
+var goPrimitives = map[string]string{
+	"INTEGER":           "int64",
+	"BOOLEAN":           "bool",
+	"NULL":              "asn.NULL",
+	"OCTET STRING":      "asn.OctetString",
+	"BIT STRING":        "asn.BitString",
+	"OBJECT IDENTIFIER": "asn.ObjectIdentifier",
+	"IA5String":         "asn.IA5String",
+	"UTF8String":        "asn.UTF8String",
+	"GraphicString":     "asn.GraphicString",
+}
+
+// goName converts an ASN.1 type, component or item name into an exported Go
+// identifier: hyphens are dropped and the first letter is upper-cased.
+func goName(name string) string {
+	name = strings.ReplaceAll(name, "-", "")
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+// generate returns the Go source of every type of the module, keyed by file
+// name. Imported types are looked up in modules, keyed by module name; they
+// are generated with their own module.
+func generate(m *module, pkg string, modules map[string]*module) (map[string][]byte, error) {
+	if err := hoist(m); err != nil {
+		return nil, err
+	}
+	defined := make(map[string]bool)
+	for _, a := range m.assignments {
+		defined[a.name] = true
+	}
+	for symbol, from := range m.imports {
+		if !unicode.IsUpper(rune(symbol[0])) {
+			continue // value reference, only used in constraints
+		}
+		if err := modules[from].exportsType(symbol); err != nil {
+			return nil, fmt.Errorf("%s imported from %s: %v", symbol, from, err)
+		}
+		defined[symbol] = true
+	}
+
+	files := make(map[string][]byte)
+	for _, a := range m.assignments {
+		body, err := generateType(a, defined)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", a.name, err)
+		}
+		var b strings.Builder
+		fmt.Fprintf(&b, "package %s\n\n", pkg)
+		if strings.Contains(body, "asn.") {
+			b.WriteString(asnImport)
+		}
+		b.WriteString(aperComment)
+		b.WriteString("\n")
+		b.WriteString(body)
+		files[goName(a.name)+".go"] = []byte(b.String())
+	}
+	return files, nil
+}
+
+// exportsType checks that the module defines and exports the type name.
+func (m *module) exportsType(name string) error {
+	if m == nil {
+		return fmt.Errorf("module not given")
+	}
+	exported := m.exports == nil
+	for _, export := range m.exports {
+		exported = exported || export == name
+	}
+	if !exported {
+		return fmt.Errorf("not exported")
+	}
+	for _, a := range m.assignments {
+		if a.name == name {
+			return nil
+		}
+	}
+	if _, ok := m.imports[name]; ok {
+		return nil // re-exported, resolved with the importing module
+	}
+	return fmt.Errorf("undefined type %s", name)
+}
+
+// hoist replaces the constructed types written inline in components and
+// collection elements with references to new assignments, named after the
+// enclosing type and the component: the CHOICE of component b of A becomes
+// type AB, the element of a SEQUENCE OF A becomes AMember.
+func hoist(m *module) error {
+	names := make(map[string]bool)
+	for _, a := range m.assignments {
+		names[goName(a.name)] = true
+	}
+	var hoisted []*assignment
+	var hoistType func(name string, t *asnType) error
+	named := func(name string, t *asnType) (*asnType, error) {
+		if names[name] {
+			return nil, fmt.Errorf("inline type %s clashes with a defined type", name)
+		}
+		names[name] = true
+		hoisted = append(hoisted, &assignment{name: name, typ: t})
+		return &asnType{kind: kindReference, name: name}, hoistType(name, t)
+	}
+	hoistType = func(name string, t *asnType) error {
+		var err error
+		switch t.kind {
+		case kindSequence, kindSet, kindChoice:
+			for _, f := range t.fields {
+				switch {
+				case isCollection(f.typ):
+					if isConstructed(f.typ.elem) {
+						f.typ.elem, err = named(name+goName(f.name), f.typ.elem)
+					}
+				case isConstructed(f.typ):
+					f.typ, err = named(name+goName(f.name), f.typ)
+				}
+				if err != nil {
+					return err
+				}
+			}
+		case kindSequenceOf, kindSetOf:
+			if isConstructed(t.elem) {
+				t.elem, err = named(name+"Member", t.elem)
+			}
+		}
+		return err
+	}
+
+	for _, a := range m.assignments {
+		if err := hoistType(goName(a.name), a.typ); err != nil {
+			return fmt.Errorf("%s: %v", a.name, err)
+		}
+	}
+	m.assignments = append(m.assignments, hoisted...)
+	return nil
+}
+
+func isCollection(t *asnType) bool {
+	return t.kind == kindSequenceOf || t.kind == kindSetOf
+}
+
+// isConstructed reports whether a type written inline needs an assignment of
+// its own to be generated.
+func isConstructed(t *asnType) bool {
+	switch t.kind {
+	case kindEnumerated, kindChoice, kindSequence, kindSet, kindSequenceOf, kindSetOf:
+		return true
+	}
+	return false
+}
+
+func generateType(a *assignment, defined map[string]bool) (string, error) {
+	name := goName(a.name)
+	var b strings.Builder
+
+	switch t := a.typ; t.kind {
+	case kindPrimitive:
+		if len(t.items) > 0 {
+			b.WriteString("const (\t/* Named Numbers */\n")
+			for _, item := range t.items {
+				fmt.Fprintf(&b, "\t%sPresent%s\tint64 = %d\n", name, goName(item.name), item.value)
+			}
+			b.WriteString(")\n\n")
+		}
+		fmt.Fprintf(&b, "type %s struct {\n\tValue\t%s \n}\n\n", name, goPrimitives[t.name])
+		if len(t.items) > 0 {
+			writeEnumMethods(&b, name, "int64", t.items)
+		}
+	case kindReference:
+		if !defined[t.name] {
+			return "", fmt.Errorf("undefined type %s", t.name)
+		}
+		fmt.Fprintf(&b, "// Open type declare\ntype %s struct {\n\tValue %s\n}\n", name, goName(t.name))
+	case kindEnumerated:
+		b.WriteString("const (\t/* Enum Type */\n")
+		for _, item := range t.items {
+			fmt.Fprintf(&b, "\t%sPresent%s\tasn.Enumerated = %d\n", name, goName(item.name), item.value)
+		}
+		fmt.Fprintf(&b, ")\n\ntype %s struct {\n\tValue\tasn.Enumerated \n}\n\n", name)
+		writeEnumMethods(&b, name, "asn.Enumerated", t.items)
+	case kindChoice:
+		fmt.Fprintf(&b, "const (\n\t%sPresentNothing\tint = iota\t/* No components present */\n", name)
+		for _, f := range t.fields {
+			fmt.Fprintf(&b, "\t%sPresent%s\n", name, goName(f.name))
+		}
+		fmt.Fprintf(&b, ")\n\ntype %s struct {\n\tPresent\tint\t/* Choice Type */\n", name)
+		for _, f := range t.fields {
+			goType, err := fieldType(f.typ, defined)
+			if err != nil {
+				return "", fmt.Errorf("%s: %v", f.name, err)
+			}
+			if isCollection(f.typ) {
+				return "", fmt.Errorf("%s: collection alternatives are not supported", f.name)
+			}
+			fmt.Fprintf(&b, "\t%s\t*%s %s\n", goName(f.name), goType, berTag(f))
+		}
+		b.WriteString("}\n\n")
+		if err := writeChoiceMethods(&b, name, t.fields, defined); err != nil {
+			return "", err
+		}
+	case kindSequence, kindSet:
+		kind := "Sequence"
+		if t.kind == kindSet {
+			kind = "Set"
+		}
+		fmt.Fprintf(&b, "type %s struct {\t/* %s Type */\n", name, kind)
+		var openTypes []string
+		for _, f := range t.fields {
+			fieldName := goName(f.name)
+			switch f.typ.kind {
+			case kindSequenceOf, kindSetOf:
+				elem, err := fieldType(f.typ.elem, defined)
+				if err != nil {
+					return "", fmt.Errorf("%s: %v", f.name, err)
+				}
+				fmt.Fprintf(&b, "\t/* %s, FULL Name = struct %s__%s */\n\t/* %s */\n\t%s []%s %s\n",
+					collectionComment(f.typ.kind), name, f.name, elem, fieldName, elem, berTag(f))
+			case kindAny:
+				openType := name + fieldName
+				fmt.Fprintf(&b, "\t%s\t%s %s\n", fieldName, openType, berTag(f))
+				openTypes = append(openTypes, openType)
+			default:
+				goType, err := fieldType(f.typ, defined)
+				if err != nil {
+					return "", fmt.Errorf("%s: %v", f.name, err)
+				}
+				if f.optional {
+					goType = "*" + goType
+				}
+				fmt.Fprintf(&b, "\t%s\t%s %s\n", fieldName, goType, berTag(f))
+			}
+		}
+		b.WriteString("}\n\n")
+		for _, openType := range openTypes {
+			fmt.Fprintf(&b, "const (\n\t%sPresentNothing\tint = iota\t/* No components present */\n)\n\n", name)
+			fmt.Fprintf(&b, "type %s struct {\n\tPresent\tint\t/* Open Type */\n}\n\n", openType)
+		}
+	case kindSequenceOf, kindSetOf:
+		elem, err := fieldType(t.elem, defined)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&b, "/* %s, FULL Name = struct %s */\n/* %s */\ntype %s struct {\n\tList []%s \n}\n",
+			collectionComment(t.kind), name, elem, name, elem)
+	default:
+		return "", fmt.Errorf("unsupported type")
+	}
+	return b.String(), nil
+}
+
+// writeEnumMethods writes the name table and the String, IsValid and Parse
+// functions of an enumeration or an INTEGER with named numbers. The names are
+// the ASN.1 identifiers.
+func writeEnumMethods(b *strings.Builder, name, valueType string, items []enumItem) {
+	names := varName(name) + "Names"
+	fmt.Fprintf(b, "var %s = map[%s]string{\n", names, valueType)
+	for _, item := range items {
+		fmt.Fprintf(b, "\t%sPresent%s:\t%q,\n", name, goName(item.name), item.name)
+	}
+	b.WriteString("}\n\n")
+
+	fmt.Fprintf(b, "func (v %s) String() string {\n", name)
+	fmt.Fprintf(b, "\tif s, ok := %s[v.Value]; ok {\n\t\treturn s\n\t}\n", names)
+	fmt.Fprintf(b, "\treturn unknownEnumString(%q, int64(v.Value))\n}\n\n", name)
+
+	b.WriteString("// IsValid reports whether the value is one of the named values.\n")
+	fmt.Fprintf(b, "func (v %s) IsValid() bool {\n", name)
+	fmt.Fprintf(b, "\t_, ok := %s[v.Value]\n\treturn ok\n}\n\n", names)
+
+	fmt.Fprintf(b, "func Parse%s(s string) (%s, error) {\n", name, name)
+	fmt.Fprintf(b, "\tfor value, name := range %s {\n", names)
+	fmt.Fprintf(b, "\t\tif name == s {\n\t\t\treturn %s{Value: value}, nil\n\t\t}\n\t}\n", name)
+	fmt.Fprintf(b, "\treturn %s{}, unknownEnumError(%q, s)\n}\n\n", name, name)
+}
+
+// writeChoiceMethods writes a constructor per alternative and the Value and
+// Validate methods of a CHOICE.
+func writeChoiceMethods(b *strings.Builder, name string, fields []*asnField, defined map[string]bool) error {
+	for _, f := range fields {
+		goType, err := fieldType(f.typ, defined)
+		if err != nil {
+			return err
+		}
+		alt := goName(f.name)
+		fmt.Fprintf(b, "func New%s%s(v %s) %s {\n", name, alt, goType, name)
+		fmt.Fprintf(b, "\treturn %s{Present: %sPresent%s, %s: &v}\n}\n\n", name, name, alt, alt)
+	}
+
+	// A nil pointer is not returned as such: wrapped in the interface it
+	// would not compare equal to nil.
+	b.WriteString("// Value returns the alternative selected by Present, nil if none is or\n")
+	b.WriteString("// the selected one is not set.\n")
+	fmt.Fprintf(b, "func (c %s) Value() interface{} {\n\tswitch c.Present {\n", name)
+	for _, f := range fields {
+		alt := goName(f.name)
+		fmt.Fprintf(b, "\tcase %sPresent%s:\n\t\tif c.%s != nil {\n\t\t\treturn c.%s\n\t\t}\n", name, alt, alt, alt)
+	}
+	b.WriteString("\t}\n\treturn nil\n}\n\n")
+
+	b.WriteString("// Validate checks that exactly the alternative selected by Present is set.\n")
+	fmt.Fprintf(b, "func (c %s) Validate() error {\n", name)
+	fmt.Fprintf(b, "\treturn validateChoice(%q, c.Present, []choiceAlternative{\n", name)
+	for _, f := range fields {
+		alt := goName(f.name)
+		fmt.Fprintf(b, "\t\t{%q, c.%s != nil},\n", alt, alt)
+	}
+	b.WriteString("\t})\n}\n\n")
+	return nil
+}
+
+// varName converts an exported Go name into an unexported one, lowering a
+// leading initialism as a whole: RATType becomes ratType.
+func varName(name string) string {
+	upper := 0
+	for upper < len(name) && name[upper] >= 'A' && name[upper] <= 'Z' {
+		upper++
+	}
+	if upper > 1 && upper < len(name) {
+		upper--
+	}
+	return strings.ToLower(name[:upper]) + name[upper:]
+}
+
+// fieldType returns the Go type of a component. Constructed types have been
+// hoisted into assignments of their own.
+func fieldType(t *asnType, defined map[string]bool) (string, error) {
+	switch t.kind {
+	case kindPrimitive:
+		return goPrimitives[t.name], nil
+	case kindReference:
+		if !defined[t.name] {
+			return "", fmt.Errorf("undefined type %s", t.name)
+		}
+		return goName(t.name), nil
+	default:
+		return "", fmt.Errorf("inline constructed types are not supported")
+	}
+}
+
+func collectionComment(kind typeKind) string {
+	if kind == kindSetOf {
+		return "Set of = 36"
+	}
+	return "Sequence of = 35"
+}
+
+// berTag returns the struct tag read by the asn package.
+func berTag(f *asnField) string {
+	var params []string
+	if f.tag >= 0 {
+		params = append(params, fmt.Sprintf("tagNum:%d", f.tag))
+	}
+	if f.explicit {
+		params = append(params, "explicit")
+	}
+	if f.optional {
+		params = append(params, "optional")
+	}
+	if f.defaultValue != "" {
+		params = append(params, "default:"+f.defaultValue)
+	}
+	if len(params) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("`ber:\"%s\"`", strings.Join(params, ","))
+}
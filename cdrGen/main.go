@@ -0,0 +1,107 @@
+// cdrGen generates the cdrType package from the ASN.1 definitions of
+// TS 32.298. Every type assignment of the modules becomes one Go file named
+// after the type; files not generated from the modules are left alone. The
+// modules a module IMPORTS from must be given as well.
+//
+// Usage:
+//
+//	go run ./cdrGen -out cdrType cdrGen/asn1/*.asn
+//
+// With -extract, cdrGen instead writes the ASN.1 modules of a specification
+// document from the 3GPP archive to the output directory, to replace the
+// modules in cdrGen/asn1 when moving to another release:
+//
+//	go run ./cdrGen -extract 32298-i30.docx -out cdrGen/asn1
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+func main() {
+	out := flag.String("out", ".", "output directory")
+	pkg := flag.String("pkg", "cdrType", "package name of the generated files")
+	spec := flag.String("extract", "", "extract the ASN.1 modules of a .docx specification")
+	flag.Parse()
+
+	if *spec != "" {
+		names, err := extract(*spec, *out)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "cdrGen:", err)
+			os.Exit(1)
+		}
+		for _, name := range names {
+			fmt.Println(name)
+		}
+		return
+	}
+
+	if flag.NArg() == 0 {
+		fmt.Fprintln(os.Stderr, "usage: cdrGen [-out dir] [-pkg name] module.asn...\n       cdrGen -extract spec.docx [-out dir]")
+		os.Exit(2)
+	}
+	if err := run(flag.Args(), *out, *pkg); err != nil {
+		fmt.Fprintln(os.Stderr, "cdrGen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(asnFiles []string, out, pkg string) error {
+	files, err := generateFiles(asnFiles, pkg)
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := os.WriteFile(filepath.Join(out, name), files[name], 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// generateFiles parses the modules and generates their types. Type names
+// must be unique across the modules; imports are resolved among them.
+func generateFiles(asnFiles []string, pkg string) (map[string][]byte, error) {
+	modules := make(map[string]*module)
+	parsed := make([]*module, 0, len(asnFiles))
+	for _, asnFile := range asnFiles {
+		src, err := os.ReadFile(asnFile)
+		if err != nil {
+			return nil, err
+		}
+		m, err := parseModule(string(src))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", asnFile, err)
+		}
+		if _, ok := modules[m.name]; ok {
+			return nil, fmt.Errorf("%s: module %s given twice", asnFile, m.name)
+		}
+		modules[m.name] = m
+		parsed = append(parsed, m)
+	}
+
+	files := make(map[string][]byte)
+	for i, m := range parsed {
+		generated, err := generate(m, pkg, modules)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", asnFiles[i], err)
+		}
+		for name, content := range generated {
+			if _, ok := files[name]; ok {
+				return nil, fmt.Errorf("%s: %s generated twice", asnFiles[i], name)
+			}
+			files[name] = content
+		}
+	}
+	return files, nil
+}
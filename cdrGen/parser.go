@@ -0,0 +1,518 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+type typeKind int
+
+const (
+	kindPrimitive typeKind = iota
+	kindReference
+	kindEnumerated
+	kindChoice
+	kindSequence
+	kindSet
+	kindSequenceOf
+	kindSetOf
+	kindAny
+)
+
+// asnType is the part of an ASN.1 type the generator cares about. Size and
+// value constraints are parsed and dropped.
+type asnType struct {
+	kind   typeKind
+	name   string // primitive type name or referenced type
+	elem   *asnType
+	fields []*asnField
+	items  []enumItem
+}
+
+type asnField struct {
+	name         string
+	tag          int // -1 if untagged
+	explicit     bool
+	typ          *asnType
+	optional     bool
+	defaultValue string
+}
+
+type enumItem struct {
+	name  string
+	value int
+}
+
+type assignment struct {
+	name string
+	typ  *asnType
+}
+
+type module struct {
+	name        string
+	assignments []*assignment
+	// exports lists the exported symbols; nil exports everything.
+	exports []string
+	// imports maps every imported symbol to the module it comes from.
+	imports map[string]string
+}
+
+type parser struct {
+	tokens []string
+	pos    int
+}
+
+// parseModule parses a single ASN.1 module.
+func parseModule(src string) (*module, error) {
+	p := &parser{tokens: tokenize(src)}
+	m, err := p.module()
+	if err != nil {
+		return nil, fmt.Errorf("%v near token %d %q", err, p.pos, p.peek())
+	}
+	return m, nil
+}
+
+// tokenize splits src into ASN.1 tokens, dropping comments.
+func tokenize(src string) []string {
+	var tokens []string
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case unicode.IsSpace(rune(c)):
+			i++
+		case strings.HasPrefix(src[i:], "--"):
+			// a comment ends at the end of line or at the next "--"
+			end := i + 2
+			for end < len(src) && src[end] != '\n' && !strings.HasPrefix(src[end:], "--") {
+				end++
+			}
+			if strings.HasPrefix(src[end:], "--") {
+				end += 2
+			}
+			i = end
+		case strings.HasPrefix(src[i:], "::="):
+			tokens = append(tokens, "::=")
+			i += 3
+		case strings.HasPrefix(src[i:], "..."):
+			tokens = append(tokens, "...")
+			i += 3
+		case strings.HasPrefix(src[i:], ".."):
+			tokens = append(tokens, "..")
+			i += 2
+		case strings.HasPrefix(src[i:], "[["), strings.HasPrefix(src[i:], "]]"):
+			tokens = append(tokens, src[i:i+2])
+			i += 2
+		case isIdentChar(c):
+			end := i
+			for end < len(src) && (isIdentChar(src[end]) ||
+				src[end] == '-' && end+1 < len(src) && isIdentChar(src[end+1])) {
+				end++
+			}
+			tokens = append(tokens, src[i:end])
+			i = end
+		default:
+			tokens = append(tokens, string(c))
+			i++
+		}
+	}
+	return tokens
+}
+
+func isIdentChar(c byte) bool {
+	return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+func (p *parser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *parser) next() string {
+	t := p.peek()
+	p.pos++
+	return t
+}
+
+func (p *parser) accept(t string) bool {
+	if p.peek() == t {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(t string) error {
+	if !p.accept(t) {
+		return fmt.Errorf("expected %q", t)
+	}
+	return nil
+}
+
+// skipBalanced skips a bracketed group starting at the current open token.
+func (p *parser) skipBalanced(open, close string) error {
+	if err := p.expect(open); err != nil {
+		return err
+	}
+	for depth := 1; depth > 0; {
+		switch p.next() {
+		case open:
+			depth++
+		case close:
+			depth--
+		case "":
+			return fmt.Errorf("unterminated %q", open)
+		}
+	}
+	return nil
+}
+
+func (p *parser) module() (*module, error) {
+	m := &module{name: p.next()}
+	if p.peek() == "{" {
+		if err := p.skipBalanced("{", "}"); err != nil {
+			return nil, err
+		}
+	}
+	if err := p.expect("DEFINITIONS"); err != nil {
+		return nil, err
+	}
+	for p.peek() != "::=" && p.peek() != "" {
+		p.next() // tagging and extensibility defaults
+	}
+	if err := p.expect("::="); err != nil {
+		return nil, err
+	}
+	if err := p.expect("BEGIN"); err != nil {
+		return nil, err
+	}
+	if p.accept("EXPORTS") {
+		if !p.accept("ALL") {
+			exports, err := p.symbols()
+			if err != nil {
+				return nil, fmt.Errorf("EXPORTS: %v", err)
+			}
+			m.exports = append([]string{}, exports...)
+		}
+		if err := p.expect(";"); err != nil {
+			return nil, err
+		}
+	}
+	if p.accept("IMPORTS") {
+		m.imports = make(map[string]string)
+		for !p.accept(";") {
+			symbols, err := p.symbols()
+			if err == nil && len(symbols) == 0 {
+				err = fmt.Errorf("missing symbols")
+			}
+			if err != nil {
+				return nil, fmt.Errorf("IMPORTS: %v", err)
+			}
+			if err := p.expect("FROM"); err != nil {
+				return nil, err
+			}
+			from := p.next()
+			if from == "" || !unicode.IsUpper(rune(from[0])) {
+				return nil, fmt.Errorf("IMPORTS: unexpected module name %q", from)
+			}
+			if p.peek() == "{" { // module OID
+				if err := p.skipBalanced("{", "}"); err != nil {
+					return nil, err
+				}
+			}
+			if p.accept("WITH") {
+				p.next() // SUCCESSORS or DESCENDANTS
+			}
+			for _, symbol := range symbols {
+				if _, ok := m.imports[symbol]; ok {
+					return nil, fmt.Errorf("IMPORTS: %s imported twice", symbol)
+				}
+				m.imports[symbol] = from
+			}
+		}
+	}
+
+	seen := make(map[string]bool)
+	for !p.accept("END") {
+		name := p.next()
+		if name == "" {
+			return nil, fmt.Errorf("missing END")
+		}
+		if !unicode.IsUpper(rune(name[0])) {
+			// value assignment, e.g. "maxAddressLength INTEGER ::= 20"
+			if _, err := p.typ(); err != nil {
+				return nil, err
+			}
+			if err := p.expect("::="); err != nil {
+				return nil, err
+			}
+			if err := p.skipValue(); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if err := p.expect("::="); err != nil {
+			return nil, err
+		}
+		t, err := p.typ()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", name, err)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("%s: defined twice", name)
+		}
+		if _, ok := m.imports[name]; ok {
+			return nil, fmt.Errorf("%s: defined and imported", name)
+		}
+		seen[name] = true
+		m.assignments = append(m.assignments, &assignment{name: name, typ: t})
+	}
+	return m, nil
+}
+
+// symbols parses a comma separated symbol list of an EXPORTS or IMPORTS
+// clause. It stops before FROM, ";" or any other token that does not follow
+// a comma.
+func (p *parser) symbols() ([]string, error) {
+	var symbols []string
+	for {
+		symbol := p.peek()
+		if symbol == "FROM" || symbol == ";" || symbol == "" {
+			if len(symbols) > 0 {
+				return nil, fmt.Errorf("missing symbol after \",\"")
+			}
+			return symbols, nil
+		}
+		p.next()
+		if p.peek() == "{" { // parameterized reference
+			if err := p.skipBalanced("{", "}"); err != nil {
+				return nil, err
+			}
+		}
+		symbols = append(symbols, symbol)
+		if !p.accept(",") {
+			return symbols, nil
+		}
+	}
+}
+
+func (p *parser) skipValue() error {
+	if p.peek() == "{" {
+		return p.skipBalanced("{", "}")
+	}
+	p.accept("-")
+	if p.next() == "" {
+		return fmt.Errorf("missing value")
+	}
+	return nil
+}
+
+// skipConstraints drops any number of parenthesized constraints.
+func (p *parser) skipConstraints() error {
+	for p.peek() == "(" {
+		if err := p.skipBalanced("(", ")"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *parser) typ() (*asnType, error) {
+	var t *asnType
+	switch tok := p.next(); tok {
+	case "INTEGER":
+		t = &asnType{kind: kindPrimitive, name: tok}
+		if p.peek() == "{" {
+			items, err := p.enumItems(true)
+			if err != nil {
+				return nil, err
+			}
+			t.items = items
+		}
+	case "BIT":
+		if err := p.expect("STRING"); err != nil {
+			return nil, err
+		}
+		if p.peek() == "{" { // named bits
+			if err := p.skipBalanced("{", "}"); err != nil {
+				return nil, err
+			}
+		}
+		t = &asnType{kind: kindPrimitive, name: "BIT STRING"}
+	case "OCTET", "OBJECT":
+		second := map[string]string{"OCTET": "STRING", "OBJECT": "IDENTIFIER"}[tok]
+		if err := p.expect(second); err != nil {
+			return nil, err
+		}
+		t = &asnType{kind: kindPrimitive, name: tok + " " + second}
+	case "BOOLEAN", "NULL", "IA5String", "UTF8String", "GraphicString":
+		t = &asnType{kind: kindPrimitive, name: tok}
+	case "ENUMERATED":
+		items, err := p.enumItems(false)
+		if err != nil {
+			return nil, err
+		}
+		t = &asnType{kind: kindEnumerated, items: items}
+	case "CHOICE", "SEQUENCE", "SET":
+		kind := map[string]typeKind{"CHOICE": kindChoice, "SEQUENCE": kindSequence, "SET": kindSet}[tok]
+		if tok != "CHOICE" {
+			if err := p.skipConstraints(); err != nil {
+				return nil, err
+			}
+			if p.peek() == "SIZE" {
+				p.next()
+				if err := p.skipConstraints(); err != nil {
+					return nil, err
+				}
+			}
+			if p.accept("OF") {
+				elem, err := p.typ()
+				if err != nil {
+					return nil, err
+				}
+				kind = map[string]typeKind{"SEQUENCE": kindSequenceOf, "SET": kindSetOf}[tok]
+				return &asnType{kind: kind, elem: elem}, nil
+			}
+		}
+		fields, err := p.fields(tok == "CHOICE")
+		if err != nil {
+			return nil, err
+		}
+		t = &asnType{kind: kind, fields: fields}
+	case "ANY":
+		if err := p.expect("DEFINED"); err != nil {
+			return nil, err
+		}
+		if err := p.expect("BY"); err != nil {
+			return nil, err
+		}
+		t = &asnType{kind: kindAny, name: p.next()}
+	default:
+		if tok == "" || !unicode.IsUpper(rune(tok[0])) {
+			return nil, fmt.Errorf("unexpected %q", tok)
+		}
+		if p.peek() == "." { // Module.Type
+			p.next()
+			tok = p.next()
+		}
+		t = &asnType{kind: kindReference, name: tok}
+	}
+	if err := p.skipConstraints(); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// enumItems parses the item list of an ENUMERATED or the named numbers of an
+// INTEGER. Enumeration items without a number take the smallest values not
+// used by the numbered ones; named numbers always carry a value.
+func (p *parser) enumItems(namedNumbers bool) ([]enumItem, error) {
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+	var items []enumItem
+	var unnumbered []int
+	numbered := make(map[int]bool)
+	for !p.accept("}") {
+		if p.accept(",") || p.accept("...") {
+			continue
+		}
+		item := enumItem{name: p.next()}
+		if item.name == "" {
+			return nil, fmt.Errorf("unterminated item list")
+		}
+		if p.accept("(") {
+			v, err := p.number()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			if numbered[v] {
+				return nil, fmt.Errorf("value %d used twice", v)
+			}
+			item.value = v
+			numbered[v] = true
+		} else if namedNumbers {
+			return nil, fmt.Errorf("named number %s without value", item.name)
+		} else {
+			unnumbered = append(unnumbered, len(items))
+		}
+		items = append(items, item)
+	}
+	next := 0
+	for _, i := range unnumbered {
+		for numbered[next] {
+			next++
+		}
+		items[i].value = next
+		numbered[next] = true
+	}
+	return items, nil
+}
+
+func (p *parser) number() (int, error) {
+	tok := p.next()
+	if tok == "-" {
+		tok += p.next()
+	}
+	return strconv.Atoi(tok)
+}
+
+func (p *parser) fields(choice bool) ([]*asnField, error) {
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+	var fields []*asnField
+	for !p.accept("}") {
+		if p.accept(",") || p.accept("...") || p.accept("[[") || p.accept("]]") {
+			continue
+		}
+		if p.peek() == "!" { // exception identification
+			p.next()
+			p.next()
+			continue
+		}
+		f := &asnField{name: p.next(), tag: -1}
+		if f.name == "" || !unicode.IsLower(rune(f.name[0])) {
+			return nil, fmt.Errorf("unexpected %q in component list", f.name)
+		}
+		if p.accept("[") {
+			if p.peek() == "APPLICATION" || p.peek() == "PRIVATE" || p.peek() == "UNIVERSAL" {
+				return nil, fmt.Errorf("%s: only context-specific tags are supported", f.name)
+			}
+			v, err := p.number()
+			if err != nil {
+				return nil, fmt.Errorf("%s: %v", f.name, err)
+			}
+			if err := p.expect("]"); err != nil {
+				return nil, err
+			}
+			f.tag = v
+			if p.accept("EXPLICIT") {
+				f.explicit = true
+			} else {
+				p.accept("IMPLICIT")
+			}
+		}
+		t, err := p.typ()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", f.name, err)
+		}
+		f.typ = t
+		if !choice {
+			if p.accept("OPTIONAL") {
+				f.optional = true
+			} else if p.accept("DEFAULT") {
+				f.optional = true
+				f.defaultValue = p.next()
+			}
+		}
+		fields = append(fields, f)
+	}
+	return fields, nil
+}
@@ -0,0 +1,329 @@
+// Package cdrPull lets the billing domain pull the closed CDR files of a
+// directory over HTTP, the pull mode of the Bx interface in TS 32.297.
+package cdrPull
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/free5gc/CDRUtil/cdrFile"
+	"github.com/free5gc/CDRUtil/cdrSink"
+	"github.com/free5gc/CDRUtil/internal/safeFile"
+)
+
+// ErrChecksumMismatch is returned by Archive when the file is not the one
+// acknowledged.
+var ErrChecksumMismatch = errors.New("checksum mismatch")
+
+// ErrArchived is returned by Archive when another file of the same name is in
+// the archive directory, which the file is not moved over.
+var ErrArchived = errors.New("another file of the same name is archived")
+
+// length of the fixed part of a CDR file header, up to the LostCdrIndicator
+const fixedHeaderLength = 48
+
+// FileInfo describes a closed CDR file by its CdrFileHeader.
+type FileInfo struct {
+	Name             string                               `json:"name"`
+	Size             int64                                `json:"size"`
+	ModTime          time.Time                            `json:"modTime"`
+	SequenceNumber   uint32                               `json:"sequenceNumber"`
+	NumberOfCdrs     uint32                               `json:"numberOfCdrs"`
+	ClosureReason    cdrFile.FileClosureTriggerReasonType `json:"closureReason"`
+	LostCdrIndicator uint8                                `json:"lostCdrIndicator"`
+	// hex SHA-256 of the file
+	Checksum string `json:"checksum"`
+}
+
+// Server serves the closed CDR files of a directory, with extension .cdr as
+// written by a cdrSink.SpoolSink, and moves each one to an archive directory
+// once the client acknowledges it:
+//
+//	GET  /files             lists the files, in the order of their names
+//	GET  /files/{name}      returns the file, with range support
+//	POST /files/{name}/ack  archives the file
+//
+// The file is returned with its SHA-256 in a Digest header, as in RFC 3230,
+// and its hex SHA-256 as ETag. An acknowledgement with a checksum query
+// parameter is refused with 409 if it is not the one of the file.
+//
+// It is safe for concurrent use.
+type Server struct {
+	dir        string
+	archiveDir string
+
+	mu sync.Mutex
+	// checksums of the files, by name
+	checksums map[string]checksum
+}
+
+type checksum struct {
+	size    int64
+	modTime time.Time
+	sum     [sha256.Size]byte
+}
+
+// NewServer returns a server of the files in dir, archived to archiveDir,
+// created if it does not exist.
+func NewServer(dir, archiveDir string) (*Server, error) {
+	if err := os.MkdirAll(archiveDir, 0o777); err != nil {
+		return nil, err
+	}
+	return &Server{
+		dir:        dir,
+		archiveDir: archiveDir,
+		checksums:  make(map[string]checksum),
+	}, nil
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/files")
+	switch {
+	case path == "" || path == "/":
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			methodNotAllowed(w, "GET, HEAD")
+			return
+		}
+		s.serveList(w, r)
+	case strings.HasSuffix(path, "/ack"):
+		if r.Method != http.MethodPost {
+			methodNotAllowed(w, "POST")
+			return
+		}
+		s.serveAck(w, r, strings.TrimSuffix(strings.TrimPrefix(path, "/"), "/ack"))
+	default:
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			methodNotAllowed(w, "GET, HEAD")
+			return
+		}
+		s.serveFile(w, r, strings.TrimPrefix(path, "/"))
+	}
+}
+
+// Files returns the closed CDR files of the directory, in the order of their
+// names. A file too short for a CDR file header is left out.
+func (s *Server) Files() ([]FileInfo, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), cdrSink.ClosedFileExt) {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+
+	files := make([]FileInfo, 0, len(names))
+	for _, name := range names {
+		info, err := s.describe(name)
+		if os.IsNotExist(err) {
+			// archived meanwhile
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if info == nil {
+			continue
+		}
+		files = append(files, *info)
+	}
+	return files, nil
+}
+
+// Archive moves the file name to the archive directory. The file is only
+// moved if its hex SHA-256 is sum, unless sum is empty, and if no file of the
+// same name was archived.
+func (s *Server) Archive(name, sum string) error {
+	if !validName(name) {
+		return os.ErrNotExist
+	}
+	if sum != "" {
+		info, err := s.describe(name)
+		if err != nil {
+			return err
+		}
+		if info == nil || info.Checksum != sum {
+			return ErrChecksumMismatch
+		}
+	}
+	err := safeFile.Move(filepath.Join(s.dir, name), filepath.Join(s.archiveDir, name))
+	if os.IsExist(err) {
+		return ErrArchived
+	}
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.checksums, name)
+	return nil
+}
+
+func (s *Server) serveList(w http.ResponseWriter, r *http.Request) {
+	files, err := s.Files()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	body, err := json.Marshal(files)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(body))
+}
+
+func (s *Server) serveFile(w http.ResponseWriter, r *http.Request, name string) {
+	if !validName(name) {
+		http.NotFound(w, r)
+		return
+	}
+	file, err := os.Open(filepath.Join(s.dir, name))
+	if os.IsNotExist(err) {
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer file.Close()
+	info, err := s.describeFile(name, file)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if info == nil {
+		http.Error(w, "not a CDR file", http.StatusInternalServerError)
+		return
+	}
+
+	sum, err := hex.DecodeString(info.Checksum)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Digest", "SHA-256="+base64.StdEncoding.EncodeToString(sum))
+	w.Header().Set("ETag", `"`+info.Checksum+`"`)
+	http.ServeContent(w, r, name, info.ModTime, file)
+}
+
+func (s *Server) serveAck(w http.ResponseWriter, r *http.Request, name string) {
+	err := s.Archive(name, r.URL.Query().Get("checksum"))
+	switch {
+	case err == nil:
+		w.WriteHeader(http.StatusNoContent)
+	case os.IsNotExist(err):
+		if validName(name) {
+			// acknowledged already
+			if _, err := os.Stat(filepath.Join(s.archiveDir, name)); err == nil {
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+		}
+		http.NotFound(w, r)
+	case errors.Is(err, ErrChecksumMismatch), errors.Is(err, ErrArchived):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// describe returns the description of the file name, nil if it is too short
+// for a CDR file header.
+func (s *Server) describe(name string) (*FileInfo, error) {
+	file, err := os.Open(filepath.Join(s.dir, name))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return s.describeFile(name, file)
+}
+
+func (s *Server) describeFile(name string, file *os.File) (*FileInfo, error) {
+	stat, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	header := make([]byte, fixedHeaderLength)
+	if _, err := io.ReadFull(file, header); err == io.EOF || err == io.ErrUnexpectedEOF {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	sum, err := s.checksum(name, stat, file)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	return &FileInfo{
+		Name:             name,
+		Size:             stat.Size(),
+		ModTime:          stat.ModTime(),
+		SequenceNumber:   binary.BigEndian.Uint32(header[22:26]),
+		NumberOfCdrs:     binary.BigEndian.Uint32(header[18:22]),
+		ClosureReason:    cdrFile.FileClosureTriggerReasonType(header[26]),
+		LostCdrIndicator: header[47],
+		Checksum:         hex.EncodeToString(sum),
+	}, nil
+}
+
+// checksum returns the SHA-256 of file, the file name, known already if it
+// did not change since.
+func (s *Server) checksum(name string, stat os.FileInfo, file *os.File) ([]byte, error) {
+	s.mu.Lock()
+	c, ok := s.checksums[name]
+	s.mu.Unlock()
+	if ok && c.size == stat.Size() && c.modTime.Equal(stat.ModTime()) {
+		return c.sum[:], nil
+	}
+
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return nil, err
+	}
+	c = checksum{size: stat.Size(), modTime: stat.ModTime()}
+	hash.Sum(c.sum[:0])
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.checksums[name] = c
+	return c.sum[:], nil
+}
+
+func validName(name string) bool {
+	return strings.HasSuffix(name, cdrSink.ClosedFileExt) && !strings.ContainsAny(name, `/\`) &&
+		name != cdrSink.ClosedFileExt
+}
+
+func methodNotAllowed(w http.ResponseWriter, allow string) {
+	w.Header().Set("Allow", allow)
+	http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+}
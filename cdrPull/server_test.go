@@ -0,0 +1,128 @@
+package cdrPull
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/free5gc/CDRUtil/cdrFile"
+	"github.com/free5gc/CDRUtil/cdrSink"
+	"github.com/free5gc/CDRUtil/internal/cdrTest"
+	"github.com/stretchr/testify/require"
+)
+
+func request(t *testing.T, method, url string, header http.Header) (*http.Response, []byte) {
+	req, err := http.NewRequest(method, url, nil)
+	require.NoError(t, err)
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp, body
+}
+
+func TestServer(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	spoolDir := filepath.Join(dir, "spool")
+	sink, err := cdrSink.NewSpoolSink(spoolDir, "chf_", cdrSink.Header{}, cdrSink.SpoolLimits{MaxCdrs: 2})
+	require.NoError(t, err)
+	for i := 1; i <= 3; i++ {
+		require.NoError(t, sink.Write(cdrTest.ChargingRecord("session1", int64(i))))
+	}
+	sink.SetLostCdrs(1)
+	require.NoError(t, sink.Flush())
+	require.NoError(t, os.WriteFile(filepath.Join(spoolDir, "short.cdr"), []byte("short"), 0o666))
+
+	server, err := NewServer(spoolDir, filepath.Join(dir, "archive"))
+	require.NoError(t, err)
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	// the open file is not listed
+	resp, body := request(t, http.MethodGet, ts.URL+"/files", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var files []FileInfo
+	require.NoError(t, json.Unmarshal(body, &files))
+	require.Len(t, files, 1)
+	require.Equal(t, "chf_0000000001.cdr", files[0].Name)
+	require.Equal(t, uint32(1), files[0].SequenceNumber)
+	require.Equal(t, uint32(2), files[0].NumberOfCdrs)
+	require.Equal(t, cdrFile.MaximumNumberOfCdrsInFileReached, files[0].ClosureReason)
+	require.Equal(t, uint8(0), files[0].LostCdrIndicator)
+
+	require.NoError(t, sink.Close())
+	files, err = server.Files()
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+	require.Equal(t, cdrFile.NormalClosure, files[1].ClosureReason)
+	require.Equal(t, uint8(0x81), files[1].LostCdrIndicator)
+
+	// the whole file
+	data, err := os.ReadFile(filepath.Join(spoolDir, "chf_0000000001.cdr"))
+	require.NoError(t, err)
+	sum := sha256.Sum256(data)
+	resp, body = request(t, http.MethodGet, ts.URL+"/files/chf_0000000001.cdr", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, data, body)
+	require.Equal(t, "SHA-256="+base64.StdEncoding.EncodeToString(sum[:]), resp.Header.Get("Digest"))
+	require.Equal(t, `"`+hex.EncodeToString(sum[:])+`"`, resp.Header.Get("ETag"))
+	require.Equal(t, hex.EncodeToString(sum[:]), files[0].Checksum)
+
+	// a range
+	resp, body = request(t, http.MethodGet, ts.URL+"/files/chf_0000000001.cdr", http.Header{"Range": {"bytes=10-"}})
+	require.Equal(t, http.StatusPartialContent, resp.StatusCode)
+	require.Equal(t, data[10:], body)
+
+	testCases := []struct {
+		method string
+		path   string
+		status int
+	}{
+		{http.MethodGet, "/files/chf_0000000009.cdr", http.StatusNotFound},
+		{http.MethodGet, "/files/..%2Farchive%2Fchf_0000000001.cdr", http.StatusNotFound},
+		{http.MethodGet, "/files/chf_0000000002.cdr.open", http.StatusNotFound},
+		{http.MethodGet, "/files/short.cdr", http.StatusInternalServerError},
+		{http.MethodDelete, "/files/chf_0000000001.cdr", http.StatusMethodNotAllowed},
+		{http.MethodGet, "/files/chf_0000000001.cdr/ack", http.StatusMethodNotAllowed},
+		{http.MethodPost, "/files/chf_0000000001.cdr/ack?checksum=00", http.StatusConflict},
+		{http.MethodPost, "/files/chf_0000000001.cdr/ack?checksum=" + hex.EncodeToString(sum[:]), http.StatusNoContent},
+		// acknowledged again
+		{http.MethodPost, "/files/chf_0000000001.cdr/ack", http.StatusNoContent},
+		{http.MethodGet, "/files/chf_0000000001.cdr", http.StatusNotFound},
+		{http.MethodPost, "/files/chf_0000000009.cdr/ack", http.StatusNotFound},
+	}
+	for _, tc := range testCases {
+		resp, _ := request(t, tc.method, ts.URL+tc.path, nil)
+		require.Equal(t, tc.status, resp.StatusCode, "%s %s", tc.method, tc.path)
+	}
+
+	require.FileExists(t, filepath.Join(dir, "archive", "chf_0000000001.cdr"))
+	files, err = server.Files()
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	require.Equal(t, "chf_0000000002.cdr", files[0].Name)
+
+	// a file of the same name archived before is kept
+	archivedPath := filepath.Join(dir, "archive", "chf_0000000002.cdr")
+	require.NoError(t, os.WriteFile(archivedPath, []byte("archived before"), 0o666))
+	resp, _ = request(t, http.MethodPost, ts.URL+"/files/chf_0000000002.cdr/ack", nil)
+	require.Equal(t, http.StatusConflict, resp.StatusCode)
+	require.ErrorIs(t, server.Archive("chf_0000000002.cdr", ""), ErrArchived)
+	archived, err := os.ReadFile(archivedPath)
+	require.NoError(t, err)
+	require.Equal(t, "archived before", string(archived))
+	require.FileExists(t, filepath.Join(spoolDir, "chf_0000000002.cdr"))
+}
@@ -0,0 +1,102 @@
+package cdrRating
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// decimals of an amount
+const amountDecimals = 6
+
+// Amount is an amount of money in millionths of the currency unit, read from
+// and written as a decimal number such as 0.25, so that prices add up
+// exactly.
+type Amount int64
+
+// ParseAmount parses a decimal number of at most six decimals.
+func ParseAmount(s string) (Amount, error) {
+	digits := strings.TrimPrefix(s, "-")
+	integer, fraction := digits, ""
+	if i := strings.IndexByte(digits, '.'); i >= 0 {
+		integer, fraction = digits[:i], digits[i+1:]
+	}
+	if integer == "" || len(fraction) > amountDecimals ||
+		strings.Trim(integer, "0123456789") != "" || strings.Trim(fraction, "0123456789") != "" {
+		return 0, fmt.Errorf("invalid amount %q", s)
+	}
+
+	v, err := strconv.ParseInt(integer+fraction+strings.Repeat("0", amountDecimals-len(fraction)), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount %q", s)
+	}
+	if len(digits) < len(s) {
+		v = -v
+	}
+	return Amount(v), nil
+}
+
+func (a Amount) String() string {
+	sign, v := "", int64(a)
+	if v < 0 {
+		sign, v = "-", -v
+	}
+	s := fmt.Sprintf("%s%d.%06d", sign, v/1e6, v%1e6)
+	return strings.TrimSuffix(strings.TrimRight(s, "0"), ".")
+}
+
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+func (a Amount) MarshalYAML() (interface{}, error) {
+	return &yaml.Node{Kind: yaml.ScalarNode, Value: a.String()}, nil
+}
+
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	v, err := ParseAmount(strings.Trim(string(data), `"`))
+	if err != nil {
+		return err
+	}
+	*a = v
+	return nil
+}
+
+func (a *Amount) UnmarshalYAML(value *yaml.Node) error {
+	v, err := ParseAmount(value.Value)
+	if err != nil {
+		return err
+	}
+	*a = v
+	return nil
+}
+
+// scale returns a*n/d rounded half up, a, n and d being positive, or the
+// largest amount if it does not fit.
+func (a Amount) scale(n, d int64) Amount {
+	hi, lo := bits.Mul64(uint64(a), uint64(n))
+	var carry uint64
+	lo, carry = bits.Add64(lo, uint64(d)/2, 0)
+	hi += carry
+	if hi >= uint64(d) {
+		return math.MaxInt64
+	}
+	q, _ := bits.Div64(hi, lo, uint64(d))
+	if q > math.MaxInt64 {
+		return math.MaxInt64
+	}
+	return Amount(q)
+}
+
+// add returns a+b, a and b being positive, or the largest amount if it does
+// not fit.
+func (a Amount) add(b Amount) Amount {
+	if a > math.MaxInt64-b {
+		return math.MaxInt64
+	}
+	return a + b
+}
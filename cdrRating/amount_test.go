@@ -0,0 +1,78 @@
+package cdrRating
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestAmount(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name   string
+		s      string
+		amount Amount
+		valid  bool
+	}{
+		{"integer", "12", 12e6, true},
+		{"decimal", "0.015", 15000, true},
+		{"micro", "0.000001", 1, true},
+		{"negative", "-1.5", -1.5e6, true},
+		{"too precise", "0.0000001", 0, false},
+		{"exponent", "1e3", 0, false},
+		{"empty integer", ".5", 0, false},
+		{"sign only", "-", 0, false},
+		{"overflow", "10000000000000", 0, false},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			amount, err := ParseAmount(tc.s)
+			if !tc.valid {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.amount, amount)
+		})
+	}
+
+	require.Equal(t, "0", Amount(0).String())
+	require.Equal(t, "12", Amount(12e6).String())
+	require.Equal(t, "0.015", Amount(15000).String())
+	require.Equal(t, "-1.5", Amount(-1.5e6).String())
+
+	var prices struct {
+		JSON Amount `json:"json"`
+		YAML Amount `yaml:"yaml"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(`{"json": 0.1}`), &prices))
+	require.NoError(t, yaml.Unmarshal([]byte("yaml: 0.2"), &prices))
+	require.Equal(t, Amount(1e5), prices.JSON)
+	require.Equal(t, Amount(2e5), prices.YAML)
+	require.Error(t, json.Unmarshal([]byte(`{"json": "a"}`), &prices))
+
+	// written back as decimals
+	prices.JSON, prices.YAML = 250000, -12e6
+	b, err := json.Marshal(prices)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"json": 0.25, "YAML": -12}`, string(b))
+	b, err = yaml.Marshal(prices)
+	require.NoError(t, err)
+	require.Equal(t, "json: 0.25\nyaml: -12\n", string(b))
+	prices.JSON, prices.YAML = 1e5, 2e5
+
+	// 0.1 + 0.2 adds up exactly
+	require.Equal(t, Amount(3e5), prices.JSON+prices.YAML)
+	// rounded half up, and saturated
+	require.Equal(t, Amount(2), Amount(3).scale(1, 2))
+	require.Equal(t, Amount(math.MaxInt64), Amount(math.MaxInt64).scale(3, 2))
+	require.Equal(t, Amount(math.MaxInt64), Amount(math.MaxInt64-1).add(2))
+}
@@ -0,0 +1,407 @@
+// Package cdrRating prices the unit usage of charging records by tariff
+// plans.
+package cdrRating
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/free5gc/CDRUtil/cdrType"
+	"gopkg.in/yaml.v3"
+)
+
+// octets in a megabyte of a volume tier
+const megabyte = 1000000
+
+// Rater prices a charging record.
+type Rater interface {
+	Rate(record cdrType.ChargingRecord) (RatedRecord, error)
+}
+
+// RatedRecord is a charging record with the price of its used unit
+// containers.
+type RatedRecord struct {
+	Record     cdrType.ChargingRecord
+	Containers []RatedContainer
+	Price      Amount
+}
+
+// RatedContainer is the price of a used unit container, at index Container
+// in its rating group.
+type RatedContainer struct {
+	RatingGroup int64
+	Container   int
+	Tariff      string
+	Price       Amount
+}
+
+// Plan is a set of tariffs, the first one matching a used unit container
+// pricing it.
+type Plan struct {
+	Currency string `json:"currency" yaml:"currency"`
+	// MCC and MNC of the home network, e.g. "20893", for the records telling
+	// only their serving network
+	HomePlmn string   `json:"homePlmn,omitempty" yaml:"homePlmn,omitempty"`
+	Tariffs  []Tariff `json:"tariffs" yaml:"tariffs"`
+}
+
+// Tariff prices the used unit containers it matches, each empty condition
+// matching all of them.
+type Tariff struct {
+	Name         string  `json:"name" yaml:"name"`
+	RatingGroups []int64 `json:"ratingGroups,omitempty" yaml:"ratingGroups,omitempty"`
+	// "home" or "roaming"
+	Roaming string `json:"roaming,omitempty" yaml:"roaming,omitempty"`
+	// MCC and MNC of the serving networks
+	ServingPlmns []string `json:"servingPlmns,omitempty" yaml:"servingPlmns,omitempty"`
+	// "hh:mm" in the UTC offset of the container time stamp, From included and
+	// To excluded, To before From going past midnight
+	From string `json:"from,omitempty" yaml:"from,omitempty"`
+	To   string `json:"to,omitempty" yaml:"to,omitempty"`
+
+	// price per megabyte of the volume of the rating group used by the
+	// subscriber under this tariff, by tier
+	Tiers []Tier `json:"tiers,omitempty" yaml:"tiers,omitempty"`
+	// price per minute of time
+	PerMinute Amount `json:"perMinute,omitempty" yaml:"perMinute,omitempty"`
+	// price per service specific unit
+	PerUnit Amount `json:"perUnit,omitempty" yaml:"perUnit,omitempty"`
+
+	from, to int // minutes of the day
+}
+
+// Tier is the price of the volume up to UpTo octets, the volume of the
+// previous tiers included. The last tier has no bound, a zero UpTo, and
+// prices all the volume beyond the others.
+type Tier struct {
+	UpTo        int64  `json:"upTo,omitempty" yaml:"upTo,omitempty"`
+	PerMegabyte Amount `json:"perMegabyte" yaml:"perMegabyte"`
+}
+
+// LoadPlan reads the plan in the JSON file at path or, if its extension is
+// .yaml or .yml, in the YAML one.
+func LoadPlan(path string) (Plan, error) {
+	var plan Plan
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return plan, err
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &plan)
+	default:
+		err = json.Unmarshal(data, &plan)
+	}
+	if err != nil {
+		return plan, fmt.Errorf("%s: %v", path, err)
+	}
+	return plan, nil
+}
+
+// PlanRater rates records by a plan, the volume tiers of a tariff applying to
+// the volume it priced for the subscriber and rating group in all records
+// rated so far.
+//
+// It is safe for concurrent use.
+type PlanRater struct {
+	plan Plan
+
+	mu sync.Mutex
+	// volume used by subscriber, rating group and tariff
+	used map[usageKey]int64
+}
+
+type usageKey struct {
+	subscriber  string
+	ratingGroup int64
+	tariff      string
+}
+
+// NewPlanRater checks plan and returns a rater by it.
+func NewPlanRater(plan Plan) (*PlanRater, error) {
+	tariffs := make([]Tariff, 0, len(plan.Tariffs))
+	for i, tariff := range plan.Tariffs {
+		if tariff.Name == "" {
+			tariff.Name = fmt.Sprintf("tariff %d", i+1)
+		}
+		if err := tariff.check(); err != nil {
+			return nil, fmt.Errorf("%s: %v", tariff.Name, err)
+		}
+		tariffs = append(tariffs, tariff)
+	}
+	plan.Tariffs = tariffs
+
+	return &PlanRater{
+		plan: plan,
+		used: make(map[usageKey]int64),
+	}, nil
+}
+
+// Rate prices each used unit container of record by the first tariff that
+// matches it. The record is not rated if one of them matches none.
+func (r *PlanRater) Rate(record cdrType.ChargingRecord) (RatedRecord, error) {
+	rated := RatedRecord{Record: record}
+
+	var subscriber string
+	if record.SubscriberIdentifier != nil {
+		subscriber = string(record.SubscriberIdentifier.SubscriptionIDData)
+	}
+	roaming, servingPlmn, err := r.servingNetwork(record)
+	if err != nil {
+		return rated, err
+	}
+	openingTime, err := record.RecordOpeningTime.Decode()
+	if err != nil {
+		return rated, fmt.Errorf("recordOpeningTime: %v", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// volume used by the record, added once it is rated
+	used := make(map[usageKey]int64)
+	for _, usage := range record.ListOfMultipleUnitUsage {
+		ratingGroup := usage.RatingGroup.Value
+		for i, container := range usage.UsedUnitContainers {
+			t := openingTime
+			if container.TriggerTimeStamp != nil {
+				if t, err = container.TriggerTimeStamp.Decode(); err != nil {
+					return rated, fmt.Errorf("triggerTimeStamp: %v", err)
+				}
+			}
+			tariff := r.match(ratingGroup, roaming, servingPlmn, t)
+			if tariff == nil {
+				return rated, fmt.Errorf("no tariff for rating group %d at %s", ratingGroup, t.Format("15:04"))
+			}
+
+			volume, err := containerVolume(container)
+			if err != nil {
+				return rated, fmt.Errorf("rating group %d container %d: %v", ratingGroup, i, err)
+			}
+			if container.Time != nil && container.Time.Value < 0 {
+				return rated, fmt.Errorf("rating group %d container %d: negative time", ratingGroup, i)
+			}
+			if container.ServiceSpecificUnits != nil && *container.ServiceSpecificUnits < 0 {
+				return rated, fmt.Errorf("rating group %d container %d: negative serviceSpecificUnits",
+					ratingGroup, i)
+			}
+
+			key := usageKey{subscriber: subscriber, ratingGroup: ratingGroup, tariff: tariff.Name}
+			price := tariff.volumePrice(r.used[key]+used[key], volume)
+			used[key] += volume
+			if container.Time != nil {
+				price = price.add(tariff.PerMinute.scale(container.Time.Value, 60))
+			}
+			if container.ServiceSpecificUnits != nil {
+				price = price.add(tariff.PerUnit.scale(*container.ServiceSpecificUnits, 1))
+			}
+
+			rated.Containers = append(rated.Containers, RatedContainer{
+				RatingGroup: ratingGroup,
+				Container:   i,
+				Tariff:      tariff.Name,
+				Price:       price,
+			})
+			rated.Price = rated.Price.add(price)
+		}
+	}
+	for key, volume := range used {
+		r.used[key] += volume
+	}
+	return rated, nil
+}
+
+// Reset forgets the volume used so far, as at the start of a billing period.
+func (r *PlanRater) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.used = make(map[usageKey]int64)
+}
+
+// Totals returns the price of the records by subscriber, the
+// SubscriptionIDData of their subscriber identifier.
+func Totals(records []RatedRecord) map[string]Amount {
+	totals := make(map[string]Amount)
+	for _, record := range records {
+		var subscriber string
+		if record.Record.SubscriberIdentifier != nil {
+			subscriber = string(record.Record.SubscriberIdentifier.SubscriptionIDData)
+		}
+		totals[subscriber] = totals[subscriber].add(record.Price)
+	}
+	return totals
+}
+
+// servingNetwork returns whether the PDU session of record is roaming, by its
+// RoamerInOut or else by its serving network, and the MCC and MNC of that
+// network, if known.
+func (r *PlanRater) servingNetwork(record cdrType.ChargingRecord) (bool, string, error) {
+	info := record.PDUSessionChargingInformation
+	if info == nil {
+		return false, "", nil
+	}
+
+	var servingPlmn string
+	if info.ServingCNPLMNID != nil {
+		mcc, mnc, err := info.ServingCNPLMNID.Decode()
+		if err != nil {
+			return false, "", fmt.Errorf("servingCNPLMNID: %v", err)
+		}
+		servingPlmn = mcc + mnc
+	}
+	switch {
+	case info.UserRoamerInOut != nil:
+		return true, servingPlmn, nil
+	case servingPlmn != "" && r.plan.HomePlmn != "":
+		return servingPlmn != r.plan.HomePlmn, servingPlmn, nil
+	}
+	return false, servingPlmn, nil
+}
+
+func (r *PlanRater) match(ratingGroup int64, roaming bool, servingPlmn string, t time.Time) *Tariff {
+	minute := t.Hour()*60 + t.Minute()
+	for i := range r.plan.Tariffs {
+		tariff := &r.plan.Tariffs[i]
+		if len(tariff.RatingGroups) > 0 && !containsInt64(tariff.RatingGroups, ratingGroup) {
+			continue
+		}
+		if tariff.Roaming != "" && (tariff.Roaming == "roaming") != roaming {
+			continue
+		}
+		if len(tariff.ServingPlmns) > 0 && !containsString(tariff.ServingPlmns, servingPlmn) {
+			continue
+		}
+		switch {
+		case tariff.from < tariff.to && (minute < tariff.from || minute >= tariff.to):
+			continue
+		case tariff.from > tariff.to && minute < tariff.from && minute >= tariff.to:
+			continue
+		}
+		return tariff
+	}
+	return nil
+}
+
+func (t *Tariff) check() error {
+	switch t.Roaming {
+	case "", "home", "roaming":
+	default:
+		return fmt.Errorf("unsupported roaming %q", t.Roaming)
+	}
+
+	var err error
+	if t.from, err = minuteOfDay(t.From); err != nil {
+		return fmt.Errorf("from: %v", err)
+	}
+	if t.to, err = minuteOfDay(t.To); err != nil {
+		return fmt.Errorf("to: %v", err)
+	}
+
+	if t.PerMinute < 0 || t.PerUnit < 0 {
+		return fmt.Errorf("negative price")
+	}
+
+	var upTo int64
+	for i, tier := range t.Tiers {
+		last := i == len(t.Tiers)-1
+		switch {
+		case tier.PerMegabyte < 0:
+			return fmt.Errorf("tier %d: negative price", i+1)
+		case tier.UpTo == 0 && !last:
+			return fmt.Errorf("tier %d without bound before the last one", i+1)
+		case tier.UpTo != 0 && last:
+			return fmt.Errorf("last tier %d bounded, up to %d octets", i+1, tier.UpTo)
+		case tier.UpTo != 0 && tier.UpTo <= upTo:
+			return fmt.Errorf("tier %d up to %d octets after %d", i+1, tier.UpTo, upTo)
+		}
+		upTo = tier.UpTo
+	}
+	return nil
+}
+
+// volumePrice returns the price of volume octets used after the given ones.
+func (t *Tariff) volumePrice(used, volume int64) Amount {
+	var price Amount
+	var lower int64
+	for _, tier := range t.Tiers {
+		start, end := used, used+volume
+		if start < lower {
+			start = lower
+		}
+		if tier.UpTo != 0 && end > tier.UpTo {
+			end = tier.UpTo
+		}
+		if end > start {
+			price = price.add(tier.PerMegabyte.scale(end-start, megabyte))
+		}
+		lower = tier.UpTo
+	}
+	return price
+}
+
+// minuteOfDay parses "hh:mm", the empty string being midnight.
+func minuteOfDay(s string) (int, error) {
+	if s == "" {
+		return 0, nil
+	}
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of day %q", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+func containerVolume(container cdrType.UsedUnitContainer) (int64, error) {
+	if container.DataTotalVolume != nil {
+		if container.DataTotalVolume.Value < 0 {
+			return 0, fmt.Errorf("negative dataTotalVolume")
+		}
+		return container.DataTotalVolume.Value, nil
+	}
+	var volume int64
+	for _, v := range []struct {
+		name   string
+		volume *cdrType.DataVolumeOctets
+	}{
+		{"dataVolumeUplink", container.DataVolumeUplink},
+		{"dataVolumeDownlink", container.DataVolumeDownlink},
+	} {
+		if v.volume == nil {
+			continue
+		}
+		if v.volume.Value < 0 {
+			return 0, fmt.Errorf("negative %s", v.name)
+		}
+		if volume > math.MaxInt64-v.volume.Value {
+			return 0, fmt.Errorf("volume overflows")
+		}
+		volume += v.volume.Value
+	}
+	return volume, nil
+}
+
+func containsInt64(list []int64, v int64) bool {
+	for _, e := range list {
+		if e == v {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(list []string, v string) bool {
+	for _, e := range list {
+		if e == v {
+			return true
+		}
+	}
+	return false
+}
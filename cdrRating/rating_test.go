@@ -0,0 +1,247 @@
+package cdrRating
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/free5gc/CDRUtil/cdrType"
+	"github.com/stretchr/testify/require"
+)
+
+const yamlPlan = `
+currency: EUR
+homePlmn: "20893"
+tariffs:
+  - name: roaming
+    roaming: roaming
+    tiers:
+      - perMegabyte: 10
+  - name: night
+    ratingGroups: [1]
+    from: "22:00"
+    to: "06:00"
+    tiers:
+      - perMegabyte: 0
+  - name: data
+    ratingGroups: [1]
+    tiers:
+      - upTo: 2000000
+        perMegabyte: 2
+      - perMegabyte: 1
+  - name: voice
+    ratingGroups: [2]
+    perMinute: 0.5
+    perUnit: 0.1
+`
+
+const jsonPlan = `{
+  "currency": "EUR",
+  "tariffs": [{"name": "data", "tiers": [{"perMegabyte": 0.015}]}]
+}`
+
+func usedUnitContainer(t time.Time, volume int64) cdrType.UsedUnitContainer {
+	triggerTimeStamp := cdrType.NewTimeStamp(t)
+	return cdrType.UsedUnitContainer{
+		DataTotalVolume:  &cdrType.DataVolumeOctets{Value: volume},
+		TriggerTimeStamp: &triggerTimeStamp,
+	}
+}
+
+func TestLoadPlan(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	yamlPath := filepath.Join(dir, "plan.yaml")
+	jsonPath := filepath.Join(dir, "plan.json")
+	require.NoError(t, os.WriteFile(yamlPath, []byte(yamlPlan), 0o666))
+	require.NoError(t, os.WriteFile(jsonPath, []byte(jsonPlan), 0o666))
+
+	plan, err := LoadPlan(yamlPath)
+	require.NoError(t, err)
+	require.Equal(t, "20893", plan.HomePlmn)
+	require.Len(t, plan.Tariffs, 4)
+	require.Equal(t, []Tier{{UpTo: 2000000, PerMegabyte: 2e6}, {PerMegabyte: 1e6}}, plan.Tariffs[2].Tiers)
+	require.Equal(t, Amount(5e5), plan.Tariffs[3].PerMinute)
+	require.Equal(t, Amount(1e5), plan.Tariffs[3].PerUnit)
+
+	plan, err = LoadPlan(jsonPath)
+	require.NoError(t, err)
+	require.Equal(t, []Tariff{{Name: "data", Tiers: []Tier{{PerMegabyte: 15000}}}}, plan.Tariffs)
+
+	_, err = LoadPlan(filepath.Join(dir, "none.json"))
+	require.Error(t, err)
+}
+
+func TestNewPlanRater(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name   string
+		tariff Tariff
+	}{
+		{"roaming", Tariff{Roaming: "abroad"}},
+		{"from", Tariff{From: "25:00"}},
+		{"unbounded tier", Tariff{Tiers: []Tier{{PerMegabyte: 1}, {UpTo: 10, PerMegabyte: 1}}}},
+		{"decreasing tiers", Tariff{Tiers: []Tier{{UpTo: 10, PerMegabyte: 1}, {UpTo: 5, PerMegabyte: 1}, {PerMegabyte: 1}}}},
+		{"bounded last tier", Tariff{Tiers: []Tier{{UpTo: 10, PerMegabyte: 1}}}},
+		{"negative price", Tariff{Tiers: []Tier{{PerMegabyte: -1}}}},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := NewPlanRater(Plan{Tariffs: []Tariff{tc.tariff}})
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestPlanRater(t *testing.T) {
+	t.Parallel()
+
+	plan, err := LoadPlan(writePlan(t))
+	require.NoError(t, err)
+	rater, err := NewPlanRater(plan)
+	require.NoError(t, err)
+
+	day := time.Date(2022, time.May, 4, 10, 0, 0, 0, time.FixedZone("", 2*3600))
+	night := time.Date(2022, time.May, 4, 23, 0, 0, 0, time.FixedZone("", 2*3600))
+	minutes := cdrType.CallDuration{Value: 120}
+	units := int64(3)
+	servingPlmn, err := cdrType.NewPLMNId("208", "93")
+	require.NoError(t, err)
+	record := cdrType.ChargingRecord{
+		SubscriberIdentifier: &cdrType.SubscriptionID{SubscriptionIDData: "208930000000001"},
+		RecordOpeningTime:    cdrType.NewTimeStamp(day),
+		ListOfMultipleUnitUsage: []cdrType.MultipleUnitUsage{{
+			RatingGroup: cdrType.RatingGroupId{Value: 1},
+			UsedUnitContainers: []cdrType.UsedUnitContainer{
+				usedUnitContainer(day, 1500000),
+				usedUnitContainer(night, 5000000),
+				usedUnitContainer(day, 1000000),
+			},
+		}, {
+			RatingGroup: cdrType.RatingGroupId{Value: 2},
+			UsedUnitContainers: []cdrType.UsedUnitContainer{
+				{Time: &minutes, ServiceSpecificUnits: &units},
+			},
+		}},
+		PDUSessionChargingInformation: &cdrType.PDUSessionChargingInformation{
+			ServingCNPLMNID: &servingPlmn,
+		},
+	}
+
+	rated, err := rater.Rate(record)
+	require.NoError(t, err)
+	require.Equal(t, []RatedContainer{
+		{RatingGroup: 1, Container: 0, Tariff: "data", Price: 3e6},
+		{RatingGroup: 1, Container: 1, Tariff: "night", Price: 0},
+		// 0.5 MB at 2 and 0.5 MB at 1
+		{RatingGroup: 1, Container: 2, Tariff: "data", Price: 1.5e6},
+		{RatingGroup: 2, Container: 0, Tariff: "voice", Price: 1.3e6},
+	}, rated.Containers)
+	require.Equal(t, Amount(5.8e6), rated.Price)
+
+	// all the volume in the last tier
+	rated2, err := rater.Rate(record)
+	require.NoError(t, err)
+	require.Equal(t, Amount(3.8e6), rated2.Price)
+
+	// roaming
+	roamingPlmn, err := cdrType.NewPLMNId("262", "01")
+	require.NoError(t, err)
+	record.PDUSessionChargingInformation.ServingCNPLMNID = &roamingPlmn
+	record.ListOfMultipleUnitUsage = record.ListOfMultipleUnitUsage[:1]
+	rated3, err := rater.Rate(record)
+	require.NoError(t, err)
+	require.Equal(t, Amount(75e6), rated3.Price)
+	require.Equal(t, "roaming", rated3.Containers[0].Tariff)
+
+	// no tariff for rating group 3
+	record.PDUSessionChargingInformation = nil
+	record.ListOfMultipleUnitUsage[0].RatingGroup.Value = 3
+	_, err = rater.Rate(record)
+	require.Error(t, err)
+
+	require.Equal(t, map[string]Amount{"208930000000001": 84.6e6},
+		Totals([]RatedRecord{rated, rated2, rated3}))
+
+	rater.Reset()
+	record.ListOfMultipleUnitUsage[0].RatingGroup.Value = 1
+	rated, err = rater.Rate(record)
+	require.NoError(t, err)
+	require.Equal(t, Amount(4.5e6), rated.Price)
+}
+
+func TestPlanRaterUnits(t *testing.T) {
+	t.Parallel()
+
+	plan, err := LoadPlan(writePlan(t))
+	require.NoError(t, err)
+
+	day := time.Date(2022, time.May, 4, 10, 0, 0, 0, time.UTC)
+	negative := int64(-1)
+	testCases := []struct {
+		name        string
+		ratingGroup int64
+		container   cdrType.UsedUnitContainer
+	}{
+		{"dataTotalVolume", 1, usedUnitContainer(day, -1)},
+		{"dataVolumeUplink", 1, cdrType.UsedUnitContainer{DataVolumeUplink: &cdrType.DataVolumeOctets{Value: -1}}},
+		{"volumeOverflow", 1, cdrType.UsedUnitContainer{
+			DataVolumeUplink:   &cdrType.DataVolumeOctets{Value: math.MaxInt64},
+			DataVolumeDownlink: &cdrType.DataVolumeOctets{Value: 1},
+		}},
+		{"time", 2, cdrType.UsedUnitContainer{Time: &cdrType.CallDuration{Value: -60}}},
+		{"serviceSpecificUnits", 2, cdrType.UsedUnitContainer{ServiceSpecificUnits: &negative}},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			rater, err := NewPlanRater(plan)
+			require.NoError(t, err)
+			_, err = rater.Rate(cdrType.ChargingRecord{
+				RecordOpeningTime: cdrType.NewTimeStamp(day),
+				ListOfMultipleUnitUsage: []cdrType.MultipleUnitUsage{{
+					RatingGroup:        cdrType.RatingGroupId{Value: tc.ratingGroup},
+					UsedUnitContainers: []cdrType.UsedUnitContainer{tc.container},
+				}},
+			})
+			require.Error(t, err)
+		})
+	}
+
+	// prices too large for an amount are saturated, not wrapped around
+	rater, err := NewPlanRater(plan)
+	require.NoError(t, err)
+	units := int64(math.MaxInt64)
+	minutes := cdrType.CallDuration{Value: math.MaxInt64}
+	rated, err := rater.Rate(cdrType.ChargingRecord{
+		RecordOpeningTime: cdrType.NewTimeStamp(day),
+		ListOfMultipleUnitUsage: []cdrType.MultipleUnitUsage{{
+			RatingGroup: cdrType.RatingGroupId{Value: 2},
+			UsedUnitContainers: []cdrType.UsedUnitContainer{
+				{Time: &minutes, ServiceSpecificUnits: &units},
+				{ServiceSpecificUnits: &units},
+			},
+		}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, Amount(math.MaxInt64), rated.Containers[0].Price)
+	require.Equal(t, Amount(math.MaxInt64), rated.Price)
+	require.Equal(t, map[string]Amount{"": math.MaxInt64}, Totals([]RatedRecord{rated, rated}))
+}
+
+func writePlan(t *testing.T) string {
+	path := filepath.Join(t.TempDir(), "plan.yml")
+	require.NoError(t, os.WriteFile(path, []byte(yamlPlan), 0o666))
+	return path
+}
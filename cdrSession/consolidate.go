@@ -0,0 +1,165 @@
+package cdrSession
+
+import (
+	"sort"
+
+	"github.com/free5gc/CDRUtil/cdrType"
+)
+
+// TS 32.298 causes for closing a partial record, the session going on
+var partialRecordCauses = map[int64]bool{
+	cdrType.CauseForRecClosingPresentPartialRecord:          true,
+	cdrType.CauseForRecClosingPresentVolumeLimit:            true,
+	cdrType.CauseForRecClosingPresentTimeLimit:              true,
+	cdrType.CauseForRecClosingPresentServingNodeChange:      true,
+	cdrType.CauseForRecClosingPresentMaxChangeCond:          true,
+	cdrType.CauseForRecClosingPresentManagementIntervention: true,
+	cdrType.CauseForRecClosingPresentRATChange:              true,
+	cdrType.CauseForRecClosingPresentMSTimeZoneChange:       true,
+	cdrType.CauseForRecClosingPresentSGSNPLMNIDChange:       true,
+	cdrType.CauseForRecClosingPresentSGWChange:              true,
+	cdrType.CauseForRecClosingPresentAPNAMBRChange:          true,
+}
+
+// Consolidation is the record of a whole charging session merged from its
+// partial records.
+type Consolidation struct {
+	Record cdrType.ChargingRecord
+	// RecordSequenceNumber of the partial records missing before the last one
+	Missing []int64
+	// RecordSequenceNumber of the partial records found more than once, only
+	// the first one being merged
+	Duplicates []int64
+	// whether the last record closes the session
+	Complete bool
+}
+
+type consolidationKey struct {
+	recordingNetworkFunctionID string
+	chargingSessionIdentifier  string
+}
+
+// Consolidate merges the records of each charging session, identified by the
+// recording network function and the charging session identifier, in the
+// order of their RecordSequenceNumber. A record without sequence number is
+// taken as the first one of its session, a record without charging session
+// identifier as a whole session of its own. The sessions are returned in the
+// order of their first record.
+//
+// The consolidated record is the first one of the session, lasting for all
+// the records merged and closed like the last one. The used unit containers
+// of each rating group and the triggers of all records are merged into it.
+// Its IncompleteCDRIndication tells which partial records are missing, the
+// last one being missing if it does not close the session.
+func Consolidate(records []cdrType.ChargingRecord) []Consolidation {
+	// the records of each session, a record without session being alone
+	var sessions [][]cdrType.ChargingRecord
+	index := make(map[consolidationKey]int)
+
+	for _, record := range records {
+		if record.ChargingSessionIdentifier == nil {
+			sessions = append(sessions, []cdrType.ChargingRecord{record})
+			continue
+		}
+		key := consolidationKey{
+			recordingNetworkFunctionID: string(record.RecordingNetworkFunctionID.Value),
+			chargingSessionIdentifier:  string(record.ChargingSessionIdentifier.Value),
+		}
+		i, ok := index[key]
+		if !ok {
+			i = len(sessions)
+			index[key] = i
+			sessions = append(sessions, nil)
+		}
+		sessions[i] = append(sessions[i], record)
+	}
+
+	consolidations := make([]Consolidation, 0, len(sessions))
+	for _, session := range sessions {
+		consolidations = append(consolidations, consolidate(session))
+	}
+	return consolidations
+}
+
+func consolidate(records []cdrType.ChargingRecord) Consolidation {
+	var consolidation Consolidation
+
+	sort.SliceStable(records, func(i, j int) bool {
+		return sequenceNumber(records[i]) < sequenceNumber(records[j])
+	})
+	parts := records[:0]
+	for _, record := range records {
+		if len(parts) > 0 && sequenceNumber(parts[len(parts)-1]) == sequenceNumber(record) {
+			consolidation.Duplicates = append(consolidation.Duplicates, sequenceNumber(record))
+			continue
+		}
+		parts = append(parts, record)
+	}
+
+	var initialLost, updateLost, terminationLost bool
+	next := int64(1)
+	for _, part := range parts {
+		for ; next < sequenceNumber(part); next++ {
+			consolidation.Missing = append(consolidation.Missing, next)
+			if next == 1 {
+				initialLost = true
+			} else {
+				updateLost = true
+			}
+		}
+		next = sequenceNumber(part) + 1
+	}
+	first, last := parts[0], parts[len(parts)-1]
+	consolidation.Complete = !partialRecordCauses[last.CauseForRecClosing.Value]
+	terminationLost = !consolidation.Complete
+
+	record := first
+	record.ListOfMultipleUnitUsage = nil
+	record.Triggers = nil
+	record.Duration = cdrType.CallDuration{}
+	record.RecordSequenceNumber = nil
+	record.LocalRecordSequenceNumber = nil
+	record.CauseForRecClosing = last.CauseForRecClosing
+	record.Diagnostics = last.Diagnostics
+	for _, part := range parts {
+		record.ListOfMultipleUnitUsage = mergeUnitUsage(record.ListOfMultipleUnitUsage, part.ListOfMultipleUnitUsage)
+		record.Triggers = mergeTriggers(record.Triggers, part.Triggers)
+		record.Duration.Value += part.Duration.Value
+		if indication := part.IncompleteCDRIndication; indication != nil {
+			initialLost = initialLost || isTrue(indication.InitialLost)
+			updateLost = updateLost || isTrue(indication.UpdateLost)
+			terminationLost = terminationLost || isTrue(indication.TerminationLost)
+		}
+	}
+
+	record.IncompleteCDRIndication = nil
+	if initialLost || updateLost || terminationLost {
+		record.IncompleteCDRIndication = &cdrType.IncompleteCDRIndication{
+			InitialLost:     trueOrNil(initialLost),
+			UpdateLost:      trueOrNil(updateLost),
+			TerminationLost: trueOrNil(terminationLost),
+		}
+	}
+	consolidation.Record = record
+
+	return consolidation
+}
+
+// sequenceNumber returns the RecordSequenceNumber of record, 1 if it has none.
+func sequenceNumber(record cdrType.ChargingRecord) int64 {
+	if record.RecordSequenceNumber == nil {
+		return 1
+	}
+	return *record.RecordSequenceNumber
+}
+
+func isTrue(b *bool) bool {
+	return b != nil && *b
+}
+
+func trueOrNil(b bool) *bool {
+	if !b {
+		return nil
+	}
+	return &b
+}
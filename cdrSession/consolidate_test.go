@@ -0,0 +1,101 @@
+package cdrSession
+
+import (
+	"testing"
+	"time"
+
+	"github.com/free5gc/CDRUtil/asn"
+	"github.com/free5gc/CDRUtil/cdrType"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConsolidate(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2022, time.May, 4, 10, 0, 0, 0, time.UTC)
+	sink := &recordSink{}
+	manager := NewManager("chf", "SMF", sink)
+	manager.SetPartialRecordLimits(PartialRecordLimits{MaxContainers: 2})
+
+	require.NoError(t, manager.Create("session1", chargingDataRequest(start, 0, 0)))
+	require.NoError(t, manager.Create("session2", chargingDataRequest(start, 0, 0)))
+	for i := 1; i <= 6; i++ {
+		now := start.Add(time.Duration(i) * time.Minute)
+		require.NoError(t, manager.Update("session1", chargingDataRequest(now, int32(i%2+1), 100, "VOLUME_LIMIT")))
+		require.NoError(t, manager.Update("session2", chargingDataRequest(now, 1, 10)))
+	}
+	require.NoError(t, manager.Release("session1", chargingDataRequest(start.Add(10*time.Minute), 0, 0)))
+	// session1 #1, session2 #1, session1 #2, session2 #2, session1 #3,
+	// session2 #3 and session1 #4, the final one
+	require.Len(t, sink.records, 7)
+
+	testCases := []struct {
+		name     string
+		records  []int
+		expected []Consolidation
+	}{
+		{
+			name:    "complete",
+			records: []int{6, 4, 2, 0},
+			expected: []Consolidation{{
+				Complete: true,
+			}},
+		},
+		{
+			name:    "duplicate and missing",
+			records: []int{0, 5, 4, 1, 6, 1},
+			expected: []Consolidation{{
+				Missing:  []int64{2},
+				Complete: true,
+			}, {
+				Duplicates: []int64{1},
+				Missing:    []int64{2},
+				Complete:   false,
+			}},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var records []cdrType.ChargingRecord
+			for _, i := range tc.records {
+				records = append(records, sink.records[i])
+			}
+			consolidations := Consolidate(records)
+			require.Len(t, consolidations, len(tc.expected))
+			for i, consolidation := range consolidations {
+				require.Equal(t, tc.expected[i].Missing, consolidation.Missing)
+				require.Equal(t, tc.expected[i].Duplicates, consolidation.Duplicates)
+				require.Equal(t, tc.expected[i].Complete, consolidation.Complete)
+				require.Nil(t, consolidation.Record.RecordSequenceNumber)
+				_, err := asn.BerMarshalWithParams(consolidation.Record, "")
+				require.NoError(t, err)
+			}
+		})
+	}
+
+	session1 := Consolidate(sink.records)[0].Record
+	require.Equal(t, asn.OctetString("session1"), session1.ChargingSessionIdentifier.Value)
+	require.Equal(t, int64(600), session1.Duration.Value)
+	require.Equal(t, cdrType.CauseForRecClosingPresentNormalRelease, session1.CauseForRecClosing.Value)
+	require.Nil(t, session1.IncompleteCDRIndication)
+	require.Len(t, session1.Triggers, 1)
+	require.Len(t, session1.ListOfMultipleUnitUsage, 2)
+	require.Len(t, session1.ListOfMultipleUnitUsage[0].UsedUnitContainers, 3)
+	require.Len(t, session1.ListOfMultipleUnitUsage[1].UsedUnitContainers, 3)
+
+	consolidations := Consolidate([]cdrType.ChargingRecord{sink.records[0], sink.records[5]})
+	require.Len(t, consolidations, 2)
+	require.Equal(t, &cdrType.IncompleteCDRIndication{
+		TerminationLost: trueOrNil(true),
+	}, consolidations[0].Record.IncompleteCDRIndication)
+	require.Equal(t, []int64{1, 2}, consolidations[1].Missing)
+	require.Equal(t, &cdrType.IncompleteCDRIndication{
+		InitialLost:     trueOrNil(true),
+		UpdateLost:      trueOrNil(true),
+		TerminationLost: trueOrNil(true),
+	}, consolidations[1].Record.IncompleteCDRIndication)
+}
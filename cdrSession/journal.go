@@ -0,0 +1,235 @@
+package cdrSession
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/free5gc/CDRUtil/internal/safeFile"
+)
+
+// JournalEntry is the state of an open session saved in a journal.
+type JournalEntry struct {
+	SessionId      string
+	OpeningTime    time.Time
+	UpdateTime     time.Time
+	SequenceNumber int64
+	// BER encoding of the open record
+	Record []byte
+}
+
+// Journal saves the open sessions of a Manager before it acknowledges their
+// changes, so that their records can be recovered after a restart, and the
+// LocalRecordSequenceNumber of the last record written, so that the
+// numbering goes on.
+type Journal interface {
+	// Save replaces the entry of its session.
+	Save(entry JournalEntry) error
+	Delete(sessionId string) error
+	// Load returns the saved entries and the number of entries that could
+	// not be read.
+	Load() ([]JournalEntry, int, error)
+	SaveLocalSequenceNumber(localSequenceNumber int64) error
+	// LoadLocalSequenceNumber returns the last number saved, 0 if none.
+	LoadLocalSequenceNumber() (int64, error)
+}
+
+// FileJournal is a Journal appending each change to a local file, synced
+// before it returns. The file is rewritten with the saved entries only when
+// opened and once the changes outnumber them enough.
+//
+// It is safe for concurrent use.
+type FileJournal struct {
+	path string
+
+	mu                  sync.Mutex
+	file                *os.File
+	entries             map[string]JournalEntry
+	localSequenceNumber int64
+	// changes in the file, the last ones saving the entries included
+	changes int
+	// lines of the file that could not be read
+	lost int
+}
+
+// a change in the file, entry being nil for a deletion, or without session
+// identifier the local sequence number saved
+type journalChange struct {
+	SessionId           string        `json:",omitempty"`
+	Entry               *JournalEntry `json:",omitempty"`
+	LocalSequenceNumber int64         `json:",omitempty"`
+}
+
+// minimum number of changes before the file is rewritten
+const journalCompactionChanges = 1024
+
+// OpenFileJournal opens the journal kept in the file at path, created if it
+// does not exist, and reads its entries. A line that cannot be read loses the
+// entry it saved, a line cut by a crash while it was being written loses the
+// change it made.
+func OpenFileJournal(path string) (*FileJournal, error) {
+	file, err := os.OpenFile(path, os.O_RDONLY|os.O_CREATE, 0o666)
+	if err != nil {
+		return nil, err
+	}
+	j := &FileJournal{
+		path:    path,
+		file:    file,
+		entries: make(map[string]JournalEntry),
+	}
+
+	reader := bufio.NewReader(file)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			// a line without end is the change of a crashed write
+			break
+		}
+		if err != nil {
+			file.Close()
+			return nil, err
+		}
+		j.changes++
+
+		var change journalChange
+		if err := json.Unmarshal(line, &change); err != nil ||
+			change.SessionId == "" && change.LocalSequenceNumber <= 0 {
+			j.lost++
+			continue
+		}
+		if change.SessionId == "" {
+			j.localSequenceNumber = change.LocalSequenceNumber
+		} else if change.Entry == nil {
+			delete(j.entries, change.SessionId)
+		} else {
+			j.entries[change.SessionId] = *change.Entry
+		}
+	}
+
+	// drop the cut line, if any, and go on from a clean file
+	if err := j.compact(); err != nil {
+		j.file.Close()
+		return nil, err
+	}
+	return j, nil
+}
+
+func (j *FileJournal) Save(entry JournalEntry) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.entries[entry.SessionId] = entry
+	return j.write(journalChange{SessionId: entry.SessionId, Entry: &entry})
+}
+
+func (j *FileJournal) Delete(sessionId string) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if _, ok := j.entries[sessionId]; !ok {
+		return nil
+	}
+	delete(j.entries, sessionId)
+	return j.write(journalChange{SessionId: sessionId})
+}
+
+// Load returns the saved entries in the order of their session identifier.
+func (j *FileJournal) Load() ([]JournalEntry, int, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	entries := make([]JournalEntry, 0, len(j.entries))
+	for _, entry := range j.entries {
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(a, b int) bool {
+		return entries[a].SessionId < entries[b].SessionId
+	})
+	return entries, j.lost, nil
+}
+
+func (j *FileJournal) SaveLocalSequenceNumber(localSequenceNumber int64) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.localSequenceNumber = localSequenceNumber
+	return j.write(journalChange{LocalSequenceNumber: localSequenceNumber})
+}
+
+func (j *FileJournal) LoadLocalSequenceNumber() (int64, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	return j.localSequenceNumber, nil
+}
+
+// Close closes the file of the journal.
+func (j *FileJournal) Close() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	return j.file.Close()
+}
+
+func (j *FileJournal) write(change journalChange) error {
+	if j.changes >= journalCompactionChanges && j.changes >= 4*len(j.entries) {
+		return j.compact()
+	}
+
+	line, err := json.Marshal(change)
+	if err != nil {
+		return err
+	}
+	if _, err := j.file.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	j.changes++
+	return j.file.Sync()
+}
+
+// compact rewrites the file with the local sequence number and the saved
+// entries only. The new file replaces the old one once synced, a crash
+// leaving either of them.
+func (j *FileJournal) compact() error {
+	var buf []byte
+	changes := 0
+	if j.localSequenceNumber > 0 {
+		line, err := json.Marshal(journalChange{LocalSequenceNumber: j.localSequenceNumber})
+		if err != nil {
+			return err
+		}
+		buf = append(append(buf, line...), '\n')
+		changes++
+	}
+	sessionIds := make([]string, 0, len(j.entries))
+	for sessionId := range j.entries {
+		sessionIds = append(sessionIds, sessionId)
+	}
+	sort.Strings(sessionIds)
+	for _, sessionId := range sessionIds {
+		entry := j.entries[sessionId]
+		line, err := json.Marshal(journalChange{SessionId: sessionId, Entry: &entry})
+		if err != nil {
+			return err
+		}
+		buf = append(append(buf, line...), '\n')
+		changes++
+	}
+
+	if err := safeFile.Replace(j.path, buf); err != nil {
+		return fmt.Errorf("journal compaction: %v", err)
+	}
+	file, err := os.OpenFile(j.path, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		return fmt.Errorf("journal compaction: %v", err)
+	}
+	j.file.Close()
+	j.file = file
+	j.changes = changes
+	return nil
+}
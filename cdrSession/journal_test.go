@@ -0,0 +1,149 @@
+package cdrSession
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/free5gc/CDRUtil/cdrType"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileJournal(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "journal")
+	journal, err := OpenFileJournal(path)
+	require.NoError(t, err)
+
+	for _, sessionId := range []string{"session2", "session1", "session3"} {
+		require.NoError(t, journal.Save(JournalEntry{SessionId: sessionId, SequenceNumber: 1}))
+	}
+	require.NoError(t, journal.Save(JournalEntry{SessionId: "session1", SequenceNumber: 2}))
+	require.NoError(t, journal.Delete("session3"))
+	require.NoError(t, journal.Delete("session4"))
+	require.NoError(t, journal.SaveLocalSequenceNumber(7))
+	require.NoError(t, journal.Close())
+
+	// a corrupted line and a line cut by a crash
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	require.NoError(t, err)
+	_, err = file.WriteString("{\"SessionId\":\n{\"SessionId\":\"session2\"")
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	journal, err = OpenFileJournal(path)
+	require.NoError(t, err)
+	entries, lost, err := journal.Load()
+	require.NoError(t, err)
+	require.Equal(t, 1, lost)
+	require.Equal(t, []JournalEntry{
+		{SessionId: "session1", SequenceNumber: 2},
+		{SessionId: "session2", SequenceNumber: 1},
+	}, entries)
+	localSequenceNumber, err := journal.LoadLocalSequenceNumber()
+	require.NoError(t, err)
+	require.Equal(t, int64(7), localSequenceNumber)
+	require.NoError(t, journal.Close())
+
+	// the file was rewritten without the lost lines
+	journal, err = OpenFileJournal(path)
+	require.NoError(t, err)
+	entries, lost, err = journal.Load()
+	require.NoError(t, err)
+	require.Equal(t, 0, lost)
+	require.Len(t, entries, 2)
+	localSequenceNumber, err = journal.LoadLocalSequenceNumber()
+	require.NoError(t, err)
+	require.Equal(t, int64(7), localSequenceNumber)
+
+	// compaction
+	for i := 0; i < 2*journalCompactionChanges; i++ {
+		require.NoError(t, journal.Save(JournalEntry{SessionId: "session3", SequenceNumber: int64(i)}))
+	}
+	require.LessOrEqual(t, journal.changes, journalCompactionChanges)
+	require.NoError(t, journal.Close())
+	journal, err = OpenFileJournal(path)
+	require.NoError(t, err)
+	entries, _, err = journal.Load()
+	require.NoError(t, err)
+	require.Equal(t, int64(2*journalCompactionChanges-1), entries[2].SequenceNumber)
+	localSequenceNumber, err = journal.LoadLocalSequenceNumber()
+	require.NoError(t, err)
+	require.Equal(t, int64(7), localSequenceNumber)
+	require.NoError(t, journal.Close())
+}
+
+func TestManagerRecover(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "journal")
+	journal, err := OpenFileJournal(path)
+	require.NoError(t, err)
+
+	start := time.Date(2022, time.May, 4, 10, 0, 0, 0, time.UTC)
+	sink := &recordSink{}
+	manager := NewManager("chf", "SMF", sink)
+	manager.SetPartialRecordLimits(PartialRecordLimits{MaxContainers: 2})
+	manager.SetJournal(journal)
+
+	require.NoError(t, manager.Create("session1", chargingDataRequest(start, 0, 0)))
+	require.NoError(t, manager.Create("session2", chargingDataRequest(start, 0, 0)))
+	require.NoError(t, manager.Create("session3", chargingDataRequest(start, 0, 0)))
+	for i := 1; i <= 3; i++ {
+		require.NoError(t, manager.Update("session1",
+			chargingDataRequest(start.Add(time.Duration(i)*time.Minute), 1, 100)))
+	}
+	require.NoError(t, manager.Release("session3", chargingDataRequest(start.Add(time.Minute), 0, 0)))
+	require.Len(t, sink.records, 2)
+	require.NoError(t, journal.Close())
+
+	// restart, the entry of session2 being corrupted
+	journal, err = OpenFileJournal(path)
+	require.NoError(t, err)
+	entry := journal.entries["session2"]
+	entry.Record = []byte{0xff}
+	require.NoError(t, journal.Save(entry))
+
+	sink = &recordSink{}
+	manager = NewManager("chf", "SMF", sink)
+	manager.SetJournal(journal)
+	lost, err := manager.Recover()
+	require.NoError(t, err)
+	require.Equal(t, 1, lost)
+
+	require.Len(t, sink.records, 1)
+	record := sink.records[0]
+	require.Equal(t, cdrType.CauseForRecClosingPresentAbnormalRelease, record.CauseForRecClosing.Value)
+	require.True(t, *record.IncompleteCDRIndication.TerminationLost)
+	require.Equal(t, int64(2), *record.RecordSequenceNumber)
+	require.Equal(t, int64(60), record.Duration.Value)
+	require.Len(t, record.ListOfMultipleUnitUsage[0].UsedUnitContainers, 1)
+
+	entries, _, err := journal.Load()
+	require.NoError(t, err)
+	require.Empty(t, entries)
+
+	// the numbering goes on after the records of the previous run
+	require.Equal(t, int64(3), record.LocalRecordSequenceNumber.Value)
+	require.NoError(t, manager.Create("session4", chargingDataRequest(start, 0, 0)))
+	require.NoError(t, manager.Release("session4", chargingDataRequest(start.Add(time.Minute), 0, 0)))
+	require.Len(t, sink.records, 2)
+	require.Equal(t, int64(4), sink.records[1].LocalRecordSequenceNumber.Value)
+	require.NoError(t, journal.Close())
+
+	// and after a restart without open sessions
+	journal, err = OpenFileJournal(path)
+	require.NoError(t, err)
+	sink = &recordSink{}
+	manager = NewManager("chf", "SMF", sink)
+	manager.SetJournal(journal)
+	lost, err = manager.Recover()
+	require.NoError(t, err)
+	require.Equal(t, 0, lost)
+	require.NoError(t, manager.Create("session5", chargingDataRequest(start, 0, 0)))
+	require.NoError(t, manager.Release("session5", chargingDataRequest(start.Add(time.Minute), 0, 0)))
+	require.Equal(t, int64(5), sink.records[0].LocalRecordSequenceNumber.Value)
+	require.NoError(t, journal.Close())
+}
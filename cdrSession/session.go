@@ -0,0 +1,512 @@
+// Package cdrSession keeps the charging records of the sessions opened on a
+// CHF by the Create, Update and Release operations of Nchf_ConvergedCharging.
+package cdrSession
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/free5gc/CDRUtil/asn"
+	"github.com/free5gc/CDRUtil/cdrConvert"
+	"github.com/free5gc/CDRUtil/cdrType"
+	"github.com/free5gc/openapi/models"
+)
+
+// Sink receives the records closed by a Manager, as the sinks of cdrSink do.
+type Sink interface {
+	Write(record cdrType.ChargingRecord) error
+}
+
+// PartialRecordLimits are the limits beyond which the open record of a
+// session is closed as a partial record, the next one opening at once. A zero
+// limit is not enforced.
+type PartialRecordLimits struct {
+	// closed with timeLimit
+	MaxDuration time.Duration
+	// octets of total data volume, closed with volumeLimit
+	MaxVolume int64
+	// used unit containers of all rating groups, closed with maxChangeCond
+	MaxContainers int
+}
+
+// Manager maintains the open record of each charging session, keyed by the
+// charging session identifier, and writes it to its sink once closed.
+//
+// The records of a session split into partial records are numbered by
+// RecordSequenceNumber from 1 on, all records written by the manager by
+// LocalRecordSequenceNumber from 1 on.
+//
+// It is safe for concurrent use.
+type Manager struct {
+	chfName           string
+	nodeFunctionality models.NodeFunctionality
+	sink              Sink
+
+	mu                  sync.Mutex
+	limits              PartialRecordLimits
+	journal             Journal
+	sessions            map[string]*session
+	localSequenceNumber int64
+}
+
+type session struct {
+	sessionId      string
+	record         cdrType.ChargingRecord
+	openingTime    time.Time
+	updateTime     time.Time
+	sequenceNumber int64
+}
+
+// NewManager returns a manager producing the records of the CHF named chfName
+// for sessions of consumers of the given node functionality.
+func NewManager(chfName string, nodeFunctionality models.NodeFunctionality, sink Sink) *Manager {
+	return &Manager{
+		chfName:           chfName,
+		nodeFunctionality: nodeFunctionality,
+		sink:              sink,
+		sessions:          make(map[string]*session),
+	}
+}
+
+// SetPartialRecordLimits sets the limits checked by the next updates.
+func (m *Manager) SetPartialRecordLimits(limits PartialRecordLimits) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.limits = limits
+}
+
+// SetJournal sets the journal saving the open sessions from now on, see
+// Recover for the sessions it already has.
+func (m *Manager) SetJournal(journal Journal) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.journal = journal
+}
+
+// Recover closes the records of the sessions left open in the journal by a
+// previous run, with abnormalRelease at their last update and an
+// IncompleteCDRIndication of their lost termination. It returns the number of
+// records that could not be recovered, to be reported by the LostCdrIndicator
+// of the next CDR file; the number is unknown if the journal cannot be
+// loaded. A record the sink fails to write stays in the journal. The
+// LocalRecordSequenceNumber goes on from the last one saved in the journal.
+func (m *Manager) Recover() (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.journal == nil {
+		return 0, fmt.Errorf("no journal")
+	}
+	localSequenceNumber, err := m.journal.LoadLocalSequenceNumber()
+	if err != nil {
+		return 0, err
+	}
+	if localSequenceNumber > m.localSequenceNumber {
+		m.localSequenceNumber = localSequenceNumber
+	}
+	entries, lost, err := m.journal.Load()
+	if err != nil {
+		return 0, err
+	}
+
+	for _, entry := range entries {
+		var record cdrType.ChargingRecord
+		if err := asn.UnmarshalWithParams(entry.Record, &record, ""); err != nil {
+			lost++
+			if err := m.journal.Delete(entry.SessionId); err != nil {
+				return lost, err
+			}
+			continue
+		}
+		terminationLost := true
+		record.IncompleteCDRIndication = &cdrType.IncompleteCDRIndication{TerminationLost: &terminationLost}
+		s := &session{
+			sessionId:      entry.SessionId,
+			record:         record,
+			openingTime:    entry.OpeningTime,
+			updateTime:     entry.UpdateTime,
+			sequenceNumber: entry.SequenceNumber,
+		}
+		if err := m.close(s, s.updateTime, cdrType.CauseForRecClosingPresentAbnormalRelease, false); err != nil {
+			return lost, err
+		}
+		if err := m.journal.Delete(entry.SessionId); err != nil {
+			return lost, err
+		}
+	}
+	return lost, nil
+}
+
+// Create opens the record of the session sessionId at the invocation time
+// stamp of request, with the unit usage and the PDU session it reports. Like
+// on Update, the record may be closed at once as a partial record. The session
+// is saved in the journal, if any, before Create returns.
+func (m *Manager) Create(sessionId string, request models.ChargingDataRequest) error {
+	record, err := cdrConvert.OneTimeEventRecordToCdr(m.chfName, request, m.nodeFunctionality)
+	if err != nil {
+		return err
+	}
+	record.ChargingSessionIdentifier = &cdrType.ChargingSessionIdentifier{Value: asn.OctetString(sessionId)}
+	if request.ServiceSpecificationInfo != "" {
+		info := asn.OctetString(request.ServiceSpecificationInfo)
+		record.ServiceSpecificationInformation = &info
+	}
+	if request.PDUSessionChargingInformation != nil {
+		pduSession, err := cdrConvert.PduSessionChargingInformationToCdr(*request.PDUSessionChargingInformation)
+		if err != nil {
+			return fmt.Errorf("pDUSessionChargingInformation: %v", err)
+		}
+		record.PDUSessionChargingInformation = &pduSession
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.sessions[sessionId]; ok {
+		return fmt.Errorf("charging session %q already exists", sessionId)
+	}
+	s := &session{
+		sessionId:      sessionId,
+		record:         record,
+		openingTime:    *request.InvocationTimeStamp,
+		updateTime:     *request.InvocationTimeStamp,
+		sequenceNumber: 1,
+	}
+	if err := s.update(request); err != nil {
+		return err
+	}
+	if cause, ok := m.limitReached(s, s.updateTime); ok {
+		m.sessions[sessionId] = s
+		return m.closePartial(s, s.updateTime, cause)
+	}
+	if err := m.save(s); err != nil {
+		return err
+	}
+	m.sessions[sessionId] = s
+
+	return nil
+}
+
+// Update adds the unit usage and triggers reported by request to the record
+// of the session sessionId. The record is then closed as a partial record at
+// the invocation time stamp of request if it reaches a partial record limit.
+// The session is saved in the journal, if any, before Update returns.
+func (m *Manager) Update(sessionId string, request models.ChargingDataRequest) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s, ok := m.sessions[sessionId]
+	if !ok {
+		return fmt.Errorf("unknown charging session %q", sessionId)
+	}
+	if request.InvocationTimeStamp == nil {
+		return fmt.Errorf("missing invocationTimeStamp")
+	}
+	if err := s.update(request); err != nil {
+		return err
+	}
+	s.updateTime = *request.InvocationTimeStamp
+
+	if cause, ok := m.limitReached(s, s.updateTime); ok {
+		return m.closePartial(s, s.updateTime, cause)
+	}
+	return m.save(s)
+}
+
+// Release adds the final unit usage reported by request to the record of the
+// session sessionId and closes it with normalRelease at the invocation time
+// stamp of request. If the sink fails to write the record, the session stays
+// open with the final unit usage added, saved in the journal, and can be
+// released again by a request reporting no further usage.
+func (m *Manager) Release(sessionId string, request models.ChargingDataRequest) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s, ok := m.sessions[sessionId]
+	if !ok {
+		return fmt.Errorf("unknown charging session %q", sessionId)
+	}
+	if request.InvocationTimeStamp == nil {
+		return fmt.Errorf("missing invocationTimeStamp")
+	}
+	if err := s.update(request); err != nil {
+		return err
+	}
+	s.updateTime = *request.InvocationTimeStamp
+
+	err := m.close(s, *request.InvocationTimeStamp, cdrType.CauseForRecClosingPresentNormalRelease, false)
+	if err != nil {
+		if saveErr := m.save(s); saveErr != nil {
+			return fmt.Errorf("%v, and saving the session: %v", err, saveErr)
+		}
+		return err
+	}
+	delete(m.sessions, sessionId)
+	return m.delete(s)
+}
+
+// CloseExpired closes as partial records, with timeLimit, the records open
+// for the maximum duration or longer at now. It returns the first error of the
+// sink, if any, after closing all of them.
+func (m *Manager) CloseExpired(now time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.limits.MaxDuration <= 0 {
+		return nil
+	}
+	var firstErr error
+	for _, sessionId := range m.sessionIds() {
+		s := m.sessions[sessionId]
+		if now.Sub(s.openingTime) < m.limits.MaxDuration {
+			continue
+		}
+		if err := m.closePartial(s, now, cdrType.CauseForRecClosingPresentTimeLimit); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// CloseRecord closes the record of the session sessionId as a partial record
+// at now, with managementIntervention.
+func (m *Manager) CloseRecord(sessionId string, now time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s, ok := m.sessions[sessionId]
+	if !ok {
+		return fmt.Errorf("unknown charging session %q", sessionId)
+	}
+	return m.closePartial(s, now, cdrType.CauseForRecClosingPresentManagementIntervention)
+}
+
+// CloseRecords closes the records of all sessions as partial records at now,
+// with managementIntervention. It returns the first error of the sink, if
+// any, after closing all of them.
+func (m *Manager) CloseRecords(now time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var firstErr error
+	for _, sessionId := range m.sessionIds() {
+		err := m.closePartial(m.sessions[sessionId], now, cdrType.CauseForRecClosingPresentManagementIntervention)
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// sessionIds returns the identifiers of the open sessions in order, so that
+// records closed together are written in the same order on every run.
+func (m *Manager) sessionIds() []string {
+	sessionIds := make([]string, 0, len(m.sessions))
+	for sessionId := range m.sessions {
+		sessionIds = append(sessionIds, sessionId)
+	}
+	sort.Strings(sessionIds)
+	return sessionIds
+}
+
+// Sessions returns the number of open sessions.
+func (m *Manager) Sessions() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return len(m.sessions)
+}
+
+// close sets the members of the record of s known when it closes at
+// closingTime for cause and writes it. The record is numbered within the
+// session if it is a partial record or follows one; the local sequence
+// number is used up only once the record is written. It is saved in the
+// journal, if any, before, so that a crash may skip a number but never
+// reuse one.
+func (m *Manager) close(s *session, closingTime time.Time, cause int64, partial bool) error {
+	record := s.record
+	duration := closingTime.Sub(s.openingTime)
+	if duration < 0 {
+		duration = 0
+	}
+	record.Duration = cdrType.CallDuration{Value: int64(duration.Round(time.Second) / time.Second)}
+	record.CauseForRecClosing = cdrType.CauseForRecClosing{Value: cause}
+	if partial || s.sequenceNumber > 1 {
+		sequenceNumber := s.sequenceNumber
+		record.RecordSequenceNumber = &sequenceNumber
+	}
+	record.LocalRecordSequenceNumber = &cdrType.LocalSequenceNumber{Value: m.localSequenceNumber + 1}
+
+	if m.journal != nil {
+		if err := m.journal.SaveLocalSequenceNumber(m.localSequenceNumber + 1); err != nil {
+			return err
+		}
+	}
+	if err := m.sink.Write(record); err != nil {
+		return err
+	}
+	m.localSequenceNumber++
+	return nil
+}
+
+// limitReached returns the cause for closing the record of s at now if it
+// reaches a partial record limit.
+func (m *Manager) limitReached(s *session, now time.Time) (int64, bool) {
+	switch {
+	case m.limits.MaxDuration > 0 && now.Sub(s.openingTime) >= m.limits.MaxDuration:
+		return cdrType.CauseForRecClosingPresentTimeLimit, true
+	case m.limits.MaxVolume > 0 && s.volume() >= m.limits.MaxVolume:
+		return cdrType.CauseForRecClosingPresentVolumeLimit, true
+	case m.limits.MaxContainers > 0 && s.containers() >= m.limits.MaxContainers:
+		return cdrType.CauseForRecClosingPresentMaxChangeCond, true
+	}
+	return 0, false
+}
+
+// closePartial closes the record of s as a partial record and opens the next
+// one of the session at closingTime, saved in the journal. If the sink fails
+// to write the record, it stays open as it is, to be closed by the next
+// update or expiry.
+func (m *Manager) closePartial(s *session, closingTime time.Time, cause int64) error {
+	if err := m.close(s, closingTime, cause, true); err != nil {
+		if saveErr := m.save(s); saveErr != nil {
+			return fmt.Errorf("%v, and saving the session: %v", err, saveErr)
+		}
+		return err
+	}
+
+	// the closed record keeps the unit usage and triggers written so far
+	s.record.ListOfMultipleUnitUsage = nil
+	s.record.Triggers = append([]cdrType.Trigger(nil), s.record.Triggers...)
+	s.record.RecordOpeningTime = cdrConvert.TimeStampToCdr(&closingTime)
+	s.openingTime = closingTime
+	s.updateTime = closingTime
+	s.sequenceNumber++
+
+	return m.save(s)
+}
+
+// save saves s in the journal, if any.
+func (m *Manager) save(s *session) error {
+	if m.journal == nil {
+		return nil
+	}
+	record, err := asn.BerMarshalWithParams(s.record, "")
+	if err != nil {
+		return err
+	}
+	return m.journal.Save(JournalEntry{
+		SessionId:      s.sessionId,
+		OpeningTime:    s.openingTime,
+		UpdateTime:     s.updateTime,
+		SequenceNumber: s.sequenceNumber,
+		Record:         record,
+	})
+}
+
+// delete removes s from the journal, if any.
+func (m *Manager) delete(s *session) error {
+	if m.journal == nil {
+		return nil
+	}
+	return m.journal.Delete(s.sessionId)
+}
+
+// update merges the unit usage and triggers reported by request into the
+// record of s, the used unit containers of a rating group being appended to
+// those it already has.
+func (s *session) update(request models.ChargingDataRequest) error {
+	multipleUnitUsage, err := cdrConvert.MultiUnitUsageToCdr(request.MultipleUnitUsage)
+	if err != nil {
+		return err
+	}
+
+	s.record.ListOfMultipleUnitUsage = mergeUnitUsage(s.record.ListOfMultipleUnitUsage, multipleUnitUsage)
+	s.record.Triggers = mergeTriggers(s.record.Triggers, cdrConvert.TriggersToCdr(request.Triggers))
+
+	return nil
+}
+
+// volume returns the total data volume of the record of s, counting the
+// uplink and downlink volumes of the containers without a total.
+func (s *session) volume() int64 {
+	var volume int64
+	for _, usage := range s.record.ListOfMultipleUnitUsage {
+		for _, container := range usage.UsedUnitContainers {
+			switch {
+			case container.DataTotalVolume != nil:
+				volume += container.DataTotalVolume.Value
+			default:
+				if container.DataVolumeUplink != nil {
+					volume += container.DataVolumeUplink.Value
+				}
+				if container.DataVolumeDownlink != nil {
+					volume += container.DataVolumeDownlink.Value
+				}
+			}
+		}
+	}
+	return volume
+}
+
+func (s *session) containers() int {
+	var containers int
+	for _, usage := range s.record.ListOfMultipleUnitUsage {
+		containers += len(usage.UsedUnitContainers)
+	}
+	return containers
+}
+
+// mergeUnitUsage appends the used unit containers of multipleUnitUsage to
+// those of the same rating group in list, the rating groups not in list being
+// added to it.
+func mergeUnitUsage(list, multipleUnitUsage []cdrType.MultipleUnitUsage) []cdrType.MultipleUnitUsage {
+	for _, usage := range multipleUnitUsage {
+		merged := false
+		for i := range list {
+			recorded := &list[i]
+			if recorded.RatingGroup != usage.RatingGroup {
+				continue
+			}
+			recorded.UsedUnitContainers = append(recorded.UsedUnitContainers, usage.UsedUnitContainers...)
+			if usage.UPFID != nil {
+				recorded.UPFID = usage.UPFID
+			}
+			if usage.MultihomedPDUAddress != nil {
+				recorded.MultihomedPDUAddress = usage.MultihomedPDUAddress
+			}
+			merged = true
+			break
+		}
+		if !merged {
+			// the containers of list are appended to, not those of the caller
+			usage.UsedUnitContainers = append([]cdrType.UsedUnitContainer(nil), usage.UsedUnitContainers...)
+			list = append(list, usage)
+		}
+	}
+	return list
+}
+
+// mergeTriggers adds the triggers not in list to it.
+func mergeTriggers(list, triggers []cdrType.Trigger) []cdrType.Trigger {
+	for _, trigger := range triggers {
+		if !hasTrigger(list, trigger) {
+			list = append(list, trigger)
+		}
+	}
+	return list
+}
+
+func hasTrigger(triggers []cdrType.Trigger, trigger cdrType.Trigger) bool {
+	for _, t := range triggers {
+		if reflect.DeepEqual(t, trigger) {
+			return true
+		}
+	}
+	return false
+}
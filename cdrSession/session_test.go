@@ -0,0 +1,241 @@
+package cdrSession
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/free5gc/CDRUtil/asn"
+	"github.com/free5gc/CDRUtil/cdrType"
+	"github.com/free5gc/openapi/models"
+	"github.com/stretchr/testify/require"
+)
+
+// recordSink keeps the records written, failing the writes while err is set.
+type recordSink struct {
+	records []cdrType.ChargingRecord
+	err     error
+}
+
+func (s *recordSink) Write(record cdrType.ChargingRecord) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.records = append(s.records, record)
+	return nil
+}
+
+func chargingDataRequest(t time.Time, ratingGroup int32, volume int32, triggers ...models.TriggerType) models.ChargingDataRequest {
+	request := models.ChargingDataRequest{
+		SubscriberIdentifier: "imsi-208930000000001",
+		NfConsumerIdentification: &models.NfIdentification{
+			NFName:            "smf",
+			NodeFunctionality: "SMF",
+		},
+		InvocationTimeStamp: &t,
+		ChargingId:          7,
+	}
+	if ratingGroup != 0 {
+		request.MultipleUnitUsage = []models.MultipleUnitUsage{{
+			RatingGroup: ratingGroup,
+			UsedUnitContainer: []models.UsedUnitContainer{{
+				TotalVolume:         volume,
+				LocalSequenceNumber: 1,
+			}},
+		}}
+	}
+	for _, trigger := range triggers {
+		request.Triggers = append(request.Triggers, models.Trigger{TriggerType: trigger})
+	}
+	return request
+}
+
+func TestManager(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2022, time.May, 4, 10, 0, 0, 0, time.UTC)
+	sink := &recordSink{}
+	manager := NewManager("chf", "SMF", sink)
+
+	request := chargingDataRequest(start, 0, 0)
+	request.PDUSessionChargingInformation = &models.PduSessionChargingInformation{
+		ChargingId: 7,
+		UetimeZone: "+02:00+1",
+		PduSessionInformation: &models.PduSessionInformation{
+			PduSessionID: 1,
+			DnnId:        "internet",
+		},
+	}
+	require.NoError(t, manager.Create("session1", request))
+	require.NoError(t, manager.Create("session2", chargingDataRequest(start, 0, 0)))
+	require.Error(t, manager.Create("session1", chargingDataRequest(start, 0, 0)))
+	request.PDUSessionChargingInformation = &models.PduSessionChargingInformation{ChargingId: 7}
+	require.Error(t, manager.Create("session5", request))
+	require.Equal(t, 2, manager.Sessions())
+
+	require.NoError(t, manager.Update("session1", chargingDataRequest(start.Add(time.Minute), 1, 100, "VOLUME_LIMIT")))
+	require.NoError(t, manager.Update("session1", chargingDataRequest(start.Add(2*time.Minute), 2, 200, "VOLUME_LIMIT")))
+	require.Error(t, manager.Update("session3", chargingDataRequest(start, 1, 100)))
+	require.Empty(t, sink.records)
+
+	// the local sequence number of a record the sink fails to write is not used up
+	require.NoError(t, manager.Create("session4", chargingDataRequest(start, 0, 0)))
+	sink.err = errors.New("disk full")
+	require.Error(t, manager.Release("session4", chargingDataRequest(start.Add(time.Minute), 0, 0)))
+	sink.err = nil
+
+	require.NoError(t, manager.Release("session2", chargingDataRequest(start.Add(30*time.Second), 0, 0)))
+	require.NoError(t, manager.Release("session1", chargingDataRequest(start.Add(90*time.Minute), 1, 300)))
+	require.Error(t, manager.Release("session1", chargingDataRequest(start.Add(90*time.Minute), 1, 300)))
+	// session4, whose release failed, is still open
+	require.Equal(t, 1, manager.Sessions())
+
+	require.Len(t, sink.records, 2)
+	for i, record := range sink.records {
+		require.Equal(t, int64(i+1), record.LocalRecordSequenceNumber.Value)
+		// a session closed by a single record does not number it
+		require.Nil(t, record.RecordSequenceNumber)
+		require.Equal(t, cdrType.CauseForRecClosingPresentNormalRelease, record.CauseForRecClosing.Value)
+		_, err := asn.BerMarshalWithParams(record, "")
+		require.NoError(t, err)
+	}
+
+	record := sink.records[1]
+	require.Equal(t, asn.OctetString("session1"), record.ChargingSessionIdentifier.Value)
+	require.Equal(t, int64(90*60), record.Duration.Value)
+	require.Len(t, record.Triggers, 1)
+	require.Len(t, record.ListOfMultipleUnitUsage, 2)
+	require.Equal(t, int64(1), record.ListOfMultipleUnitUsage[0].RatingGroup.Value)
+	require.Len(t, record.ListOfMultipleUnitUsage[0].UsedUnitContainers, 2)
+	require.Equal(t, int64(300), record.ListOfMultipleUnitUsage[0].UsedUnitContainers[1].DataTotalVolume.Value)
+	require.Equal(t, int64(2), record.ListOfMultipleUnitUsage[1].RatingGroup.Value)
+	offset, dst, err := record.PDUSessionChargingInformation.UETimeZone.Decode()
+	require.NoError(t, err)
+	require.Equal(t, 2*3600, offset)
+	require.Equal(t, cdrType.DaylightSavingPlusOneHour, dst)
+	require.Equal(t, "internet", string(record.PDUSessionChargingInformation.DataNetworkNameIdentifier.Value))
+
+	require.Equal(t, int64(30), sink.records[0].Duration.Value)
+	require.Empty(t, sink.records[0].ListOfMultipleUnitUsage)
+}
+
+func TestManagerPartialRecords(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2022, time.May, 4, 10, 0, 0, 0, time.UTC)
+	sink := &recordSink{}
+	manager := NewManager("chf", "SMF", sink)
+	manager.SetPartialRecordLimits(PartialRecordLimits{
+		MaxDuration:   time.Hour,
+		MaxVolume:     1000,
+		MaxContainers: 3,
+	})
+
+	require.NoError(t, manager.Create("session1", chargingDataRequest(start, 0, 0)))
+	// volumeLimit
+	require.NoError(t, manager.Update("session1", chargingDataRequest(start.Add(time.Minute), 1, 600)))
+	require.NoError(t, manager.Update("session1", chargingDataRequest(start.Add(2*time.Minute), 1, 600)))
+	// maxChangeCond
+	for i := 3; i <= 5; i++ {
+		require.NoError(t, manager.Update("session1",
+			chargingDataRequest(start.Add(time.Duration(i)*time.Minute), 2, 10, "QUOTA_THRESHOLD")))
+	}
+	// timeLimit
+	require.NoError(t, manager.CloseExpired(start.Add(time.Hour)))
+	require.NoError(t, manager.CloseExpired(start.Add(time.Hour+5*time.Minute)))
+	// managementIntervention
+	require.NoError(t, manager.CloseRecord("session1", start.Add(time.Hour+15*time.Minute)))
+	require.Error(t, manager.CloseRecord("session2", start))
+	// timeLimit
+	require.NoError(t, manager.Update("session1", chargingDataRequest(start.Add(2*time.Hour+15*time.Minute), 1, 5)))
+	require.NoError(t, manager.Release("session1", chargingDataRequest(start.Add(2*time.Hour+25*time.Minute), 0, 0)))
+
+	causes := []int64{
+		cdrType.CauseForRecClosingPresentVolumeLimit,
+		cdrType.CauseForRecClosingPresentMaxChangeCond,
+		cdrType.CauseForRecClosingPresentTimeLimit,
+		cdrType.CauseForRecClosingPresentManagementIntervention,
+		cdrType.CauseForRecClosingPresentTimeLimit,
+		cdrType.CauseForRecClosingPresentNormalRelease,
+	}
+	durations := []int64{120, 180, 3600, 600, 3600, 600}
+	require.Len(t, sink.records, len(causes))
+	for i, record := range sink.records {
+		require.Equal(t, causes[i], record.CauseForRecClosing.Value, "record %d", i+1)
+		require.Equal(t, durations[i], record.Duration.Value, "record %d", i+1)
+		require.Equal(t, int64(i+1), *record.RecordSequenceNumber)
+		require.Equal(t, int64(i+1), record.LocalRecordSequenceNumber.Value)
+	}
+	require.Len(t, sink.records[0].ListOfMultipleUnitUsage[0].UsedUnitContainers, 2)
+	require.Len(t, sink.records[1].ListOfMultipleUnitUsage[0].UsedUnitContainers, 3)
+	require.Empty(t, sink.records[2].ListOfMultipleUnitUsage)
+	require.Len(t, sink.records[4].ListOfMultipleUnitUsage, 1)
+	require.Empty(t, sink.records[5].ListOfMultipleUnitUsage)
+	require.Len(t, sink.records[5].Triggers, 1)
+}
+
+func TestManagerReleaseFailure(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2022, time.May, 4, 10, 0, 0, 0, time.UTC)
+	journal, err := OpenFileJournal(filepath.Join(t.TempDir(), "journal"))
+	require.NoError(t, err)
+	defer journal.Close()
+	sink := &recordSink{}
+	manager := NewManager("chf", "SMF", sink)
+	manager.SetJournal(journal)
+
+	require.NoError(t, manager.Create("session1", chargingDataRequest(start, 0, 0)))
+	sink.err = errors.New("disk full")
+	require.Error(t, manager.Release("session1", chargingDataRequest(start.Add(time.Minute), 1, 300)))
+
+	// the session stays open, saved with the final usage
+	require.Equal(t, 1, manager.Sessions())
+	entries, _, err := journal.Load()
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, start.Add(time.Minute), entries[0].UpdateTime)
+
+	sink.err = nil
+	require.NoError(t, manager.Release("session1", chargingDataRequest(start.Add(2*time.Minute), 0, 0)))
+	require.Equal(t, 0, manager.Sessions())
+	require.Len(t, sink.records, 1)
+	record := sink.records[0]
+	require.Equal(t, cdrType.CauseForRecClosingPresentNormalRelease, record.CauseForRecClosing.Value)
+	require.Len(t, record.ListOfMultipleUnitUsage[0].UsedUnitContainers, 1)
+	entries, _, err = journal.Load()
+	require.NoError(t, err)
+	require.Empty(t, entries)
+}
+
+func TestManagerPartialRecordFailure(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2022, time.May, 4, 10, 0, 0, 0, time.UTC)
+	sink := &recordSink{err: errors.New("disk full")}
+	manager := NewManager("chf", "SMF", sink)
+	manager.SetPartialRecordLimits(PartialRecordLimits{MaxVolume: 1000})
+
+	require.NoError(t, manager.Create("session1", chargingDataRequest(start, 0, 0)))
+	require.Error(t, manager.Update("session1", chargingDataRequest(start.Add(time.Minute), 1, 1200)))
+	require.Error(t, manager.CloseRecord("session1", start.Add(2*time.Minute)))
+
+	// the record stays open and is closed by the next update
+	sink.err = nil
+	require.NoError(t, manager.Update("session1", chargingDataRequest(start.Add(3*time.Minute), 1, 100)))
+	require.NoError(t, manager.Release("session1", chargingDataRequest(start.Add(4*time.Minute), 0, 0)))
+
+	require.Len(t, sink.records, 2)
+	record := sink.records[0]
+	require.Equal(t, cdrType.CauseForRecClosingPresentVolumeLimit, record.CauseForRecClosing.Value)
+	require.Equal(t, int64(180), record.Duration.Value)
+	require.Equal(t, int64(1), *record.RecordSequenceNumber)
+	require.Equal(t, int64(1), record.LocalRecordSequenceNumber.Value)
+	require.Len(t, record.ListOfMultipleUnitUsage[0].UsedUnitContainers, 2)
+
+	record = sink.records[1]
+	require.Equal(t, int64(2), *record.RecordSequenceNumber)
+	require.Equal(t, int64(60), record.Duration.Value)
+	require.Empty(t, record.ListOfMultipleUnitUsage)
+}
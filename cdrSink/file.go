@@ -0,0 +1,71 @@
+package cdrSink
+
+import (
+	"sync"
+	"time"
+
+	"github.com/free5gc/CDRUtil/cdrFile"
+	"github.com/free5gc/CDRUtil/cdrType"
+	"github.com/free5gc/CDRUtil/internal/safeFile"
+)
+
+// FileSink writes its records to a single CDR file, rewritten as a whole by
+// each flush with the normal closure as closure reason.
+//
+// It is safe for concurrent use.
+type FileSink struct {
+	path string
+
+	mu     sync.Mutex
+	file   *openFile
+	closed bool
+}
+
+// NewFileSink returns a sink writing the CDR file at path, replacing any file
+// there on the first flush. The sequence number of the file is the one of
+// header.
+func NewFileSink(path string, header Header) *FileSink {
+	return &FileSink{
+		path: path,
+		file: newOpenFile(header, header.File.FileSequenceNumber, time.Now()),
+	}
+}
+
+func (s *FileSink) Write(record cdrType.ChargingRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return ErrClosed
+	}
+	cdr, err := s.file.encodeCdr(record)
+	if err != nil {
+		return err
+	}
+	s.file.add(cdr, time.Now())
+	return nil
+}
+
+func (s *FileSink) Flush() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return ErrClosed
+	}
+	return safeFile.Replace(s.path, s.file.bytes(cdrFile.NormalClosure))
+}
+
+func (s *FileSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return nil
+	}
+	if err := safeFile.Replace(s.path, s.file.bytes(cdrFile.NormalClosure)); err != nil {
+		return err
+	}
+	s.closed = true
+	return nil
+}
@@ -0,0 +1,146 @@
+// Package cdrSink stores the charging records produced by a CHF, so that the
+// code producing them does not deal with CDR files itself.
+package cdrSink
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/free5gc/CDRUtil/asn"
+	"github.com/free5gc/CDRUtil/cdrFile"
+	"github.com/free5gc/CDRUtil/cdrType"
+)
+
+// Sink stores charging records. A record written is only sure to be stored
+// once the sink is flushed.
+//
+// Every Sink is a cdrSession.Sink.
+type Sink interface {
+	Write(record cdrType.ChargingRecord) error
+	Flush() error
+	// Close flushes the sink, which takes no more records.
+	Close() error
+}
+
+// ErrClosed is returned by the sinks written after they are closed.
+var ErrClosed = errors.New("sink closed")
+
+// Header is the template of the headers of the CDR files written by a sink.
+// The sink sets the lengths, time stamps, CDR count and closure reason of the
+// file headers, and the length and data record format of the CDR headers, the
+// records being BER encoded.
+type Header struct {
+	File cdrFile.CdrFileHeader
+	Cdr  cdrFile.CdrHeader
+}
+
+// MemorySink keeps the records written in memory, as for tests.
+//
+// It is safe for concurrent use.
+type MemorySink struct {
+	mu      sync.Mutex
+	records []cdrType.ChargingRecord
+	closed  bool
+}
+
+// NewMemorySink returns an empty memory sink.
+func NewMemorySink() *MemorySink {
+	return &MemorySink{}
+}
+
+func (s *MemorySink) Write(record cdrType.ChargingRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return ErrClosed
+	}
+	s.records = append(s.records, record)
+	return nil
+}
+
+func (s *MemorySink) Flush() error {
+	return nil
+}
+
+func (s *MemorySink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.closed = true
+	return nil
+}
+
+// Records returns the records written so far, in order.
+func (s *MemorySink) Records() []cdrType.ChargingRecord {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]cdrType.ChargingRecord(nil), s.records...)
+}
+
+// openFile is a CDR file being filled by a sink.
+type openFile struct {
+	header Header
+	file   cdrFile.CDRFile
+	// length of the CDRs, their headers included
+	length int
+}
+
+func newOpenFile(header Header, sequenceNumber uint32, now time.Time) *openFile {
+	f := &openFile{header: header}
+	f.file.Hdr = header.File
+	f.file.Hdr.FileSequenceNumber = sequenceNumber
+	f.file.Hdr.FileOpeningTimestamp = cdrFile.NewCdrHdrTimeStamp(now)
+	f.file.Hdr.TimestampWhenLastCdrWasAppendedToFIle = f.file.Hdr.FileOpeningTimestamp
+	return f
+}
+
+// encodeCdr returns the CDR of the BER encoding of record.
+func (f *openFile) encodeCdr(record cdrType.ChargingRecord) (cdrFile.CDR, error) {
+	data, err := asn.BerMarshalWithParams(record, "")
+	if err != nil {
+		return cdrFile.CDR{}, err
+	}
+	if len(data) > 0xffff {
+		return cdrFile.CDR{}, fmt.Errorf("record of %d octets exceeds the CDR length limit", len(data))
+	}
+	cdr := cdrFile.CDR{Hdr: f.header.Cdr, CdrByte: data}
+	cdr.Hdr.CdrLength = uint16(len(data))
+	cdr.Hdr.DataRecordFormat = cdrFile.BasicEncodingRules
+	return cdr, nil
+}
+
+func cdrLength(cdr cdrFile.CDR) int {
+	return len(cdr.Hdr.Encoding()) + len(cdr.CdrByte)
+}
+
+func (f *openFile) add(cdr cdrFile.CDR, now time.Time) {
+	f.file.CdrList = append(f.file.CdrList, cdr)
+	f.file.Hdr.NumberOfCdrsInFile++
+	f.file.Hdr.TimestampWhenLastCdrWasAppendedToFIle = cdrFile.NewCdrHdrTimeStamp(now)
+	f.length += cdrLength(cdr)
+}
+
+// headerLength returns the length of the file header.
+func (f *openFile) headerLength() int {
+	hdr := f.file.Hdr
+	hdr.HeaderLength = 0xffffffff
+	return len(hdr.Encoding())
+}
+
+// size returns the length of the file.
+func (f *openFile) size() int {
+	return f.headerLength() + f.length
+}
+
+// bytes returns the encoding of the file closed for reason.
+func (f *openFile) bytes(reason cdrFile.FileClosureTriggerReasonType) []byte {
+	file := f.file
+	file.Hdr.FileClosureTriggerReason = reason
+	file.Hdr.HeaderLength = uint32(f.headerLength())
+	file.Hdr.FileLength = file.Hdr.HeaderLength + uint32(f.length)
+	return file.Bytes()
+}
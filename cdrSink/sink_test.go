@@ -0,0 +1,242 @@
+package cdrSink
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/free5gc/CDRUtil/asn"
+	"github.com/free5gc/CDRUtil/cdrFile"
+	"github.com/free5gc/CDRUtil/cdrType"
+	"github.com/free5gc/CDRUtil/internal/cdrTest"
+	"github.com/stretchr/testify/require"
+)
+
+var header = Header{
+	File: cdrFile.CdrFileHeader{
+		HighReleaseIdentifier: 7,
+		HighVersionIdentifier: 1,
+		LowReleaseIdentifier:  7,
+		LowVersionIdentifier:  1,
+		FileSequenceNumber:    5,
+	},
+	Cdr: cdrFile.CdrHeader{
+		ReleaseIdentifier: cdrFile.BeyondRel9,
+		TsNumber:          cdrFile.TS32255,
+	},
+}
+
+func decode(t *testing.T, path string) (cdrFile.CDRFile, []cdrType.ChargingRecord) {
+	var file cdrFile.CDRFile
+	file.Decoding(path)
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	require.Equal(t, uint32(info.Size()), file.Hdr.FileLength)
+	require.Equal(t, uint32(len(file.CdrList)), file.Hdr.NumberOfCdrsInFile)
+
+	var records []cdrType.ChargingRecord
+	for _, cdr := range file.CdrList {
+		require.Equal(t, cdrFile.BasicEncodingRules, cdr.Hdr.DataRecordFormat)
+		require.Equal(t, cdrFile.TS32255, cdr.Hdr.TsNumber)
+		var record cdrType.ChargingRecord
+		require.NoError(t, asn.UnmarshalWithParams(cdr.CdrByte, &record, ""))
+		records = append(records, record)
+	}
+	return file, records
+}
+
+func TestMemorySink(t *testing.T) {
+	t.Parallel()
+
+	sink := NewMemorySink()
+	require.NoError(t, sink.Write(cdrTest.ChargingRecord("session1", 1)))
+	require.NoError(t, sink.Write(cdrTest.ChargingRecord("session1", 2)))
+	require.NoError(t, sink.Flush())
+	require.NoError(t, sink.Close())
+	require.ErrorIs(t, sink.Write(cdrTest.ChargingRecord("session1", 3)), ErrClosed)
+
+	records := sink.Records()
+	require.Len(t, records, 2)
+	require.Equal(t, int64(2), *records[1].RecordSequenceNumber)
+}
+
+func TestFileSink(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "chf.cdr")
+	sink := NewFileSink(path, header)
+
+	require.NoError(t, sink.Write(cdrTest.ChargingRecord("session1", 1)))
+	require.NoError(t, sink.Flush())
+	_, records := decode(t, path)
+	require.Len(t, records, 1)
+
+	require.NoError(t, sink.Write(cdrTest.ChargingRecord("session1", 2)))
+	require.NoError(t, sink.Close())
+	require.ErrorIs(t, sink.Write(cdrTest.ChargingRecord("session1", 3)), ErrClosed)
+
+	file, records := decode(t, path)
+	require.Len(t, records, 2)
+	require.Equal(t, int64(2), *records[1].RecordSequenceNumber)
+	require.Equal(t, uint32(5), file.Hdr.FileSequenceNumber)
+	require.Equal(t, cdrFile.NormalClosure, file.Hdr.FileClosureTriggerReason)
+}
+
+func TestSpoolSink(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	sink, err := NewSpoolSink(dir, "chf_", header, SpoolLimits{MaxCdrs: 3, MaxAge: time.Hour})
+	require.NoError(t, err)
+
+	// MaximumNumberOfCdrsInFileReached
+	sink.SetLostCdrs(2)
+	for i := 1; i <= 4; i++ {
+		require.NoError(t, sink.Write(cdrTest.ChargingRecord("session1", int64(i))))
+	}
+	require.NoError(t, sink.Flush())
+	require.FileExists(t, filepath.Join(dir, "chf_0000000002.cdr.open"))
+	// FileOpentimeLimitedReached
+	require.NoError(t, sink.CloseExpired(time.Now()))
+	require.NoError(t, sink.CloseExpired(time.Now().Add(time.Hour)))
+	// FileClosedByManualIntervention
+	require.NoError(t, sink.Write(cdrTest.ChargingRecord("session1", 5)))
+	require.NoError(t, sink.Rotate())
+	require.NoError(t, sink.Rotate())
+	// FileSizeLimitReached
+	one, err := os.ReadFile(filepath.Join(dir, "chf_0000000003.cdr"))
+	require.NoError(t, err)
+	sink.SetLimits(SpoolLimits{MaxSize: len(one) + 1})
+	require.NoError(t, sink.Write(cdrTest.ChargingRecord("session1", 6)))
+	require.NoError(t, sink.Write(cdrTest.ChargingRecord("session1", 7)))
+	// NormalClosure
+	require.NoError(t, sink.Close())
+	require.ErrorIs(t, sink.Write(cdrTest.ChargingRecord("session1", 8)), ErrClosed)
+
+	testCases := []struct {
+		name    string
+		reason  cdrFile.FileClosureTriggerReasonType
+		records []int64
+		lost    uint8
+	}{
+		{"chf_0000000001.cdr", cdrFile.MaximumNumberOfCdrsInFileReached, []int64{1, 2, 3}, 0x82},
+		{"chf_0000000002.cdr", cdrFile.FileOpentimeLimitedReached, []int64{4}, 0},
+		{"chf_0000000003.cdr", cdrFile.FileClosedByManualIntervention, []int64{5}, 0},
+		{"chf_0000000004.cdr", cdrFile.FileSizeLimitReached, []int64{6}, 0},
+		{"chf_0000000005.cdr", cdrFile.NormalClosure, []int64{7}, 0},
+	}
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	// the files and the last sequence number
+	require.Len(t, entries, len(testCases)+1)
+	require.Equal(t, "chf_.seq", entries[0].Name())
+	for i, tc := range testCases {
+		require.Equal(t, tc.name, entries[i+1].Name())
+		file, records := decode(t, filepath.Join(dir, tc.name))
+		require.Equal(t, uint32(i+1), file.Hdr.FileSequenceNumber, tc.name)
+		require.Equal(t, tc.reason, file.Hdr.FileClosureTriggerReason, tc.name)
+		require.Equal(t, tc.lost, file.Hdr.LostCdrIndicator, tc.name)
+		require.Len(t, records, len(tc.records), tc.name)
+		for j, record := range records {
+			require.Equal(t, tc.records[j], *record.RecordSequenceNumber, tc.name)
+		}
+	}
+}
+
+func TestSpoolSinkRotationFailure(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	sink, err := NewSpoolSink(dir, "chf_", header, SpoolLimits{MaxCdrs: 2})
+	require.NoError(t, err)
+
+	// a directory in the way of the closed file
+	closedPath := filepath.Join(dir, "chf_0000000001.cdr")
+	require.NoError(t, os.Mkdir(closedPath, 0o777))
+	require.NoError(t, sink.Write(cdrTest.ChargingRecord("session1", 1)))
+	// stored although the file fails to close
+	require.NoError(t, sink.Write(cdrTest.ChargingRecord("session1", 2)))
+	require.Error(t, sink.Flush())
+	// not stored, the full file failing to close again
+	require.Error(t, sink.Write(cdrTest.ChargingRecord("session1", 3)))
+
+	require.NoError(t, os.Remove(closedPath))
+	require.NoError(t, sink.Write(cdrTest.ChargingRecord("session1", 3)))
+	require.NoError(t, sink.Close())
+
+	_, records := decode(t, closedPath)
+	require.Len(t, records, 2)
+	_, records = decode(t, filepath.Join(dir, "chf_0000000002.cdr"))
+	require.Len(t, records, 1)
+	require.Equal(t, int64(3), *records[0].RecordSequenceNumber)
+}
+
+func TestSpoolSinkRestart(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	sink, err := NewSpoolSink(dir, "chf_", header, SpoolLimits{MaxCdrs: 1})
+	require.NoError(t, err)
+	require.NoError(t, sink.Write(cdrTest.ChargingRecord("session1", 1)))
+	sink.SetLimits(SpoolLimits{})
+	require.NoError(t, sink.Write(cdrTest.ChargingRecord("session1", 2)))
+	require.NoError(t, sink.Flush())
+	// a crash leaves the open file and a temporary one
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "chf_0000000003.cdr.tmp"), []byte("cut"), 0o666))
+
+	sink, err = NewSpoolSink(dir, "chf_", header, SpoolLimits{})
+	require.NoError(t, err)
+	file, records := decode(t, filepath.Join(dir, "chf_0000000002.cdr"))
+	require.Equal(t, cdrFile.AbnormalFileClosure, file.Hdr.FileClosureTriggerReason)
+	require.Len(t, records, 1)
+
+	require.NoError(t, sink.Write(cdrTest.ChargingRecord("session1", 3)))
+	require.NoError(t, sink.Close())
+	file, _ = decode(t, filepath.Join(dir, "chf_0000000003.cdr"))
+	require.Equal(t, uint32(3), file.Hdr.FileSequenceNumber)
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 4)
+}
+
+func TestSpoolSinkRestartAfterDrain(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	sink, err := NewSpoolSink(dir, "chf_", header, SpoolLimits{MaxCdrs: 1})
+	require.NoError(t, err)
+	require.NoError(t, sink.Write(cdrTest.ChargingRecord("session1", 1)))
+	require.NoError(t, sink.Write(cdrTest.ChargingRecord("session1", 2)))
+	require.NoError(t, sink.Close())
+
+	// all the files transferred and removed
+	for _, name := range []string{"chf_0000000001.cdr", "chf_0000000002.cdr"} {
+		require.NoError(t, os.Remove(filepath.Join(dir, name)))
+	}
+
+	sink, err = NewSpoolSink(dir, "chf_", header, SpoolLimits{})
+	require.NoError(t, err)
+	require.NoError(t, sink.Write(cdrTest.ChargingRecord("session1", 3)))
+	require.NoError(t, sink.Close())
+	file, _ := decode(t, filepath.Join(dir, "chf_0000000003.cdr"))
+	require.Equal(t, uint32(3), file.Hdr.FileSequenceNumber)
+
+	// a file of a previous run beyond the number kept is not reused either
+	require.NoError(t, os.Rename(filepath.Join(dir, "chf_0000000003.cdr"), filepath.Join(dir, "chf_0000000009.cdr")))
+	sink, err = NewSpoolSink(dir, "chf_", header, SpoolLimits{})
+	require.NoError(t, err)
+	require.NoError(t, os.Remove(filepath.Join(dir, "chf_0000000009.cdr")))
+	sink2, err := NewSpoolSink(dir, "chf_", header, SpoolLimits{})
+	require.NoError(t, err)
+	require.NoError(t, sink2.Write(cdrTest.ChargingRecord("session1", 4)))
+	require.NoError(t, sink2.Close())
+	require.FileExists(t, filepath.Join(dir, "chf_0000000010.cdr"))
+	require.NoError(t, sink.Close())
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "chf_.seq"), []byte("x"), 0o666))
+	_, err = NewSpoolSink(dir, "chf_", header, SpoolLimits{})
+	require.Error(t, err)
+}
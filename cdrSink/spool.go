@@ -0,0 +1,346 @@
+package cdrSink
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/free5gc/CDRUtil/cdrFile"
+	"github.com/free5gc/CDRUtil/cdrType"
+	"github.com/free5gc/CDRUtil/internal/safeFile"
+)
+
+const (
+	// extension of the closed CDR files of a spool directory
+	ClosedFileExt = ".cdr"
+	// extension of the CDR file being filled
+	openFileExt = ".cdr.open"
+	// extension of the file keeping the last sequence number
+	sequenceFileExt = ".seq"
+	// offset of the FileClosureTriggerReason in a CDR file
+	closureReasonOffset = 26
+)
+
+// SpoolLimits are the limits beyond which the open file of a SpoolSink is
+// closed, the next record opening a new one. A zero limit is not enforced.
+type SpoolLimits struct {
+	// closed with MaximumNumberOfCdrsInFileReached
+	MaxCdrs int
+	// octets, closed with FileSizeLimitReached
+	MaxSize int
+	// closed with FileOpentimeLimitedReached by CloseExpired
+	MaxAge time.Duration
+}
+
+// SpoolSink writes its records to a spool directory of CDR files named by
+// their prefix and sequence number, e.g. chf_0000000001.cdr. A file is open
+// under the extension .cdr.open until it is closed, each flush rewriting it;
+// the closed files are the ones with extension .cdr. The sequence number of
+// the last file closed is kept in the file named after the prefix with
+// extension .seq, e.g. chf_.seq, so that it survives the removal of the files.
+//
+// It is safe for concurrent use.
+type SpoolSink struct {
+	dir    string
+	prefix string
+	header Header
+
+	mu             sync.Mutex
+	limits         SpoolLimits
+	file           *openFile
+	openingTime    time.Time
+	sequenceNumber uint32
+	lost           int
+	closed         bool
+}
+
+// NewSpoolSink returns a sink writing to the spool directory dir, created if
+// it does not exist, the files named after prefix. The sequence numbers go on
+// from the last file closed in dir, even if it was removed since. A file left
+// open by a crash is closed with AbnormalFileClosure, as it was last flushed.
+func NewSpoolSink(dir, prefix string, header Header, limits SpoolLimits) (*SpoolSink, error) {
+	if err := os.MkdirAll(dir, 0o777); err != nil {
+		return nil, err
+	}
+	s := &SpoolSink{
+		dir:    dir,
+		prefix: prefix,
+		header: header,
+		limits: limits,
+	}
+
+	saved, err := s.loadSequenceNumber()
+	if err != nil {
+		return nil, err
+	}
+	s.sequenceNumber = saved
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if !strings.HasPrefix(name, prefix) || entry.IsDir() {
+			continue
+		}
+		if strings.HasSuffix(name, safeFile.TmpExt) {
+			// the temporary file of a crashed write
+			if err := os.Remove(filepath.Join(dir, name)); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		sequenceNumber, open, ok := s.parseName(name)
+		if !ok {
+			continue
+		}
+		if sequenceNumber > s.sequenceNumber {
+			s.sequenceNumber = sequenceNumber
+		}
+		if open {
+			if err := s.closeAbandoned(sequenceNumber); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if s.sequenceNumber > saved {
+		if err := s.saveSequenceNumber(); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// SetLimits sets the limits checked by the next writes.
+func (s *SpoolSink) SetLimits(limits SpoolLimits) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.limits = limits
+}
+
+// SetLostCdrs reports n CDRs lost before the open file, or the next one if
+// none is open, by its LostCdrIndicator; see cdrFile.SetLostCdrs.
+func (s *SpoolSink) SetLostCdrs(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.file != nil {
+		s.file.file.Hdr.SetLostCdrs(n)
+		return
+	}
+	s.lost = n
+}
+
+// Write adds record to the open file, opening one if needed. The file is
+// closed first if the record would exceed its size limit, and after if it
+// reaches its CDR count limit. The record is stored once it is added, even if
+// the file then fails to close: the closure is retried, and its failure
+// reported, by the next Write, before adding its record, or by Flush.
+func (s *SpoolSink) Write(record cdrType.ChargingRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return ErrClosed
+	}
+	now := time.Now()
+
+	if s.full() {
+		if err := s.rotate(cdrFile.MaximumNumberOfCdrsInFileReached); err != nil {
+			return err
+		}
+	}
+	if s.file == nil {
+		s.open(now)
+	}
+	cdr, err := s.file.encodeCdr(record)
+	if err != nil {
+		return err
+	}
+	if s.limits.MaxSize > 0 && len(s.file.file.CdrList) > 0 &&
+		s.file.size()+cdrLength(cdr) > s.limits.MaxSize {
+		if err := s.rotate(cdrFile.FileSizeLimitReached); err != nil {
+			return err
+		}
+		s.open(now)
+	}
+	s.file.add(cdr, now)
+
+	if s.full() {
+		// retried by the next Write or Flush
+		_ = s.rotate(cdrFile.MaximumNumberOfCdrsInFileReached)
+	}
+	return nil
+}
+
+// Flush rewrites the open file, or closes it if it reached its CDR count
+// limit but failed to close on Write.
+func (s *SpoolSink) Flush() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return ErrClosed
+	}
+	if s.file == nil {
+		return nil
+	}
+	if s.full() {
+		return s.rotate(cdrFile.MaximumNumberOfCdrsInFileReached)
+	}
+	return safeFile.Replace(s.path(s.sequenceNumber, true), s.file.bytes(cdrFile.NormalClosure))
+}
+
+// CloseExpired closes the open file if it was opened MaxAge or more before
+// now.
+func (s *SpoolSink) CloseExpired(now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed || s.file == nil || s.limits.MaxAge <= 0 || now.Sub(s.openingTime) < s.limits.MaxAge {
+		return nil
+	}
+	return s.rotate(cdrFile.FileOpentimeLimitedReached)
+}
+
+// Rotate closes the open file, if any, with FileClosedByManualIntervention.
+func (s *SpoolSink) Rotate() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return ErrClosed
+	}
+	if s.file == nil {
+		return nil
+	}
+	return s.rotate(cdrFile.FileClosedByManualIntervention)
+}
+
+// Close closes the open file, if any, with NormalClosure.
+func (s *SpoolSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return nil
+	}
+	if s.file != nil {
+		if err := s.rotate(cdrFile.NormalClosure); err != nil {
+			return err
+		}
+	}
+	s.closed = true
+	return nil
+}
+
+func (s *SpoolSink) open(now time.Time) {
+	s.sequenceNumber++
+	s.file = newOpenFile(s.header, s.sequenceNumber, now)
+	s.file.file.Hdr.SetLostCdrs(s.lost)
+	s.openingTime = now
+	s.lost = 0
+}
+
+// full returns whether the open file reached its CDR count limit.
+func (s *SpoolSink) full() bool {
+	return s.file != nil && s.limits.MaxCdrs > 0 && len(s.file.file.CdrList) >= s.limits.MaxCdrs
+}
+
+// rotate writes the open file closed for reason and removes its open
+// version.
+func (s *SpoolSink) rotate(reason cdrFile.FileClosureTriggerReasonType) error {
+	if err := safeFile.Replace(s.path(s.sequenceNumber, false), s.file.bytes(reason)); err != nil {
+		return err
+	}
+	if err := s.saveSequenceNumber(); err != nil {
+		return err
+	}
+	if err := os.Remove(s.path(s.sequenceNumber, true)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	s.file = nil
+	return nil
+}
+
+// closeAbandoned closes the open file of the given sequence number left by a
+// previous run, unless it was closed already.
+func (s *SpoolSink) closeAbandoned(sequenceNumber uint32) error {
+	openPath := s.path(sequenceNumber, true)
+	closedPath := s.path(sequenceNumber, false)
+	if _, err := os.Stat(closedPath); err == nil {
+		return os.Remove(openPath)
+	}
+
+	data, err := os.ReadFile(openPath)
+	if err != nil {
+		return err
+	}
+	if len(data) <= closureReasonOffset {
+		return fmt.Errorf("%s: truncated CDR file", openPath)
+	}
+	data[closureReasonOffset] = byte(cdrFile.AbnormalFileClosure)
+	if err := safeFile.Replace(closedPath, data); err != nil {
+		return err
+	}
+	return os.Remove(openPath)
+}
+
+// loadSequenceNumber returns the sequence number kept in dir, 0 if none is.
+func (s *SpoolSink) loadSequenceNumber() (uint32, error) {
+	path := filepath.Join(s.dir, s.prefix+sequenceFileExt)
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	sequenceNumber, err := strconv.ParseUint(strings.TrimSpace(string(data)), 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("%s: invalid sequence number %q", path, data)
+	}
+	return uint32(sequenceNumber), nil
+}
+
+// saveSequenceNumber keeps the current sequence number in dir.
+func (s *SpoolSink) saveSequenceNumber() error {
+	return safeFile.Replace(filepath.Join(s.dir, s.prefix+sequenceFileExt),
+		[]byte(strconv.FormatUint(uint64(s.sequenceNumber), 10)+"\n"))
+}
+
+func (s *SpoolSink) path(sequenceNumber uint32, open bool) string {
+	ext := ClosedFileExt
+	if open {
+		ext = openFileExt
+	}
+	return filepath.Join(s.dir, fmt.Sprintf("%s%010d%s", s.prefix, sequenceNumber, ext))
+}
+
+// parseName returns the sequence number of the file named name and whether
+// it is open.
+func (s *SpoolSink) parseName(name string) (uint32, bool, bool) {
+	open := strings.HasSuffix(name, openFileExt)
+	name = strings.TrimPrefix(name, s.prefix)
+	if open {
+		name = strings.TrimSuffix(name, openFileExt)
+	} else if strings.HasSuffix(name, ClosedFileExt) {
+		name = strings.TrimSuffix(name, ClosedFileExt)
+	} else {
+		return 0, false, false
+	}
+	if len(name) != 10 {
+		return 0, false, false
+	}
+	sequenceNumber, err := strconv.ParseUint(name, 10, 32)
+	if err != nil {
+		return 0, false, false
+	}
+	return uint32(sequenceNumber), open, true
+}
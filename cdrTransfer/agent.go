@@ -0,0 +1,288 @@
+// Package cdrTransfer pushes the closed CDR files of a spool directory to the
+// billing domain, as the CGF does over the Bf interface in TS 32.297.
+package cdrTransfer
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/free5gc/CDRUtil/cdrSink"
+	"github.com/free5gc/CDRUtil/internal/safeFile"
+)
+
+// name of the acknowledgement log in the sent directory
+const ackLogName = "acks.log"
+
+// Config is the configuration of an Agent. The zero durations are replaced
+// by their defaults.
+type Config struct {
+	// directory of the closed CDR files, as written by a cdrSink.SpoolSink
+	SpoolDir string
+	// directory the files are moved to once transferred
+	SentDir string
+	// interval between two scans of the spool directory, 10s by default
+	PollInterval time.Duration
+	// wait after a failed transfer, 1s by default, doubled by each failure
+	// in a row up to MaxRetryInterval, 1m by default
+	RetryInterval    time.Duration
+	MaxRetryInterval time.Duration
+}
+
+// Ack is the acknowledgement of a transferred file.
+type Ack struct {
+	Name string
+	Size int
+	// hex SHA-256 of the file
+	Checksum string
+	Time     time.Time
+}
+
+// Agent transfers the closed CDR files of the spool directory by a
+// Transport, in the order of their names, and moves each one to the sent
+// directory once acknowledged. The acknowledgements are logged in the sent
+// directory before the files are moved, so that a file is not transferred
+// again after a crash in between. A file is never moved over another of the
+// same name in the sent directory: the transfer fails until that one is
+// moved away.
+//
+// It is safe for concurrent use.
+type Agent struct {
+	config Config
+	dial   func() (Transport, error)
+
+	mu        sync.Mutex
+	transport Transport
+	ackLog    *os.File
+	// acknowledged files not moved yet, by name
+	acked map[string]Ack
+	// failed transfers in a row
+	failures int
+}
+
+// NewAgent returns an agent for config dialing the transport with dial, once
+// there is a file to transfer and again after a failure.
+func NewAgent(config Config, dial func() (Transport, error)) (*Agent, error) {
+	if config.SpoolDir == "" || config.SentDir == "" {
+		return nil, fmt.Errorf("no spool or sent directory")
+	}
+	if config.PollInterval <= 0 {
+		config.PollInterval = 10 * time.Second
+	}
+	if config.RetryInterval <= 0 {
+		config.RetryInterval = time.Second
+	}
+	if config.MaxRetryInterval <= 0 {
+		config.MaxRetryInterval = time.Minute
+	}
+	if err := os.MkdirAll(config.SentDir, 0o777); err != nil {
+		return nil, err
+	}
+	a := &Agent{
+		config: config,
+		dial:   dial,
+		acked:  make(map[string]Ack),
+	}
+	if err := a.openAckLog(); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// Run transfers the files until ctx is done, scanning the spool directory
+// every poll interval or, after a failure, once the retry interval elapsed.
+// Run returns the error of ctx.
+func (a *Agent) Run(ctx context.Context) error {
+	for {
+		wait := a.config.PollInterval
+		if _, err := a.Transfer(); err != nil {
+			wait = a.retryInterval()
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// Transfer transfers the files in the spool directory and returns the
+// acknowledgements of the ones moved to the sent directory. It stops at the
+// first failure, the transport being closed.
+func (a *Agent) Transfer() ([]Ack, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	names, err := a.closedFiles()
+	if err != nil {
+		return nil, err
+	}
+	var acks []Ack
+	for _, name := range names {
+		ack, err := a.transfer(name)
+		if err != nil {
+			a.failures++
+			if a.transport != nil {
+				a.transport.Close()
+				a.transport = nil
+			}
+			return acks, fmt.Errorf("%s: %v", name, err)
+		}
+		acks = append(acks, ack)
+	}
+	a.failures = 0
+	return acks, nil
+}
+
+// Pending returns the number of files waiting in the spool directory.
+func (a *Agent) Pending() (int, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	names, err := a.closedFiles()
+	return len(names), err
+}
+
+// Close closes the transport and the acknowledgement log.
+func (a *Agent) Close() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.transport != nil {
+		a.transport.Close()
+		a.transport = nil
+	}
+	return a.ackLog.Close()
+}
+
+func (a *Agent) retryInterval() time.Duration {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	wait := a.config.RetryInterval
+	for i := 1; i < a.failures && wait < a.config.MaxRetryInterval; i++ {
+		wait *= 2
+	}
+	if wait > a.config.MaxRetryInterval {
+		wait = a.config.MaxRetryInterval
+	}
+	return wait
+}
+
+// transfer transfers the file name, unless it was acknowledged already, and
+// moves it to the sent directory.
+func (a *Agent) transfer(name string) (Ack, error) {
+	data, err := os.ReadFile(filepath.Join(a.config.SpoolDir, name))
+	if err != nil {
+		return Ack{}, err
+	}
+	sum := sha256.Sum256(data)
+	checksum := hex.EncodeToString(sum[:])
+
+	ack, ok := a.acked[name]
+	if !ok || ack.Checksum != checksum {
+		if a.transport == nil {
+			transport, err := a.dial()
+			if err != nil {
+				return Ack{}, err
+			}
+			a.transport = transport
+		}
+		if err := a.transport.Put(name, data); err != nil {
+			return Ack{}, err
+		}
+		ack = Ack{Name: name, Size: len(data), Checksum: checksum, Time: time.Now()}
+		if err := a.logAck(ack); err != nil {
+			return Ack{}, err
+		}
+		a.acked[name] = ack
+	}
+
+	err = safeFile.Move(filepath.Join(a.config.SpoolDir, name), filepath.Join(a.config.SentDir, name))
+	if os.IsExist(err) {
+		return Ack{}, fmt.Errorf("another file of the same name is in the sent directory")
+	}
+	if err != nil {
+		return Ack{}, err
+	}
+	delete(a.acked, name)
+	return ack, nil
+}
+
+// closedFiles returns the names of the closed CDR files in the spool
+// directory, in order.
+func (a *Agent) closedFiles() ([]string, error) {
+	entries, err := os.ReadDir(a.config.SpoolDir)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), cdrSink.ClosedFileExt) {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// openAckLog reads the acknowledgement log and rewrites it with the
+// acknowledgements of the files still in the spool directory only, those of
+// the files moved being of no use anymore.
+func (a *Agent) openAckLog() error {
+	path := filepath.Join(a.config.SentDir, ackLogName)
+	file, err := os.OpenFile(path, os.O_RDONLY|os.O_CREATE, 0o666)
+	if err != nil {
+		return err
+	}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var ack Ack
+		// a line cut by a crash loses its acknowledgement, the file being
+		// transferred again
+		if err := json.Unmarshal(scanner.Bytes(), &ack); err != nil {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(a.config.SpoolDir, ack.Name)); err == nil {
+			a.acked[ack.Name] = ack
+		}
+	}
+	file.Close()
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	var buf []byte
+	for _, ack := range a.acked {
+		line, err := json.Marshal(ack)
+		if err != nil {
+			return err
+		}
+		buf = append(append(buf, line...), '\n')
+	}
+	if err := safeFile.Replace(path, buf); err != nil {
+		return err
+	}
+	a.ackLog, err = os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	return err
+}
+
+func (a *Agent) logAck(ack Ack) error {
+	line, err := json.Marshal(ack)
+	if err != nil {
+		return err
+	}
+	if _, err := a.ackLog.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	return a.ackLog.Sync()
+}
@@ -0,0 +1,465 @@
+package cdrTransfer
+
+import (
+	"bufio"
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/sftp"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+)
+
+// flakyTransport fails the puts while failing is set.
+type flakyTransport struct {
+	Transport
+	failing *bool
+	puts    *int
+}
+
+func (t flakyTransport) Put(name string, data []byte) error {
+	*t.puts++
+	if *t.failing {
+		return errors.New("connection reset")
+	}
+	return t.Transport.Put(name, data)
+}
+
+func writeFiles(t *testing.T, dir string, names ...string) {
+	require.NoError(t, os.MkdirAll(dir, 0o777))
+	for _, name := range names {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(name), 0o666))
+	}
+}
+
+func TestAgent(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	config := Config{
+		SpoolDir: filepath.Join(dir, "spool"),
+		SentDir:  filepath.Join(dir, "sent"),
+	}
+	writeFiles(t, config.SpoolDir, "chf_0000000001.cdr", "chf_0000000002.cdr", "chf_0000000003.cdr.open")
+
+	failing := false
+	var puts, dials int
+	agent, err := NewAgent(config, func() (Transport, error) {
+		dials++
+		transport, err := NewDirTransport(filepath.Join(dir, "bd"))
+		return flakyTransport{transport, &failing, &puts}, err
+	})
+	require.NoError(t, err)
+
+	acks, err := agent.Transfer()
+	require.NoError(t, err)
+	require.Len(t, acks, 2)
+	require.Equal(t, "chf_0000000001.cdr", acks[0].Name)
+	require.Equal(t, len("chf_0000000001.cdr"), acks[0].Size)
+	require.FileExists(t, filepath.Join(dir, "bd", "chf_0000000002.cdr"))
+	require.FileExists(t, filepath.Join(config.SentDir, "chf_0000000002.cdr"))
+	require.FileExists(t, filepath.Join(config.SpoolDir, "chf_0000000003.cdr.open"))
+	require.Equal(t, 1, dials)
+
+	// the transport is dialed again after a failure
+	writeFiles(t, config.SpoolDir, "chf_0000000003.cdr")
+	failing = true
+	acks, err = agent.Transfer()
+	require.Error(t, err)
+	require.Empty(t, acks)
+	require.Equal(t, time.Second, agent.retryInterval())
+	_, err = agent.Transfer()
+	require.Error(t, err)
+	require.Equal(t, 2*time.Second, agent.retryInterval())
+	pending, err := agent.Pending()
+	require.NoError(t, err)
+	require.Equal(t, 1, pending)
+
+	failing = false
+	acks, err = agent.Transfer()
+	require.NoError(t, err)
+	require.Len(t, acks, 1)
+	require.Equal(t, 3, dials)
+	require.Equal(t, time.Second, agent.retryInterval())
+	require.NoError(t, agent.Close())
+
+	entries, err := os.ReadDir(filepath.Join(dir, "bd"))
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+}
+
+func TestAgentSentFileInTheWay(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	config := Config{
+		SpoolDir: filepath.Join(dir, "spool"),
+		SentDir:  filepath.Join(dir, "sent"),
+	}
+	writeFiles(t, config.SpoolDir, "chf_0000000001.cdr")
+	// a file of the same name sent before, as after the spool was reset
+	sentPath := filepath.Join(config.SentDir, "chf_0000000001.cdr")
+	require.NoError(t, os.MkdirAll(config.SentDir, 0o777))
+	require.NoError(t, os.WriteFile(sentPath, []byte("sent before"), 0o666))
+
+	var puts int
+	failing := false
+	dial := func() (Transport, error) {
+		transport, err := NewDirTransport(filepath.Join(dir, "bd"))
+		return flakyTransport{transport, &failing, &puts}, err
+	}
+	agent, err := NewAgent(config, dial)
+	require.NoError(t, err)
+	defer agent.Close()
+
+	_, err = agent.Transfer()
+	require.Error(t, err)
+	data, err := os.ReadFile(sentPath)
+	require.NoError(t, err)
+	require.Equal(t, "sent before", string(data))
+	require.FileExists(t, filepath.Join(config.SpoolDir, "chf_0000000001.cdr"))
+
+	// moved, without transferring it again, once the other one is moved away
+	require.NoError(t, os.Rename(sentPath, filepath.Join(dir, "chf_0000000001.cdr")))
+	acks, err := agent.Transfer()
+	require.NoError(t, err)
+	require.Len(t, acks, 1)
+	require.Equal(t, 1, puts)
+	require.FileExists(t, sentPath)
+}
+
+func TestAgentAcknowledged(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	config := Config{
+		SpoolDir: filepath.Join(dir, "spool"),
+		SentDir:  filepath.Join(dir, "sent"),
+	}
+	writeFiles(t, config.SpoolDir, "chf_0000000001.cdr", "chf_0000000002.cdr")
+
+	var puts int
+	failing := false
+	dial := func() (Transport, error) {
+		transport, err := NewDirTransport(filepath.Join(dir, "bd"))
+		return flakyTransport{transport, &failing, &puts}, err
+	}
+	agent, err := NewAgent(config, dial)
+	require.NoError(t, err)
+	acks, err := agent.Transfer()
+	require.NoError(t, err)
+	require.NoError(t, agent.Close())
+
+	// a crash after the acknowledgements, before the files were moved
+	for _, ack := range acks {
+		require.NoError(t, os.Rename(filepath.Join(config.SentDir, ack.Name), filepath.Join(config.SpoolDir, ack.Name)))
+	}
+	// the second file changed since
+	require.NoError(t, os.WriteFile(filepath.Join(config.SpoolDir, "chf_0000000002.cdr"), []byte("changed"), 0o666))
+
+	agent, err = NewAgent(config, dial)
+	require.NoError(t, err)
+	acks, err = agent.Transfer()
+	require.NoError(t, err)
+	require.Len(t, acks, 2)
+	require.Equal(t, 3, puts)
+	require.NoError(t, agent.Close())
+
+	// the acknowledgements of the moved files are dropped
+	agent, err = NewAgent(config, dial)
+	require.NoError(t, err)
+	require.Empty(t, agent.acked)
+	require.NoError(t, agent.Close())
+}
+
+// serveSFTP runs an SFTP server on a local port accepting user with
+// password, and returns its address and host key.
+func serveSFTP(t *testing.T, user, password string) (string, ssh.PublicKey) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	signer, err := ssh.NewSignerFromKey(key)
+	require.NoError(t, err)
+
+	config := &ssh.ServerConfig{
+		PasswordCallback: func(conn ssh.ConnMetadata, p []byte) (*ssh.Permissions, error) {
+			if conn.User() == user && string(p) == password {
+				return nil, nil
+			}
+			return nil, errors.New("access denied")
+		},
+	}
+	config.AddHostKey(signer)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveSSH(conn, config)
+		}
+	}()
+	return listener.Addr().String(), signer.PublicKey()
+}
+
+func serveSSH(conn net.Conn, config *ssh.ServerConfig) {
+	defer conn.Close()
+	_, channels, requests, err := ssh.NewServerConn(conn, config)
+	if err != nil {
+		return
+	}
+	go ssh.DiscardRequests(requests)
+
+	for newChannel := range channels {
+		if newChannel.ChannelType() != "session" {
+			newChannel.Reject(ssh.UnknownChannelType, "unknown channel type")
+			continue
+		}
+		channel, requests, err := newChannel.Accept()
+		if err != nil {
+			return
+		}
+		go func() {
+			for request := range requests {
+				request.Reply(request.Type == "subsystem" && string(request.Payload[4:]) == "sftp", nil)
+			}
+		}()
+		server, err := sftp.NewServer(channel)
+		if err != nil {
+			return
+		}
+		if err := server.Serve(); err != nil && err != io.EOF {
+			return
+		}
+		server.Close()
+	}
+}
+
+func TestAgentSFTP(t *testing.T) {
+	t.Parallel()
+
+	addr, hostKey := serveSFTP(t, "cgf", "secret")
+	dir := t.TempDir()
+	config := Config{
+		SpoolDir: filepath.Join(dir, "spool"),
+		SentDir:  filepath.Join(dir, "sent"),
+	}
+	writeFiles(t, config.SpoolDir, "chf_0000000001.cdr", "chf_0000000002.cdr")
+
+	sftpConfig := SFTPConfig{
+		Addr:            addr,
+		User:            "cgf",
+		Password:        "wrong",
+		HostKeyCallback: ssh.FixedHostKey(hostKey),
+		Dir:             filepath.Join(dir, "bd", "cdr"),
+	}
+	agent, err := NewAgent(config, func() (Transport, error) {
+		return DialSFTP(sftpConfig)
+	})
+	require.NoError(t, err)
+
+	_, err = agent.Transfer()
+	require.Error(t, err)
+
+	sftpConfig.Password = "secret"
+	acks, err := agent.Transfer()
+	require.NoError(t, err)
+	require.Len(t, acks, 2)
+	require.NoError(t, agent.Close())
+
+	for _, ack := range acks {
+		data, err := os.ReadFile(filepath.Join(sftpConfig.Dir, ack.Name))
+		require.NoError(t, err)
+		require.Equal(t, ack.Name, string(data))
+		require.NoFileExists(t, filepath.Join(sftpConfig.Dir, ack.Name+partialExt))
+		require.FileExists(t, filepath.Join(config.SentDir, ack.Name))
+	}
+}
+
+// serveFTP runs an FTP server on a local port serving dir to user with
+// password, and returns its address. Like many servers, it does not rename
+// onto an existing file.
+func serveFTP(t *testing.T, dir, user, password string) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveFTPConn(conn, dir, user, password)
+		}
+	}()
+	return listener.Addr().String()
+}
+
+func serveFTPConn(conn net.Conn, root, user, password string) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	reply := func(format string, args ...interface{}) {
+		fmt.Fprintf(conn, format+"\r\n", args...)
+	}
+
+	dir := root
+	var loggedIn bool
+	var userName, renameFrom string
+	var data net.Listener
+	defer func() {
+		if data != nil {
+			data.Close()
+		}
+	}()
+
+	reply("220 ready")
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		command, arg, _ := strings.Cut(strings.TrimRight(line, "\r\n"), " ")
+		path := filepath.Join(dir, filepath.Base(arg))
+
+		switch {
+		case command == "USER":
+			userName = arg
+			reply("331 password required")
+		case command == "PASS":
+			loggedIn = userName == user && arg == password
+			if !loggedIn {
+				reply("530 login incorrect")
+				continue
+			}
+			reply("230 logged in")
+		case command == "QUIT":
+			reply("221 bye")
+			return
+		case !loggedIn:
+			reply("530 not logged in")
+		case command == "TYPE":
+			reply("200 type set")
+		case command == "CWD":
+			if info, err := os.Stat(filepath.Join(root, arg)); err != nil || !info.IsDir() {
+				reply("550 no such directory")
+				continue
+			}
+			dir = filepath.Join(root, arg)
+			reply("250 directory changed")
+		case command == "EPSV":
+			if data, err = net.Listen("tcp", "127.0.0.1:0"); err != nil {
+				reply("425 no data connection")
+				continue
+			}
+			reply("229 entering extended passive mode (|||%d|)", data.Addr().(*net.TCPAddr).Port)
+		case command == "STOR" && data != nil:
+			reply("150 opening data connection")
+			dataConn, err := data.Accept()
+			data.Close()
+			data = nil
+			if err != nil {
+				reply("425 no data connection")
+				continue
+			}
+			b, err := io.ReadAll(dataConn)
+			dataConn.Close()
+			if err != nil || os.WriteFile(path, b, 0o666) != nil {
+				reply("451 transfer aborted")
+				continue
+			}
+			reply("226 transfer complete")
+		case command == "RNFR":
+			if _, err := os.Stat(path); err != nil {
+				reply("550 no such file")
+				continue
+			}
+			renameFrom = path
+			reply("350 ready for RNTO")
+		case command == "RNTO" && renameFrom != "":
+			if _, err := os.Stat(path); err == nil {
+				reply("553 file exists")
+				continue
+			}
+			if err := os.Rename(renameFrom, path); err != nil {
+				reply("550 rename failed")
+				continue
+			}
+			renameFrom = ""
+			reply("250 renamed")
+		case command == "DELE":
+			if err := os.Remove(path); err != nil {
+				reply("550 no such file")
+				continue
+			}
+			reply("250 deleted")
+		default:
+			reply("502 command not implemented")
+		}
+	}
+}
+
+func TestAgentFTP(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	bdDir := filepath.Join(dir, "bd")
+	require.NoError(t, os.MkdirAll(filepath.Join(bdDir, "cdr"), 0o777))
+	addr := serveFTP(t, bdDir, "cgf", "secret")
+	config := Config{
+		SpoolDir: filepath.Join(dir, "spool"),
+		SentDir:  filepath.Join(dir, "sent"),
+	}
+	writeFiles(t, config.SpoolDir, "chf_0000000001.cdr", "chf_0000000002.cdr")
+
+	ftpConfig := FTPConfig{
+		Addr:     addr,
+		User:     "cgf",
+		Password: "wrong",
+		Dir:      "cdr",
+		Timeout:  time.Second,
+	}
+	agent, err := NewAgent(config, func() (Transport, error) {
+		return DialFTP(ftpConfig)
+	})
+	require.NoError(t, err)
+
+	_, err = agent.Transfer()
+	require.Error(t, err)
+
+	ftpConfig.Password = "secret"
+	acks, err := agent.Transfer()
+	require.NoError(t, err)
+	require.Len(t, acks, 2)
+	require.NoError(t, agent.Close())
+
+	for _, ack := range acks {
+		data, err := os.ReadFile(filepath.Join(bdDir, "cdr", ack.Name))
+		require.NoError(t, err)
+		require.Equal(t, ack.Name, string(data))
+		require.NoFileExists(t, filepath.Join(bdDir, "cdr", ack.Name+partialExt))
+		require.FileExists(t, filepath.Join(config.SentDir, ack.Name))
+	}
+
+	// a file transferred again replaces the first one
+	transport, err := DialFTP(ftpConfig)
+	require.NoError(t, err)
+	require.NoError(t, transport.Put("chf_0000000001.cdr", []byte("again")))
+	require.NoError(t, transport.Close())
+	data, err := os.ReadFile(filepath.Join(bdDir, "cdr", "chf_0000000001.cdr"))
+	require.NoError(t, err)
+	require.Equal(t, "again", string(data))
+}
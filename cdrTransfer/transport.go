@@ -0,0 +1,180 @@
+package cdrTransfer
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/free5gc/CDRUtil/internal/safeFile"
+	"github.com/jlaffaye/ftp"
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+)
+
+// extension of a file while it is being transferred, so that the billing
+// domain only sees complete files
+const partialExt = ".part"
+
+// Transport delivers CDR files to the billing domain.
+type Transport interface {
+	// Put stores data in the file name, replacing any file of that name. The
+	// file is complete once Put returns without error, the acknowledgement of
+	// the transfer.
+	Put(name string, data []byte) error
+	Close() error
+}
+
+// DirTransport is a Transport copying the files into a local directory, a
+// stand-in for the billing domain.
+type DirTransport struct {
+	dir string
+}
+
+// NewDirTransport returns a transport to the directory dir, created if it
+// does not exist.
+func NewDirTransport(dir string) (*DirTransport, error) {
+	if err := os.MkdirAll(dir, 0o777); err != nil {
+		return nil, err
+	}
+	return &DirTransport{dir: dir}, nil
+}
+
+func (t *DirTransport) Put(name string, data []byte) error {
+	return safeFile.ReplaceThrough(filepath.Join(t.dir, name), filepath.Join(t.dir, name+partialExt), data)
+}
+
+func (t *DirTransport) Close() error {
+	return nil
+}
+
+// SFTPConfig is the SFTP server of the billing domain and the directory the
+// files go to.
+type SFTPConfig struct {
+	// host:port
+	Addr string
+	User string
+	// password or, if Signer is set, passphrase-less public key
+	// authentication
+	Password string
+	Signer   ssh.Signer
+	// checks the host key of the server, see ssh.FixedHostKey
+	HostKeyCallback ssh.HostKeyCallback
+	Dir             string
+	Timeout         time.Duration
+}
+
+// SFTPTransport is a Transport over SFTP.
+type SFTPTransport struct {
+	dir    string
+	conn   *ssh.Client
+	client *sftp.Client
+}
+
+// DialSFTP connects and logs in to the SFTP server of config.
+func DialSFTP(config SFTPConfig) (*SFTPTransport, error) {
+	if config.HostKeyCallback == nil {
+		return nil, fmt.Errorf("no host key callback")
+	}
+	auth := []ssh.AuthMethod{ssh.Password(config.Password)}
+	if config.Signer != nil {
+		auth = []ssh.AuthMethod{ssh.PublicKeys(config.Signer)}
+	}
+	conn, err := ssh.Dial("tcp", config.Addr, &ssh.ClientConfig{
+		User:            config.User,
+		Auth:            auth,
+		HostKeyCallback: config.HostKeyCallback,
+		Timeout:         config.Timeout,
+	})
+	if err != nil {
+		return nil, err
+	}
+	client, err := sftp.NewClient(conn)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if config.Dir != "" {
+		if err := client.MkdirAll(config.Dir); err != nil {
+			client.Close()
+			conn.Close()
+			return nil, err
+		}
+	}
+	return &SFTPTransport{dir: config.Dir, conn: conn, client: client}, nil
+}
+
+func (t *SFTPTransport) Put(name string, data []byte) error {
+	partialPath := path.Join(t.dir, name+partialExt)
+	file, err := t.client.Create(partialPath)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	return t.client.PosixRename(partialPath, path.Join(t.dir, name))
+}
+
+func (t *SFTPTransport) Close() error {
+	t.client.Close()
+	return t.conn.Close()
+}
+
+// FTPConfig is the FTP server of the billing domain and the directory the
+// files go to.
+type FTPConfig struct {
+	// host:port
+	Addr     string
+	User     string
+	Password string
+	Dir      string
+	Timeout  time.Duration
+}
+
+// FTPTransport is a Transport over FTP, in binary mode.
+type FTPTransport struct {
+	conn *ftp.ServerConn
+}
+
+// DialFTP connects and logs in to the FTP server of config.
+func DialFTP(config FTPConfig) (*FTPTransport, error) {
+	conn, err := ftp.Dial(config.Addr, ftp.DialWithTimeout(config.Timeout))
+	if err != nil {
+		return nil, err
+	}
+	if err := conn.Login(config.User, config.Password); err != nil {
+		conn.Quit()
+		return nil, err
+	}
+	if config.Dir != "" {
+		if err := conn.ChangeDir(config.Dir); err != nil {
+			conn.Quit()
+			return nil, err
+		}
+	}
+	return &FTPTransport{conn: conn}, nil
+}
+
+// Put deletes any file name before renaming the complete file to it, as many
+// FTP servers do not rename onto an existing file; name is then missing for
+// a moment.
+func (t *FTPTransport) Put(name string, data []byte) error {
+	if err := t.conn.Stor(name+partialExt, bytes.NewReader(data)); err != nil {
+		return err
+	}
+	// fails if there is no such file, a failure of another kind failing the
+	// rename
+	_ = t.conn.Delete(name)
+	return t.conn.Rename(name+partialExt, name)
+}
+
+func (t *FTPTransport) Close() error {
+	return t.conn.Quit()
+}
@@ -12,3 +12,30 @@ type APIDirection struct {
 	Value	asn.Enumerated 
 }
 
+var apiDirectionNames = map[asn.Enumerated]string{
+	APIDirectionPresentInvocation:	"invocation",
+	APIDirectionPresentNotification:	"notification",
+}
+
+func (v APIDirection) String() string {
+	if s, ok := apiDirectionNames[v.Value]; ok {
+		return s
+	}
+	return unknownEnumString("APIDirection", int64(v.Value))
+}
+
+// IsValid reports whether the value is one of the named values.
+func (v APIDirection) IsValid() bool {
+	_, ok := apiDirectionNames[v.Value]
+	return ok
+}
+
+func ParseAPIDirection(s string) (APIDirection, error) {
+	for value, name := range apiDirectionNames {
+		if name == s {
+			return APIDirection{Value: value}, nil
+		}
+	}
+	return APIDirection{}, unknownEnumError("APIDirection", s)
+}
+
@@ -15,3 +15,33 @@ type ATSSSCapability struct {
 	Value	asn.Enumerated 
 }
 
+var atsssCapabilityNames = map[asn.Enumerated]string{
+	ATSSSCapabilityPresentATSSSLL:	"aTSSSLL",
+	ATSSSCapabilityPresentMPTCPATSSLL:	"mPTCPATSSLL",
+	ATSSSCapabilityPresentMPTCPATSSLLASModeUL:	"mPTCPATSSLLASModeUL",
+	ATSSSCapabilityPresentMPTCPATSSLLExSDModeUL:	"mPTCPATSSLLExSDModeUL",
+	ATSSSCapabilityPresentMPTCPATSSLLASModeDLUL:	"mPTCPATSSLLASModeDLUL",
+}
+
+func (v ATSSSCapability) String() string {
+	if s, ok := atsssCapabilityNames[v.Value]; ok {
+		return s
+	}
+	return unknownEnumString("ATSSSCapability", int64(v.Value))
+}
+
+// IsValid reports whether the value is one of the named values.
+func (v ATSSSCapability) IsValid() bool {
+	_, ok := atsssCapabilityNames[v.Value]
+	return ok
+}
+
+func ParseATSSSCapability(s string) (ATSSSCapability, error) {
+	for value, name := range atsssCapabilityNames {
+		if name == s {
+			return ATSSSCapability{Value: value}, nil
+		}
+	}
+	return ATSSSCapability{}, unknownEnumError("ATSSSCapability", s)
+}
+
@@ -12,3 +12,30 @@ type AccessType struct {
 	Value	asn.Enumerated 
 }
 
+var accessTypeNames = map[asn.Enumerated]string{
+	AccessTypePresentThreeGPPAccess:	"threeGPPAccess",
+	AccessTypePresentNonThreeGPPAccess:	"nonThreeGPPAccess",
+}
+
+func (v AccessType) String() string {
+	if s, ok := accessTypeNames[v.Value]; ok {
+		return s
+	}
+	return unknownEnumString("AccessType", int64(v.Value))
+}
+
+// IsValid reports whether the value is one of the named values.
+func (v AccessType) IsValid() bool {
+	_, ok := accessTypeNames[v.Value]
+	return ok
+}
+
+func ParseAccessType(s string) (AccessType, error) {
+	for value, name := range accessTypeNames {
+		if name == s {
+			return AccessType{Value: value}, nil
+		}
+	}
+	return AccessType{}, unknownEnumError("AccessType", s)
+}
+
@@ -13,3 +13,31 @@ type AdministrativeState struct {
 	Value	asn.Enumerated 
 }
 
+var administrativeStateNames = map[asn.Enumerated]string{
+	AdministrativeStatePresentLOCKED:	"lOCKED",
+	AdministrativeStatePresentUNLOCKED:	"uNLOCKED",
+	AdministrativeStatePresentSHUTTINGDOWN:	"sHUTTINGDOWN",
+}
+
+func (v AdministrativeState) String() string {
+	if s, ok := administrativeStateNames[v.Value]; ok {
+		return s
+	}
+	return unknownEnumString("AdministrativeState", int64(v.Value))
+}
+
+// IsValid reports whether the value is one of the named values.
+func (v AdministrativeState) IsValid() bool {
+	_, ok := administrativeStateNames[v.Value]
+	return ok
+}
+
+func ParseAdministrativeState(s string) (AdministrativeState, error) {
+	for value, name := range administrativeStateNames {
+		if name == s {
+			return AdministrativeState{Value: value}, nil
+		}
+	}
+	return AdministrativeState{}, unknownEnumError("AdministrativeState", s)
+}
+
@@ -12,3 +12,26 @@ type CHFRecord struct {
 	ChargingFunctionRecord	*ChargingRecord `ber:"tagNum:200"`
 }
 
+func NewCHFRecordChargingFunctionRecord(v ChargingRecord) CHFRecord {
+	return CHFRecord{Present: CHFRecordPresentChargingFunctionRecord, ChargingFunctionRecord: &v}
+}
+
+// Value returns the alternative selected by Present, nil if none is or
+// the selected one is not set.
+func (c CHFRecord) Value() interface{} {
+	switch c.Present {
+	case CHFRecordPresentChargingFunctionRecord:
+		if c.ChargingFunctionRecord != nil {
+			return c.ChargingFunctionRecord
+		}
+	}
+	return nil
+}
+
+// Validate checks that exactly the alternative selected by Present is set.
+func (c CHFRecord) Validate() error {
+	return validateChoice("CHFRecord", c.Present, []choiceAlternative{
+		{"ChargingFunctionRecord", c.ChargingFunctionRecord != nil},
+	})
+}
+
@@ -2,7 +2,77 @@ package cdrType
 
 // Need to import "gofree5gc/lib/aper" if it uses "aper"
 
+const (	/* Named Numbers */
+	CauseForRecClosingPresentNormalRelease	int64 = 0
+	CauseForRecClosingPresentPartialRecord	int64 = 1
+	CauseForRecClosingPresentAbnormalRelease	int64 = 4
+	CauseForRecClosingPresentCAMELInitCallRelease	int64 = 5
+	CauseForRecClosingPresentVolumeLimit	int64 = 16
+	CauseForRecClosingPresentTimeLimit	int64 = 17
+	CauseForRecClosingPresentServingNodeChange	int64 = 18
+	CauseForRecClosingPresentMaxChangeCond	int64 = 19
+	CauseForRecClosingPresentManagementIntervention	int64 = 20
+	CauseForRecClosingPresentIntraSGSNIntersystemChange	int64 = 21
+	CauseForRecClosingPresentRATChange	int64 = 22
+	CauseForRecClosingPresentMSTimeZoneChange	int64 = 23
+	CauseForRecClosingPresentSGSNPLMNIDChange	int64 = 24
+	CauseForRecClosingPresentSGWChange	int64 = 25
+	CauseForRecClosingPresentAPNAMBRChange	int64 = 26
+	CauseForRecClosingPresentMOExceptionDataCounterReceipt	int64 = 27
+	CauseForRecClosingPresentUnauthorizedRequestingNetwork	int64 = 52
+	CauseForRecClosingPresentUnauthorizedLCSClient	int64 = 53
+	CauseForRecClosingPresentPositionMethodFailure	int64 = 54
+	CauseForRecClosingPresentUnknownOrUnreachableLCSClient	int64 = 58
+	CauseForRecClosingPresentListofDownstreamNodeChange	int64 = 59
+)
+
 type CauseForRecClosing struct {
 	Value	int64 
 }
 
+var causeForRecClosingNames = map[int64]string{
+	CauseForRecClosingPresentNormalRelease:	"normalRelease",
+	CauseForRecClosingPresentPartialRecord:	"partialRecord",
+	CauseForRecClosingPresentAbnormalRelease:	"abnormalRelease",
+	CauseForRecClosingPresentCAMELInitCallRelease:	"cAMELInitCallRelease",
+	CauseForRecClosingPresentVolumeLimit:	"volumeLimit",
+	CauseForRecClosingPresentTimeLimit:	"timeLimit",
+	CauseForRecClosingPresentServingNodeChange:	"servingNodeChange",
+	CauseForRecClosingPresentMaxChangeCond:	"maxChangeCond",
+	CauseForRecClosingPresentManagementIntervention:	"managementIntervention",
+	CauseForRecClosingPresentIntraSGSNIntersystemChange:	"intraSGSNIntersystemChange",
+	CauseForRecClosingPresentRATChange:	"rATChange",
+	CauseForRecClosingPresentMSTimeZoneChange:	"mSTimeZoneChange",
+	CauseForRecClosingPresentSGSNPLMNIDChange:	"sGSNPLMNIDChange",
+	CauseForRecClosingPresentSGWChange:	"sGWChange",
+	CauseForRecClosingPresentAPNAMBRChange:	"aPNAMBRChange",
+	CauseForRecClosingPresentMOExceptionDataCounterReceipt:	"mOExceptionDataCounterReceipt",
+	CauseForRecClosingPresentUnauthorizedRequestingNetwork:	"unauthorizedRequestingNetwork",
+	CauseForRecClosingPresentUnauthorizedLCSClient:	"unauthorizedLCSClient",
+	CauseForRecClosingPresentPositionMethodFailure:	"positionMethodFailure",
+	CauseForRecClosingPresentUnknownOrUnreachableLCSClient:	"unknownOrUnreachableLCSClient",
+	CauseForRecClosingPresentListofDownstreamNodeChange:	"listofDownstreamNodeChange",
+}
+
+func (v CauseForRecClosing) String() string {
+	if s, ok := causeForRecClosingNames[v.Value]; ok {
+		return s
+	}
+	return unknownEnumString("CauseForRecClosing", int64(v.Value))
+}
+
+// IsValid reports whether the value is one of the named values.
+func (v CauseForRecClosing) IsValid() bool {
+	_, ok := causeForRecClosingNames[v.Value]
+	return ok
+}
+
+func ParseCauseForRecClosing(s string) (CauseForRecClosing, error) {
+	for value, name := range causeForRecClosingNames {
+		if name == s {
+			return CauseForRecClosing{Value: value}, nil
+		}
+	}
+	return CauseForRecClosing{}, unknownEnumError("CauseForRecClosing", s)
+}
+
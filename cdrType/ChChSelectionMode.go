@@ -17,3 +17,35 @@ type ChChSelectionMode struct {
 	Value	asn.Enumerated 
 }
 
+var chChSelectionModeNames = map[asn.Enumerated]string{
+	ChChSelectionModePresentServingNodeSupplied:	"servingNodeSupplied",
+	ChChSelectionModePresentSubscriptionSpecific:	"subscriptionSpecific",
+	ChChSelectionModePresentAPNSpecific:	"aPNSpecific",
+	ChChSelectionModePresentHomeDefault:	"homeDefault",
+	ChChSelectionModePresentRoamingDefault:	"roamingDefault",
+	ChChSelectionModePresentVisitingDefault:	"visitingDefault",
+	ChChSelectionModePresentFixedDefault:	"fixedDefault",
+}
+
+func (v ChChSelectionMode) String() string {
+	if s, ok := chChSelectionModeNames[v.Value]; ok {
+		return s
+	}
+	return unknownEnumString("ChChSelectionMode", int64(v.Value))
+}
+
+// IsValid reports whether the value is one of the named values.
+func (v ChChSelectionMode) IsValid() bool {
+	_, ok := chChSelectionModeNames[v.Value]
+	return ok
+}
+
+func ParseChChSelectionMode(s string) (ChChSelectionMode, error) {
+	for value, name := range chChSelectionModeNames {
+		if name == s {
+			return ChChSelectionMode{Value: value}, nil
+		}
+	}
+	return ChChSelectionMode{}, unknownEnumError("ChChSelectionMode", s)
+}
+
@@ -12,3 +12,30 @@ type CoreNetworkType struct {
 	Value	asn.Enumerated 
 }
 
+var coreNetworkTypeNames = map[asn.Enumerated]string{
+	CoreNetworkTypePresentFiveGC:	"fiveGC",
+	CoreNetworkTypePresentEPC:	"ePC",
+}
+
+func (v CoreNetworkType) String() string {
+	if s, ok := coreNetworkTypeNames[v.Value]; ok {
+		return s
+	}
+	return unknownEnumString("CoreNetworkType", int64(v.Value))
+}
+
+// IsValid reports whether the value is one of the named values.
+func (v CoreNetworkType) IsValid() bool {
+	_, ok := coreNetworkTypeNames[v.Value]
+	return ok
+}
+
+func ParseCoreNetworkType(s string) (CoreNetworkType, error) {
+	for value, name := range coreNetworkTypeNames {
+		if name == s {
+			return CoreNetworkType{Value: value}, nil
+		}
+	}
+	return CoreNetworkType{}, unknownEnumError("CoreNetworkType", s)
+}
+
@@ -13,3 +13,31 @@ type DNNSelectionMode struct {
 	Value	asn.Enumerated 
 }
 
+var dnnSelectionModeNames = map[asn.Enumerated]string{
+	DNNSelectionModePresentUEorNetworkProvidedSubscriptionVerified:	"uEorNetworkProvidedSubscriptionVerified",
+	DNNSelectionModePresentUEProvidedSubscriptionNotVerified:	"uEProvidedSubscriptionNotVerified",
+	DNNSelectionModePresentNetworkProvidedSubscriptionNotVerified:	"networkProvidedSubscriptionNotVerified",
+}
+
+func (v DNNSelectionMode) String() string {
+	if s, ok := dnnSelectionModeNames[v.Value]; ok {
+		return s
+	}
+	return unknownEnumString("DNNSelectionMode", int64(v.Value))
+}
+
+// IsValid reports whether the value is one of the named values.
+func (v DNNSelectionMode) IsValid() bool {
+	_, ok := dnnSelectionModeNames[v.Value]
+	return ok
+}
+
+func ParseDNNSelectionMode(s string) (DNNSelectionMode, error) {
+	for value, name := range dnnSelectionModeNames {
+		if name == s {
+			return DNNSelectionMode{Value: value}, nil
+		}
+	}
+	return DNNSelectionMode{}, unknownEnumError("DNNSelectionMode", s)
+}
+
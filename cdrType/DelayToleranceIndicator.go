@@ -12,3 +12,30 @@ type DelayToleranceIndicator struct {
 	Value	asn.Enumerated 
 }
 
+var delayToleranceIndicatorNames = map[asn.Enumerated]string{
+	DelayToleranceIndicatorPresentDTSupported:	"dTSupported",
+	DelayToleranceIndicatorPresentDTNotSupported:	"dTNotSupported",
+}
+
+func (v DelayToleranceIndicator) String() string {
+	if s, ok := delayToleranceIndicatorNames[v.Value]; ok {
+		return s
+	}
+	return unknownEnumString("DelayToleranceIndicator", int64(v.Value))
+}
+
+// IsValid reports whether the value is one of the named values.
+func (v DelayToleranceIndicator) IsValid() bool {
+	_, ok := delayToleranceIndicatorNames[v.Value]
+	return ok
+}
+
+func ParseDelayToleranceIndicator(s string) (DelayToleranceIndicator, error) {
+	for value, name := range delayToleranceIndicatorNames {
+		if name == s {
+			return DelayToleranceIndicator{Value: value}, nil
+		}
+	}
+	return DelayToleranceIndicator{}, unknownEnumError("DelayToleranceIndicator", s)
+}
+
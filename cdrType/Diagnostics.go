@@ -26,3 +26,89 @@ type Diagnostics struct {
 	DiameterResultCodeAndExperimentalResult	*int64 `ber:"tagNum:7"`
 }
 
+func NewDiagnosticsGsm0408Cause(v int64) Diagnostics {
+	return Diagnostics{Present: DiagnosticsPresentGsm0408Cause, Gsm0408Cause: &v}
+}
+
+func NewDiagnosticsGsm0902MapErrorValue(v int64) Diagnostics {
+	return Diagnostics{Present: DiagnosticsPresentGsm0902MapErrorValue, Gsm0902MapErrorValue: &v}
+}
+
+func NewDiagnosticsItuTQ767Cause(v int64) Diagnostics {
+	return Diagnostics{Present: DiagnosticsPresentItuTQ767Cause, ItuTQ767Cause: &v}
+}
+
+func NewDiagnosticsNetworkSpecificCause(v ManagementExtension) Diagnostics {
+	return Diagnostics{Present: DiagnosticsPresentNetworkSpecificCause, NetworkSpecificCause: &v}
+}
+
+func NewDiagnosticsManufacturerSpecificCause(v ManagementExtension) Diagnostics {
+	return Diagnostics{Present: DiagnosticsPresentManufacturerSpecificCause, ManufacturerSpecificCause: &v}
+}
+
+func NewDiagnosticsPositionMethodFailureCause(v PositionMethodFailureDiagnostic) Diagnostics {
+	return Diagnostics{Present: DiagnosticsPresentPositionMethodFailureCause, PositionMethodFailureCause: &v}
+}
+
+func NewDiagnosticsUnauthorizedLCSClientCause(v UnauthorizedLCSClientDiagnostic) Diagnostics {
+	return Diagnostics{Present: DiagnosticsPresentUnauthorizedLCSClientCause, UnauthorizedLCSClientCause: &v}
+}
+
+func NewDiagnosticsDiameterResultCodeAndExperimentalResult(v int64) Diagnostics {
+	return Diagnostics{Present: DiagnosticsPresentDiameterResultCodeAndExperimentalResult, DiameterResultCodeAndExperimentalResult: &v}
+}
+
+// Value returns the alternative selected by Present, nil if none is or
+// the selected one is not set.
+func (c Diagnostics) Value() interface{} {
+	switch c.Present {
+	case DiagnosticsPresentGsm0408Cause:
+		if c.Gsm0408Cause != nil {
+			return c.Gsm0408Cause
+		}
+	case DiagnosticsPresentGsm0902MapErrorValue:
+		if c.Gsm0902MapErrorValue != nil {
+			return c.Gsm0902MapErrorValue
+		}
+	case DiagnosticsPresentItuTQ767Cause:
+		if c.ItuTQ767Cause != nil {
+			return c.ItuTQ767Cause
+		}
+	case DiagnosticsPresentNetworkSpecificCause:
+		if c.NetworkSpecificCause != nil {
+			return c.NetworkSpecificCause
+		}
+	case DiagnosticsPresentManufacturerSpecificCause:
+		if c.ManufacturerSpecificCause != nil {
+			return c.ManufacturerSpecificCause
+		}
+	case DiagnosticsPresentPositionMethodFailureCause:
+		if c.PositionMethodFailureCause != nil {
+			return c.PositionMethodFailureCause
+		}
+	case DiagnosticsPresentUnauthorizedLCSClientCause:
+		if c.UnauthorizedLCSClientCause != nil {
+			return c.UnauthorizedLCSClientCause
+		}
+	case DiagnosticsPresentDiameterResultCodeAndExperimentalResult:
+		if c.DiameterResultCodeAndExperimentalResult != nil {
+			return c.DiameterResultCodeAndExperimentalResult
+		}
+	}
+	return nil
+}
+
+// Validate checks that exactly the alternative selected by Present is set.
+func (c Diagnostics) Validate() error {
+	return validateChoice("Diagnostics", c.Present, []choiceAlternative{
+		{"Gsm0408Cause", c.Gsm0408Cause != nil},
+		{"Gsm0902MapErrorValue", c.Gsm0902MapErrorValue != nil},
+		{"ItuTQ767Cause", c.ItuTQ767Cause != nil},
+		{"NetworkSpecificCause", c.NetworkSpecificCause != nil},
+		{"ManufacturerSpecificCause", c.ManufacturerSpecificCause != nil},
+		{"PositionMethodFailureCause", c.PositionMethodFailureCause != nil},
+		{"UnauthorizedLCSClientCause", c.UnauthorizedLCSClientCause != nil},
+		{"DiameterResultCodeAndExperimentalResult", c.DiameterResultCodeAndExperimentalResult != nil},
+	})
+}
+
@@ -21,3 +21,62 @@ type IPAddress struct {
 	IPBinV6AddressWithPrefix	*IPBinV6AddressWithPrefixLength `ber:"tagNum:4"`
 }
 
+func NewIPAddressIPBinV4Address(v IPBinV4Address) IPAddress {
+	return IPAddress{Present: IPAddressPresentIPBinV4Address, IPBinV4Address: &v}
+}
+
+func NewIPAddressIPBinV6Address(v IPBinV6Address) IPAddress {
+	return IPAddress{Present: IPAddressPresentIPBinV6Address, IPBinV6Address: &v}
+}
+
+func NewIPAddressIPTextV4Address(v asn.IA5String) IPAddress {
+	return IPAddress{Present: IPAddressPresentIPTextV4Address, IPTextV4Address: &v}
+}
+
+func NewIPAddressIPTextV6Address(v asn.IA5String) IPAddress {
+	return IPAddress{Present: IPAddressPresentIPTextV6Address, IPTextV6Address: &v}
+}
+
+func NewIPAddressIPBinV6AddressWithPrefix(v IPBinV6AddressWithPrefixLength) IPAddress {
+	return IPAddress{Present: IPAddressPresentIPBinV6AddressWithPrefix, IPBinV6AddressWithPrefix: &v}
+}
+
+// Value returns the alternative selected by Present, nil if none is or
+// the selected one is not set.
+func (c IPAddress) Value() interface{} {
+	switch c.Present {
+	case IPAddressPresentIPBinV4Address:
+		if c.IPBinV4Address != nil {
+			return c.IPBinV4Address
+		}
+	case IPAddressPresentIPBinV6Address:
+		if c.IPBinV6Address != nil {
+			return c.IPBinV6Address
+		}
+	case IPAddressPresentIPTextV4Address:
+		if c.IPTextV4Address != nil {
+			return c.IPTextV4Address
+		}
+	case IPAddressPresentIPTextV6Address:
+		if c.IPTextV6Address != nil {
+			return c.IPTextV6Address
+		}
+	case IPAddressPresentIPBinV6AddressWithPrefix:
+		if c.IPBinV6AddressWithPrefix != nil {
+			return c.IPBinV6AddressWithPrefix
+		}
+	}
+	return nil
+}
+
+// Validate checks that exactly the alternative selected by Present is set.
+func (c IPAddress) Validate() error {
+	return validateChoice("IPAddress", c.Present, []choiceAlternative{
+		{"IPBinV4Address", c.IPBinV4Address != nil},
+		{"IPBinV6Address", c.IPBinV6Address != nil},
+		{"IPTextV4Address", c.IPTextV4Address != nil},
+		{"IPTextV6Address", c.IPTextV6Address != nil},
+		{"IPBinV6AddressWithPrefix", c.IPBinV6AddressWithPrefix != nil},
+	})
+}
+
@@ -14,3 +14,35 @@ type IPBinV6AddressWithOrWithoutPrefixLength struct {
 	IPBinV6AddressWithPrefix	*IPBinV6AddressWithPrefixLength `ber:"tagNum:4"`
 }
 
+func NewIPBinV6AddressWithOrWithoutPrefixLengthIPBinV6Address(v IPBinV6Address) IPBinV6AddressWithOrWithoutPrefixLength {
+	return IPBinV6AddressWithOrWithoutPrefixLength{Present: IPBinV6AddressWithOrWithoutPrefixLengthPresentIPBinV6Address, IPBinV6Address: &v}
+}
+
+func NewIPBinV6AddressWithOrWithoutPrefixLengthIPBinV6AddressWithPrefix(v IPBinV6AddressWithPrefixLength) IPBinV6AddressWithOrWithoutPrefixLength {
+	return IPBinV6AddressWithOrWithoutPrefixLength{Present: IPBinV6AddressWithOrWithoutPrefixLengthPresentIPBinV6AddressWithPrefix, IPBinV6AddressWithPrefix: &v}
+}
+
+// Value returns the alternative selected by Present, nil if none is or
+// the selected one is not set.
+func (c IPBinV6AddressWithOrWithoutPrefixLength) Value() interface{} {
+	switch c.Present {
+	case IPBinV6AddressWithOrWithoutPrefixLengthPresentIPBinV6Address:
+		if c.IPBinV6Address != nil {
+			return c.IPBinV6Address
+		}
+	case IPBinV6AddressWithOrWithoutPrefixLengthPresentIPBinV6AddressWithPrefix:
+		if c.IPBinV6AddressWithPrefix != nil {
+			return c.IPBinV6AddressWithPrefix
+		}
+	}
+	return nil
+}
+
+// Validate checks that exactly the alternative selected by Present is set.
+func (c IPBinV6AddressWithOrWithoutPrefixLength) Validate() error {
+	return validateChoice("IPBinV6AddressWithOrWithoutPrefixLength", c.Present, []choiceAlternative{
+		{"IPBinV6Address", c.IPBinV6Address != nil},
+		{"IPBinV6AddressWithPrefix", c.IPBinV6AddressWithPrefix != nil},
+	})
+}
+
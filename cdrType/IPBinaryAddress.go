@@ -14,3 +14,35 @@ type IPBinaryAddress struct {
 	IPBinV6Address	*IPBinV6AddressWithOrWithoutPrefixLength 
 }
 
+func NewIPBinaryAddressIPBinV4Address(v IPBinV4Address) IPBinaryAddress {
+	return IPBinaryAddress{Present: IPBinaryAddressPresentIPBinV4Address, IPBinV4Address: &v}
+}
+
+func NewIPBinaryAddressIPBinV6Address(v IPBinV6AddressWithOrWithoutPrefixLength) IPBinaryAddress {
+	return IPBinaryAddress{Present: IPBinaryAddressPresentIPBinV6Address, IPBinV6Address: &v}
+}
+
+// Value returns the alternative selected by Present, nil if none is or
+// the selected one is not set.
+func (c IPBinaryAddress) Value() interface{} {
+	switch c.Present {
+	case IPBinaryAddressPresentIPBinV4Address:
+		if c.IPBinV4Address != nil {
+			return c.IPBinV4Address
+		}
+	case IPBinaryAddressPresentIPBinV6Address:
+		if c.IPBinV6Address != nil {
+			return c.IPBinV6Address
+		}
+	}
+	return nil
+}
+
+// Validate checks that exactly the alternative selected by Present is set.
+func (c IPBinaryAddress) Validate() error {
+	return validateChoice("IPBinaryAddress", c.Present, []choiceAlternative{
+		{"IPBinV4Address", c.IPBinV4Address != nil},
+		{"IPBinV6Address", c.IPBinV6Address != nil},
+	})
+}
+
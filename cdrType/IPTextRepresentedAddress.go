@@ -15,3 +15,35 @@ type IPTextRepresentedAddress struct {
 	IPTextV6Address	*asn.IA5String `ber:"tagNum:3"`
 }
 
+func NewIPTextRepresentedAddressIPTextV4Address(v asn.IA5String) IPTextRepresentedAddress {
+	return IPTextRepresentedAddress{Present: IPTextRepresentedAddressPresentIPTextV4Address, IPTextV4Address: &v}
+}
+
+func NewIPTextRepresentedAddressIPTextV6Address(v asn.IA5String) IPTextRepresentedAddress {
+	return IPTextRepresentedAddress{Present: IPTextRepresentedAddressPresentIPTextV6Address, IPTextV6Address: &v}
+}
+
+// Value returns the alternative selected by Present, nil if none is or
+// the selected one is not set.
+func (c IPTextRepresentedAddress) Value() interface{} {
+	switch c.Present {
+	case IPTextRepresentedAddressPresentIPTextV4Address:
+		if c.IPTextV4Address != nil {
+			return c.IPTextV4Address
+		}
+	case IPTextRepresentedAddressPresentIPTextV6Address:
+		if c.IPTextV6Address != nil {
+			return c.IPTextV6Address
+		}
+	}
+	return nil
+}
+
+// Validate checks that exactly the alternative selected by Present is set.
+func (c IPTextRepresentedAddress) Validate() error {
+	return validateChoice("IPTextRepresentedAddress", c.Present, []choiceAlternative{
+		{"IPTextV4Address", c.IPTextV4Address != nil},
+		{"IPTextV6Address", c.IPTextV6Address != nil},
+	})
+}
+
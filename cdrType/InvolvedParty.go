@@ -21,3 +21,62 @@ type InvolvedParty struct {
 	ExternalId	*asn.UTF8String `ber:"tagNum:4"`
 }
 
+func NewInvolvedPartySIPURI(v asn.GraphicString) InvolvedParty {
+	return InvolvedParty{Present: InvolvedPartyPresentSIPURI, SIPURI: &v}
+}
+
+func NewInvolvedPartyTELURI(v asn.GraphicString) InvolvedParty {
+	return InvolvedParty{Present: InvolvedPartyPresentTELURI, TELURI: &v}
+}
+
+func NewInvolvedPartyURN(v asn.GraphicString) InvolvedParty {
+	return InvolvedParty{Present: InvolvedPartyPresentURN, URN: &v}
+}
+
+func NewInvolvedPartyISDNE164(v asn.GraphicString) InvolvedParty {
+	return InvolvedParty{Present: InvolvedPartyPresentISDNE164, ISDNE164: &v}
+}
+
+func NewInvolvedPartyExternalId(v asn.UTF8String) InvolvedParty {
+	return InvolvedParty{Present: InvolvedPartyPresentExternalId, ExternalId: &v}
+}
+
+// Value returns the alternative selected by Present, nil if none is or
+// the selected one is not set.
+func (c InvolvedParty) Value() interface{} {
+	switch c.Present {
+	case InvolvedPartyPresentSIPURI:
+		if c.SIPURI != nil {
+			return c.SIPURI
+		}
+	case InvolvedPartyPresentTELURI:
+		if c.TELURI != nil {
+			return c.TELURI
+		}
+	case InvolvedPartyPresentURN:
+		if c.URN != nil {
+			return c.URN
+		}
+	case InvolvedPartyPresentISDNE164:
+		if c.ISDNE164 != nil {
+			return c.ISDNE164
+		}
+	case InvolvedPartyPresentExternalId:
+		if c.ExternalId != nil {
+			return c.ExternalId
+		}
+	}
+	return nil
+}
+
+// Validate checks that exactly the alternative selected by Present is set.
+func (c InvolvedParty) Validate() error {
+	return validateChoice("InvolvedParty", c.Present, []choiceAlternative{
+		{"SIPURI", c.SIPURI != nil},
+		{"TELURI", c.TELURI != nil},
+		{"URN", c.URN != nil},
+		{"ISDNE164", c.ISDNE164 != nil},
+		{"ExternalId", c.ExternalId != nil},
+	})
+}
+
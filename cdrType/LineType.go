@@ -12,3 +12,30 @@ type LineType struct {
 	Value	asn.Enumerated 
 }
 
+var lineTypeNames = map[asn.Enumerated]string{
+	LineTypePresentDSL:	"dSL",
+	LineTypePresentPON:	"pON",
+}
+
+func (v LineType) String() string {
+	if s, ok := lineTypeNames[v.Value]; ok {
+		return s
+	}
+	return unknownEnumString("LineType", int64(v.Value))
+}
+
+// IsValid reports whether the value is one of the named values.
+func (v LineType) IsValid() bool {
+	_, ok := lineTypeNames[v.Value]
+	return ok
+}
+
+func ParseLineType(s string) (LineType, error) {
+	for value, name := range lineTypeNames {
+		if name == s {
+			return LineType{Value: value}, nil
+		}
+	}
+	return LineType{}, unknownEnumError("LineType", s)
+}
+
@@ -12,3 +12,30 @@ type MAPDUSessionIndicator struct {
 	Value	asn.Enumerated 
 }
 
+var mapduSessionIndicatorNames = map[asn.Enumerated]string{
+	MAPDUSessionIndicatorPresentMAPDURequest:	"mAPDURequest",
+	MAPDUSessionIndicatorPresentMAPDUNetworkUpgradeAllowed:	"mAPDUNetworkUpgradeAllowed",
+}
+
+func (v MAPDUSessionIndicator) String() string {
+	if s, ok := mapduSessionIndicatorNames[v.Value]; ok {
+		return s
+	}
+	return unknownEnumString("MAPDUSessionIndicator", int64(v.Value))
+}
+
+// IsValid reports whether the value is one of the named values.
+func (v MAPDUSessionIndicator) IsValid() bool {
+	_, ok := mapduSessionIndicatorNames[v.Value]
+	return ok
+}
+
+func ParseMAPDUSessionIndicator(s string) (MAPDUSessionIndicator, error) {
+	for value, name := range mapduSessionIndicatorNames {
+		if name == s {
+			return MAPDUSessionIndicator{Value: value}, nil
+		}
+	}
+	return MAPDUSessionIndicator{}, unknownEnumError("MAPDUSessionIndicator", s)
+}
+
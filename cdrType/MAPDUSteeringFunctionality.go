@@ -12,3 +12,30 @@ type MAPDUSteeringFunctionality struct {
 	Value	asn.Enumerated 
 }
 
+var mapduSteeringFunctionalityNames = map[asn.Enumerated]string{
+	MAPDUSteeringFunctionalityPresentMPTCP:	"mPTCP",
+	MAPDUSteeringFunctionalityPresentATSSSLL:	"aTSSSLL",
+}
+
+func (v MAPDUSteeringFunctionality) String() string {
+	if s, ok := mapduSteeringFunctionalityNames[v.Value]; ok {
+		return s
+	}
+	return unknownEnumString("MAPDUSteeringFunctionality", int64(v.Value))
+}
+
+// IsValid reports whether the value is one of the named values.
+func (v MAPDUSteeringFunctionality) IsValid() bool {
+	_, ok := mapduSteeringFunctionalityNames[v.Value]
+	return ok
+}
+
+func ParseMAPDUSteeringFunctionality(s string) (MAPDUSteeringFunctionality, error) {
+	for value, name := range mapduSteeringFunctionalityNames {
+		if name == s {
+			return MAPDUSteeringFunctionality{Value: value}, nil
+		}
+	}
+	return MAPDUSteeringFunctionality{}, unknownEnumError("MAPDUSteeringFunctionality", s)
+}
+
@@ -12,3 +12,30 @@ type MICOModeIndication struct {
 	Value	asn.Enumerated 
 }
 
+var micoModeIndicationNames = map[asn.Enumerated]string{
+	MICOModeIndicationPresentMICOMode:	"mICOMode",
+	MICOModeIndicationPresentNoMICOMode:	"noMICOMode",
+}
+
+func (v MICOModeIndication) String() string {
+	if s, ok := micoModeIndicationNames[v.Value]; ok {
+		return s
+	}
+	return unknownEnumString("MICOModeIndication", int64(v.Value))
+}
+
+// IsValid reports whether the value is one of the named values.
+func (v MICOModeIndication) IsValid() bool {
+	_, ok := micoModeIndicationNames[v.Value]
+	return ok
+}
+
+func ParseMICOModeIndication(s string) (MICOModeIndication, error) {
+	for value, name := range micoModeIndicationNames {
+		if name == s {
+			return MICOModeIndication{Value: value}, nil
+		}
+	}
+	return MICOModeIndication{}, unknownEnumError("MICOModeIndication", s)
+}
+
@@ -13,3 +13,31 @@ type ManagementOperation struct {
 	Value	asn.Enumerated 
 }
 
+var managementOperationNames = map[asn.Enumerated]string{
+	ManagementOperationPresentCreateMOI:	"createMOI",
+	ManagementOperationPresentModifyMOIAttributes:	"modifyMOIAttributes",
+	ManagementOperationPresentDeleteMOI:	"deleteMOI",
+}
+
+func (v ManagementOperation) String() string {
+	if s, ok := managementOperationNames[v.Value]; ok {
+		return s
+	}
+	return unknownEnumString("ManagementOperation", int64(v.Value))
+}
+
+// IsValid reports whether the value is one of the named values.
+func (v ManagementOperation) IsValid() bool {
+	_, ok := managementOperationNames[v.Value]
+	return ok
+}
+
+func ParseManagementOperation(s string) (ManagementOperation, error) {
+	for value, name := range managementOperationNames {
+		if name == s {
+			return ManagementOperation{Value: value}, nil
+		}
+	}
+	return ManagementOperation{}, unknownEnumError("ManagementOperation", s)
+}
+
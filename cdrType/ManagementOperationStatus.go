@@ -12,3 +12,30 @@ type ManagementOperationStatus struct {
 	Value	asn.Enumerated 
 }
 
+var managementOperationStatusNames = map[asn.Enumerated]string{
+	ManagementOperationStatusPresentOPERATIONSUCCEEDED:	"oPERATIONSUCCEEDED",
+	ManagementOperationStatusPresentOPERATIONFAILED:	"oPERATIONFAILED",
+}
+
+func (v ManagementOperationStatus) String() string {
+	if s, ok := managementOperationStatusNames[v.Value]; ok {
+		return s
+	}
+	return unknownEnumString("ManagementOperationStatus", int64(v.Value))
+}
+
+// IsValid reports whether the value is one of the named values.
+func (v ManagementOperationStatus) IsValid() bool {
+	_, ok := managementOperationStatusNames[v.Value]
+	return ok
+}
+
+func ParseManagementOperationStatus(s string) (ManagementOperationStatus, error) {
+	for value, name := range managementOperationStatusNames {
+		if name == s {
+			return ManagementOperationStatus{Value: value}, nil
+		}
+	}
+	return ManagementOperationStatus{}, unknownEnumError("ManagementOperationStatus", s)
+}
+
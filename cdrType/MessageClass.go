@@ -14,3 +14,32 @@ type MessageClass struct {
 	Value	asn.Enumerated 
 }
 
+var messageClassNames = map[asn.Enumerated]string{
+	MessageClassPresentPersonal:	"personal",
+	MessageClassPresentAdvertisement:	"advertisement",
+	MessageClassPresentInformationService:	"informationService",
+	MessageClassPresentAuto:	"auto",
+}
+
+func (v MessageClass) String() string {
+	if s, ok := messageClassNames[v.Value]; ok {
+		return s
+	}
+	return unknownEnumString("MessageClass", int64(v.Value))
+}
+
+// IsValid reports whether the value is one of the named values.
+func (v MessageClass) IsValid() bool {
+	_, ok := messageClassNames[v.Value]
+	return ok
+}
+
+func ParseMessageClass(s string) (MessageClass, error) {
+	for value, name := range messageClassNames {
+		if name == s {
+			return MessageClass{Value: value}, nil
+		}
+	}
+	return MessageClass{}, unknownEnumError("MessageClass", s)
+}
+
@@ -14,3 +14,32 @@ type MobilityLevel struct {
 	Value	asn.Enumerated 
 }
 
+var mobilityLevelNames = map[asn.Enumerated]string{
+	MobilityLevelPresentStationary:	"stationary",
+	MobilityLevelPresentNomadic:	"nomadic",
+	MobilityLevelPresentRestrictedMobility:	"restrictedMobility",
+	MobilityLevelPresentFullyMobility:	"fullyMobility",
+}
+
+func (v MobilityLevel) String() string {
+	if s, ok := mobilityLevelNames[v.Value]; ok {
+		return s
+	}
+	return unknownEnumString("MobilityLevel", int64(v.Value))
+}
+
+// IsValid reports whether the value is one of the named values.
+func (v MobilityLevel) IsValid() bool {
+	_, ok := mobilityLevelNames[v.Value]
+	return ok
+}
+
+func ParseMobilityLevel(s string) (MobilityLevel, error) {
+	for value, name := range mobilityLevelNames {
+		if name == s {
+			return MobilityLevel{Value: value}, nil
+		}
+	}
+	return MobilityLevel{}, unknownEnumError("MobilityLevel", s)
+}
+
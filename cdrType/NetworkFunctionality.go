@@ -21,3 +21,39 @@ type NetworkFunctionality struct {
 	Value	asn.Enumerated 
 }
 
+var networkFunctionalityNames = map[asn.Enumerated]string{
+	NetworkFunctionalityPresentCHF:	"cHF",
+	NetworkFunctionalityPresentSMF:	"sMF",
+	NetworkFunctionalityPresentAMF:	"aMF",
+	NetworkFunctionalityPresentSMSF:	"sMSF",
+	NetworkFunctionalityPresentSGW:	"sGW",
+	NetworkFunctionalityPresentISMF:	"iSMF",
+	NetworkFunctionalityPresentEPDG:	"ePDG",
+	NetworkFunctionalityPresentCEF:	"cEF",
+	NetworkFunctionalityPresentNEF:	"nEF",
+	NetworkFunctionalityPresentPGWCSMF:	"pGWCSMF",
+	NetworkFunctionalityPresentMnSProducer:	"mnSProducer",
+}
+
+func (v NetworkFunctionality) String() string {
+	if s, ok := networkFunctionalityNames[v.Value]; ok {
+		return s
+	}
+	return unknownEnumString("NetworkFunctionality", int64(v.Value))
+}
+
+// IsValid reports whether the value is one of the named values.
+func (v NetworkFunctionality) IsValid() bool {
+	_, ok := networkFunctionalityNames[v.Value]
+	return ok
+}
+
+func ParseNetworkFunctionality(s string) (NetworkFunctionality, error) {
+	for value, name := range networkFunctionalityNames {
+		if name == s {
+			return NetworkFunctionality{Value: value}, nil
+		}
+	}
+	return NetworkFunctionality{}, unknownEnumError("NetworkFunctionality", s)
+}
+
@@ -15,3 +15,35 @@ type NodeAddress struct {
 	DomainName	*asn.GraphicString `ber:"tagNum:1"`
 }
 
+func NewNodeAddressIPAddress(v IPAddress) NodeAddress {
+	return NodeAddress{Present: NodeAddressPresentIPAddress, IPAddress: &v}
+}
+
+func NewNodeAddressDomainName(v asn.GraphicString) NodeAddress {
+	return NodeAddress{Present: NodeAddressPresentDomainName, DomainName: &v}
+}
+
+// Value returns the alternative selected by Present, nil if none is or
+// the selected one is not set.
+func (c NodeAddress) Value() interface{} {
+	switch c.Present {
+	case NodeAddressPresentIPAddress:
+		if c.IPAddress != nil {
+			return c.IPAddress
+		}
+	case NodeAddressPresentDomainName:
+		if c.DomainName != nil {
+			return c.DomainName
+		}
+	}
+	return nil
+}
+
+// Validate checks that exactly the alternative selected by Present is set.
+func (c NodeAddress) Validate() error {
+	return validateChoice("NodeAddress", c.Present, []choiceAlternative{
+		{"IPAddress", c.IPAddress != nil},
+		{"DomainName", c.DomainName != nil},
+	})
+}
+
@@ -12,3 +12,30 @@ type OperationalState struct {
 	Value	asn.Enumerated 
 }
 
+var operationalStateNames = map[asn.Enumerated]string{
+	OperationalStatePresentENABLED:	"eNABLED",
+	OperationalStatePresentDISABLED:	"dISABLED",
+}
+
+func (v OperationalState) String() string {
+	if s, ok := operationalStateNames[v.Value]; ok {
+		return s
+	}
+	return unknownEnumString("OperationalState", int64(v.Value))
+}
+
+// IsValid reports whether the value is one of the named values.
+func (v OperationalState) IsValid() bool {
+	_, ok := operationalStateNames[v.Value]
+	return ok
+}
+
+func ParseOperationalState(s string) (OperationalState, error) {
+	for value, name := range operationalStateNames {
+		if name == s {
+			return OperationalState{Value: value}, nil
+		}
+	}
+	return OperationalState{}, unknownEnumError("OperationalState", s)
+}
+
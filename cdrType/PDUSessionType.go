@@ -15,3 +15,33 @@ type PDUSessionType struct {
 	Value	asn.Enumerated 
 }
 
+var pduSessionTypeNames = map[asn.Enumerated]string{
+	PDUSessionTypePresentIPv4v6:	"iPv4v6",
+	PDUSessionTypePresentIPv4:	"iPv4",
+	PDUSessionTypePresentIPv6:	"iPv6",
+	PDUSessionTypePresentUnstructured:	"unstructured",
+	PDUSessionTypePresentEthernet:	"ethernet",
+}
+
+func (v PDUSessionType) String() string {
+	if s, ok := pduSessionTypeNames[v.Value]; ok {
+		return s
+	}
+	return unknownEnumString("PDUSessionType", int64(v.Value))
+}
+
+// IsValid reports whether the value is one of the named values.
+func (v PDUSessionType) IsValid() bool {
+	_, ok := pduSessionTypeNames[v.Value]
+	return ok
+}
+
+func ParsePDUSessionType(s string) (PDUSessionType, error) {
+	for value, name := range pduSessionTypeNames {
+		if name == s {
+			return PDUSessionType{Value: value}, nil
+		}
+	}
+	return PDUSessionType{}, unknownEnumError("PDUSessionType", s)
+}
+
@@ -12,3 +12,30 @@ type PartialRecordMethod struct {
 	Value	asn.Enumerated 
 }
 
+var partialRecordMethodNames = map[asn.Enumerated]string{
+	PartialRecordMethodPresentDefault:	"default",
+	PartialRecordMethodPresentIndividual:	"individual",
+}
+
+func (v PartialRecordMethod) String() string {
+	if s, ok := partialRecordMethodNames[v.Value]; ok {
+		return s
+	}
+	return unknownEnumString("PartialRecordMethod", int64(v.Value))
+}
+
+// IsValid reports whether the value is one of the named values.
+func (v PartialRecordMethod) IsValid() bool {
+	_, ok := partialRecordMethodNames[v.Value]
+	return ok
+}
+
+func ParsePartialRecordMethod(s string) (PartialRecordMethod, error) {
+	for value, name := range partialRecordMethodNames {
+		if name == s {
+			return PartialRecordMethod{Value: value}, nil
+		}
+	}
+	return PartialRecordMethod{}, unknownEnumError("PartialRecordMethod", s)
+}
+
@@ -19,3 +19,37 @@ type PositionMethodFailureDiagnostic struct {
 	Value	asn.Enumerated 
 }
 
+var positionMethodFailureDiagnosticNames = map[asn.Enumerated]string{
+	PositionMethodFailureDiagnosticPresentCongestion:	"congestion",
+	PositionMethodFailureDiagnosticPresentInsufficientResources:	"insufficientResources",
+	PositionMethodFailureDiagnosticPresentInsufficientMeasurementData:	"insufficientMeasurementData",
+	PositionMethodFailureDiagnosticPresentInconsistentMeasurementData:	"inconsistentMeasurementData",
+	PositionMethodFailureDiagnosticPresentLocationProcedureNotCompleted:	"locationProcedureNotCompleted",
+	PositionMethodFailureDiagnosticPresentLocationProcedureNotSupportedByTargetMS:	"locationProcedureNotSupportedByTargetMS",
+	PositionMethodFailureDiagnosticPresentQoSNotAttainable:	"qoSNotAttainable",
+	PositionMethodFailureDiagnosticPresentPositionMethodNotAvailableInNetwork:	"positionMethodNotAvailableInNetwork",
+	PositionMethodFailureDiagnosticPresentPositionMethodNotAvailableInLocationArea:	"positionMethodNotAvailableInLocationArea",
+}
+
+func (v PositionMethodFailureDiagnostic) String() string {
+	if s, ok := positionMethodFailureDiagnosticNames[v.Value]; ok {
+		return s
+	}
+	return unknownEnumString("PositionMethodFailureDiagnostic", int64(v.Value))
+}
+
+// IsValid reports whether the value is one of the named values.
+func (v PositionMethodFailureDiagnostic) IsValid() bool {
+	_, ok := positionMethodFailureDiagnosticNames[v.Value]
+	return ok
+}
+
+func ParsePositionMethodFailureDiagnostic(s string) (PositionMethodFailureDiagnostic, error) {
+	for value, name := range positionMethodFailureDiagnosticNames {
+		if name == s {
+			return PositionMethodFailureDiagnostic{Value: value}, nil
+		}
+	}
+	return PositionMethodFailureDiagnostic{}, unknownEnumError("PositionMethodFailureDiagnostic", s)
+}
+
@@ -12,3 +12,30 @@ type PreemptionCapability struct {
 	Value	asn.Enumerated 
 }
 
+var preemptionCapabilityNames = map[asn.Enumerated]string{
+	PreemptionCapabilityPresentNOTPREEMPT:	"nOTPREEMPT",
+	PreemptionCapabilityPresentMAYPREEMPT:	"mAYPREEMPT",
+}
+
+func (v PreemptionCapability) String() string {
+	if s, ok := preemptionCapabilityNames[v.Value]; ok {
+		return s
+	}
+	return unknownEnumString("PreemptionCapability", int64(v.Value))
+}
+
+// IsValid reports whether the value is one of the named values.
+func (v PreemptionCapability) IsValid() bool {
+	_, ok := preemptionCapabilityNames[v.Value]
+	return ok
+}
+
+func ParsePreemptionCapability(s string) (PreemptionCapability, error) {
+	for value, name := range preemptionCapabilityNames {
+		if name == s {
+			return PreemptionCapability{Value: value}, nil
+		}
+	}
+	return PreemptionCapability{}, unknownEnumError("PreemptionCapability", s)
+}
+
@@ -12,3 +12,30 @@ type PreemptionVulnerability struct {
 	Value	asn.Enumerated 
 }
 
+var preemptionVulnerabilityNames = map[asn.Enumerated]string{
+	PreemptionVulnerabilityPresentNOTPREEMPTABLE:	"nOTPREEMPTABLE",
+	PreemptionVulnerabilityPresentPREEMPTABLE:	"pREEMPTABLE",
+}
+
+func (v PreemptionVulnerability) String() string {
+	if s, ok := preemptionVulnerabilityNames[v.Value]; ok {
+		return s
+	}
+	return unknownEnumString("PreemptionVulnerability", int64(v.Value))
+}
+
+// IsValid reports whether the value is one of the named values.
+func (v PreemptionVulnerability) IsValid() bool {
+	_, ok := preemptionVulnerabilityNames[v.Value]
+	return ok
+}
+
+func ParsePreemptionVulnerability(s string) (PreemptionVulnerability, error) {
+	for value, name := range preemptionVulnerabilityNames {
+		if name == s {
+			return PreemptionVulnerability{Value: value}, nil
+		}
+	}
+	return PreemptionVulnerability{}, unknownEnumError("PreemptionVulnerability", s)
+}
+
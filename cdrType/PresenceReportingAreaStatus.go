@@ -14,3 +14,32 @@ type PresenceReportingAreaStatus struct {
 	Value	asn.Enumerated 
 }
 
+var presenceReportingAreaStatusNames = map[asn.Enumerated]string{
+	PresenceReportingAreaStatusPresentInsideArea:	"insideArea",
+	PresenceReportingAreaStatusPresentOutsideArea:	"outsideArea",
+	PresenceReportingAreaStatusPresentInactive:	"inactive",
+	PresenceReportingAreaStatusPresentUnknown:	"unknown",
+}
+
+func (v PresenceReportingAreaStatus) String() string {
+	if s, ok := presenceReportingAreaStatusNames[v.Value]; ok {
+		return s
+	}
+	return unknownEnumString("PresenceReportingAreaStatus", int64(v.Value))
+}
+
+// IsValid reports whether the value is one of the named values.
+func (v PresenceReportingAreaStatus) IsValid() bool {
+	_, ok := presenceReportingAreaStatusNames[v.Value]
+	return ok
+}
+
+func ParsePresenceReportingAreaStatus(s string) (PresenceReportingAreaStatus, error) {
+	for value, name := range presenceReportingAreaStatusNames {
+		if name == s {
+			return PresenceReportingAreaStatus{Value: value}, nil
+		}
+	}
+	return PresenceReportingAreaStatus{}, unknownEnumError("PresenceReportingAreaStatus", s)
+}
+
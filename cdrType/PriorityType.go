@@ -13,3 +13,31 @@ type PriorityType struct {
 	Value	asn.Enumerated 
 }
 
+var priorityTypeNames = map[asn.Enumerated]string{
+	PriorityTypePresentLow:	"low",
+	PriorityTypePresentNormal:	"normal",
+	PriorityTypePresentHigh:	"high",
+}
+
+func (v PriorityType) String() string {
+	if s, ok := priorityTypeNames[v.Value]; ok {
+		return s
+	}
+	return unknownEnumString("PriorityType", int64(v.Value))
+}
+
+// IsValid reports whether the value is one of the named values.
+func (v PriorityType) IsValid() bool {
+	_, ok := priorityTypeNames[v.Value]
+	return ok
+}
+
+func ParsePriorityType(s string) (PriorityType, error) {
+	for value, name := range priorityTypeNames {
+		if name == s {
+			return PriorityType{Value: value}, nil
+		}
+	}
+	return PriorityType{}, unknownEnumError("PriorityType", s)
+}
+
@@ -13,3 +13,31 @@ type QuotaManagementIndicator struct {
 	Value	asn.Enumerated 
 }
 
+var quotaManagementIndicatorNames = map[asn.Enumerated]string{
+	QuotaManagementIndicatorPresentOnlineCharging:	"onlineCharging",
+	QuotaManagementIndicatorPresentOfflineCharging:	"offlineCharging",
+	QuotaManagementIndicatorPresentQuotaManagementSuspended:	"quotaManagementSuspended",
+}
+
+func (v QuotaManagementIndicator) String() string {
+	if s, ok := quotaManagementIndicatorNames[v.Value]; ok {
+		return s
+	}
+	return unknownEnumString("QuotaManagementIndicator", int64(v.Value))
+}
+
+// IsValid reports whether the value is one of the named values.
+func (v QuotaManagementIndicator) IsValid() bool {
+	_, ok := quotaManagementIndicatorNames[v.Value]
+	return ok
+}
+
+func ParseQuotaManagementIndicator(s string) (QuotaManagementIndicator, error) {
+	for value, name := range quotaManagementIndicatorNames {
+		if name == s {
+			return QuotaManagementIndicator{Value: value}, nil
+		}
+	}
+	return QuotaManagementIndicator{}, unknownEnumError("QuotaManagementIndicator", s)
+}
+
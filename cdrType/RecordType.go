@@ -2,7 +2,207 @@ package cdrType
 
 // Need to import "gofree5gc/lib/aper" if it uses "aper"
 
+const (	/* Named Numbers */
+	RecordTypePresentMoCallRecord	int64 = 0
+	RecordTypePresentMtCallRecord	int64 = 1
+	RecordTypePresentRoamingRecord	int64 = 2
+	RecordTypePresentIncGatewayRecord	int64 = 3
+	RecordTypePresentOutGatewayRecord	int64 = 4
+	RecordTypePresentTransitCallRecord	int64 = 5
+	RecordTypePresentMoSMSRecord	int64 = 6
+	RecordTypePresentMtSMSRecord	int64 = 7
+	RecordTypePresentMoSMSIWRecord	int64 = 8
+	RecordTypePresentMtSMSGWRecord	int64 = 9
+	RecordTypePresentSsActionRecord	int64 = 10
+	RecordTypePresentHlrIntRecord	int64 = 11
+	RecordTypePresentLocUpdateHLRRecord	int64 = 12
+	RecordTypePresentLocUpdateVLRRecord	int64 = 13
+	RecordTypePresentCommonEquipRecord	int64 = 14
+	RecordTypePresentMoTraceRecord	int64 = 15
+	RecordTypePresentMtTraceRecord	int64 = 16
+	RecordTypePresentTermCAMELRecord	int64 = 17
+	RecordTypePresentSgsnPDPRecord	int64 = 18
+	RecordTypePresentGgsnPDPRecord	int64 = 19
+	RecordTypePresentSgsnMMRecord	int64 = 20
+	RecordTypePresentSgsnSMORecord	int64 = 21
+	RecordTypePresentSgsnSMTRecord	int64 = 22
+	RecordTypePresentMtLCSRecord	int64 = 23
+	RecordTypePresentMoLCSRecord	int64 = 24
+	RecordTypePresentNiLCSRecord	int64 = 25
+	RecordTypePresentSgsnMtLCSRecord	int64 = 26
+	RecordTypePresentSgsnMoLCSRecord	int64 = 27
+	RecordTypePresentSgsnNiLCSRecord	int64 = 28
+	RecordTypePresentMMO1SRecord	int64 = 30
+	RecordTypePresentMMO4FRqRecord	int64 = 31
+	RecordTypePresentMMO4FRsRecord	int64 = 32
+	RecordTypePresentMMO4DRecord	int64 = 33
+	RecordTypePresentMMO1DRecord	int64 = 34
+	RecordTypePresentMMO4RRecord	int64 = 35
+	RecordTypePresentMMO1RRecord	int64 = 36
+	RecordTypePresentMMOMDRecord	int64 = 37
+	RecordTypePresentMMR4FRecord	int64 = 38
+	RecordTypePresentMMR1NRqRecord	int64 = 39
+	RecordTypePresentMMR1NRsRecord	int64 = 40
+	RecordTypePresentMMR1RtRecord	int64 = 41
+	RecordTypePresentMMR1AFRecord	int64 = 42
+	RecordTypePresentMMR4DRqRecord	int64 = 43
+	RecordTypePresentMMR4DRsRecord	int64 = 44
+	RecordTypePresentMMR1RRRecord	int64 = 45
+	RecordTypePresentMMR4RRqRecord	int64 = 46
+	RecordTypePresentMMR4RRsRecord	int64 = 47
+	RecordTypePresentMMRMDRecord	int64 = 48
+	RecordTypePresentMMFRecord	int64 = 49
+	RecordTypePresentMMBx1SRecord	int64 = 50
+	RecordTypePresentMMBx1VRecord	int64 = 51
+	RecordTypePresentMMBx1URecord	int64 = 52
+	RecordTypePresentMMBx1DRecord	int64 = 53
+	RecordTypePresentMM7SRecord	int64 = 54
+	RecordTypePresentMM7DRqRecord	int64 = 55
+	RecordTypePresentMM7DRsRecord	int64 = 56
+	RecordTypePresentMM7CRecord	int64 = 57
+	RecordTypePresentMM7RRecord	int64 = 58
+	RecordTypePresentMM7DRRqRecord	int64 = 59
+	RecordTypePresentMM7DRRsRecord	int64 = 60
+	RecordTypePresentMM7RRqRecord	int64 = 61
+	RecordTypePresentMM7RRsRecord	int64 = 62
+	RecordTypePresentSCSCFRecord	int64 = 63
+	RecordTypePresentPCSCFRecord	int64 = 64
+	RecordTypePresentICSCFRecord	int64 = 65
+	RecordTypePresentMRFCRecord	int64 = 66
+	RecordTypePresentMGCFRecord	int64 = 67
+	RecordTypePresentBGCFRecord	int64 = 68
+	RecordTypePresentASRecord	int64 = 69
+	RecordTypePresentIBCFRecord	int64 = 82
+	RecordTypePresentLCSGMORecord	int64 = 71
+	RecordTypePresentLCSRGMTRecord	int64 = 72
+	RecordTypePresentLCSHGMTRecord	int64 = 73
+	RecordTypePresentLCSVGMTRecord	int64 = 74
+	RecordTypePresentLCSGNIRecord	int64 = 75
+	RecordTypePresentSgsnMBMSRecord	int64 = 76
+	RecordTypePresentGgsnMBMSRecord	int64 = 77
+	RecordTypePresentSUBBMSCRecord	int64 = 78
+	RecordTypePresentCONTENTBMSCRecord	int64 = 79
+	RecordTypePresentGwMBMSRecord	int64 = 86
+	RecordTypePresentPPFRecord	int64 = 80
+	RecordTypePresentCPFRecord	int64 = 81
+	RecordTypePresentMMTelRecord	int64 = 83
+	RecordTypePresentSGWRecord	int64 = 84
+	RecordTypePresentPGWRecord	int64 = 85
+	RecordTypePresentChargingFunctionRecord	int64 = 200
+)
+
 type RecordType struct {
 	Value	int64 
 }
 
+var recordTypeNames = map[int64]string{
+	RecordTypePresentMoCallRecord:	"moCallRecord",
+	RecordTypePresentMtCallRecord:	"mtCallRecord",
+	RecordTypePresentRoamingRecord:	"roamingRecord",
+	RecordTypePresentIncGatewayRecord:	"incGatewayRecord",
+	RecordTypePresentOutGatewayRecord:	"outGatewayRecord",
+	RecordTypePresentTransitCallRecord:	"transitCallRecord",
+	RecordTypePresentMoSMSRecord:	"moSMSRecord",
+	RecordTypePresentMtSMSRecord:	"mtSMSRecord",
+	RecordTypePresentMoSMSIWRecord:	"moSMSIWRecord",
+	RecordTypePresentMtSMSGWRecord:	"mtSMSGWRecord",
+	RecordTypePresentSsActionRecord:	"ssActionRecord",
+	RecordTypePresentHlrIntRecord:	"hlrIntRecord",
+	RecordTypePresentLocUpdateHLRRecord:	"locUpdateHLRRecord",
+	RecordTypePresentLocUpdateVLRRecord:	"locUpdateVLRRecord",
+	RecordTypePresentCommonEquipRecord:	"commonEquipRecord",
+	RecordTypePresentMoTraceRecord:	"moTraceRecord",
+	RecordTypePresentMtTraceRecord:	"mtTraceRecord",
+	RecordTypePresentTermCAMELRecord:	"termCAMELRecord",
+	RecordTypePresentSgsnPDPRecord:	"sgsnPDPRecord",
+	RecordTypePresentGgsnPDPRecord:	"ggsnPDPRecord",
+	RecordTypePresentSgsnMMRecord:	"sgsnMMRecord",
+	RecordTypePresentSgsnSMORecord:	"sgsnSMORecord",
+	RecordTypePresentSgsnSMTRecord:	"sgsnSMTRecord",
+	RecordTypePresentMtLCSRecord:	"mtLCSRecord",
+	RecordTypePresentMoLCSRecord:	"moLCSRecord",
+	RecordTypePresentNiLCSRecord:	"niLCSRecord",
+	RecordTypePresentSgsnMtLCSRecord:	"sgsnMtLCSRecord",
+	RecordTypePresentSgsnMoLCSRecord:	"sgsnMoLCSRecord",
+	RecordTypePresentSgsnNiLCSRecord:	"sgsnNiLCSRecord",
+	RecordTypePresentMMO1SRecord:	"mMO1SRecord",
+	RecordTypePresentMMO4FRqRecord:	"mMO4FRqRecord",
+	RecordTypePresentMMO4FRsRecord:	"mMO4FRsRecord",
+	RecordTypePresentMMO4DRecord:	"mMO4DRecord",
+	RecordTypePresentMMO1DRecord:	"mMO1DRecord",
+	RecordTypePresentMMO4RRecord:	"mMO4RRecord",
+	RecordTypePresentMMO1RRecord:	"mMO1RRecord",
+	RecordTypePresentMMOMDRecord:	"mMOMDRecord",
+	RecordTypePresentMMR4FRecord:	"mMR4FRecord",
+	RecordTypePresentMMR1NRqRecord:	"mMR1NRqRecord",
+	RecordTypePresentMMR1NRsRecord:	"mMR1NRsRecord",
+	RecordTypePresentMMR1RtRecord:	"mMR1RtRecord",
+	RecordTypePresentMMR1AFRecord:	"mMR1AFRecord",
+	RecordTypePresentMMR4DRqRecord:	"mMR4DRqRecord",
+	RecordTypePresentMMR4DRsRecord:	"mMR4DRsRecord",
+	RecordTypePresentMMR1RRRecord:	"mMR1RRRecord",
+	RecordTypePresentMMR4RRqRecord:	"mMR4RRqRecord",
+	RecordTypePresentMMR4RRsRecord:	"mMR4RRsRecord",
+	RecordTypePresentMMRMDRecord:	"mMRMDRecord",
+	RecordTypePresentMMFRecord:	"mMFRecord",
+	RecordTypePresentMMBx1SRecord:	"mMBx1SRecord",
+	RecordTypePresentMMBx1VRecord:	"mMBx1VRecord",
+	RecordTypePresentMMBx1URecord:	"mMBx1URecord",
+	RecordTypePresentMMBx1DRecord:	"mMBx1DRecord",
+	RecordTypePresentMM7SRecord:	"mM7SRecord",
+	RecordTypePresentMM7DRqRecord:	"mM7DRqRecord",
+	RecordTypePresentMM7DRsRecord:	"mM7DRsRecord",
+	RecordTypePresentMM7CRecord:	"mM7CRecord",
+	RecordTypePresentMM7RRecord:	"mM7RRecord",
+	RecordTypePresentMM7DRRqRecord:	"mM7DRRqRecord",
+	RecordTypePresentMM7DRRsRecord:	"mM7DRRsRecord",
+	RecordTypePresentMM7RRqRecord:	"mM7RRqRecord",
+	RecordTypePresentMM7RRsRecord:	"mM7RRsRecord",
+	RecordTypePresentSCSCFRecord:	"s-CSCFRecord",
+	RecordTypePresentPCSCFRecord:	"p-CSCFRecord",
+	RecordTypePresentICSCFRecord:	"i-CSCFRecord",
+	RecordTypePresentMRFCRecord:	"mRFCRecord",
+	RecordTypePresentMGCFRecord:	"mGCFRecord",
+	RecordTypePresentBGCFRecord:	"bGCFRecord",
+	RecordTypePresentASRecord:	"aSRecord",
+	RecordTypePresentIBCFRecord:	"iBCFRecord",
+	RecordTypePresentLCSGMORecord:	"lCSGMORecord",
+	RecordTypePresentLCSRGMTRecord:	"lCSRGMTRecord",
+	RecordTypePresentLCSHGMTRecord:	"lCSHGMTRecord",
+	RecordTypePresentLCSVGMTRecord:	"lCSVGMTRecord",
+	RecordTypePresentLCSGNIRecord:	"lCSGNIRecord",
+	RecordTypePresentSgsnMBMSRecord:	"sgsnMBMSRecord",
+	RecordTypePresentGgsnMBMSRecord:	"ggsnMBMSRecord",
+	RecordTypePresentSUBBMSCRecord:	"sUBBMSCRecord",
+	RecordTypePresentCONTENTBMSCRecord:	"cONTENTBMSCRecord",
+	RecordTypePresentGwMBMSRecord:	"gwMBMSRecord",
+	RecordTypePresentPPFRecord:	"pPFRecord",
+	RecordTypePresentCPFRecord:	"cPFRecord",
+	RecordTypePresentMMTelRecord:	"mMTelRecord",
+	RecordTypePresentSGWRecord:	"sGWRecord",
+	RecordTypePresentPGWRecord:	"pGWRecord",
+	RecordTypePresentChargingFunctionRecord:	"chargingFunctionRecord",
+}
+
+func (v RecordType) String() string {
+	if s, ok := recordTypeNames[v.Value]; ok {
+		return s
+	}
+	return unknownEnumString("RecordType", int64(v.Value))
+}
+
+// IsValid reports whether the value is one of the named values.
+func (v RecordType) IsValid() bool {
+	_, ok := recordTypeNames[v.Value]
+	return ok
+}
+
+func ParseRecordType(s string) (RecordType, error) {
+	for value, name := range recordTypeNames {
+		if name == s {
+			return RecordType{Value: value}, nil
+		}
+	}
+	return RecordType{}, unknownEnumError("RecordType", s)
+}
+
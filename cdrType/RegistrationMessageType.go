@@ -15,3 +15,33 @@ type RegistrationMessageType struct {
 	Value	asn.Enumerated 
 }
 
+var registrationMessageTypeNames = map[asn.Enumerated]string{
+	RegistrationMessageTypePresentInitial:	"initial",
+	RegistrationMessageTypePresentMobility:	"mobility",
+	RegistrationMessageTypePresentPeriodic:	"periodic",
+	RegistrationMessageTypePresentEmergency:	"emergency",
+	RegistrationMessageTypePresentDeregistration:	"deregistration",
+}
+
+func (v RegistrationMessageType) String() string {
+	if s, ok := registrationMessageTypeNames[v.Value]; ok {
+		return s
+	}
+	return unknownEnumString("RegistrationMessageType", int64(v.Value))
+}
+
+// IsValid reports whether the value is one of the named values.
+func (v RegistrationMessageType) IsValid() bool {
+	_, ok := registrationMessageTypeNames[v.Value]
+	return ok
+}
+
+func ParseRegistrationMessageType(s string) (RegistrationMessageType, error) {
+	for value, name := range registrationMessageTypeNames {
+		if name == s {
+			return RegistrationMessageType{Value: value}, nil
+		}
+	}
+	return RegistrationMessageType{}, unknownEnumError("RegistrationMessageType", s)
+}
+
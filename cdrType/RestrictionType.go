@@ -12,3 +12,30 @@ type RestrictionType struct {
 	Value	asn.Enumerated 
 }
 
+var restrictionTypeNames = map[asn.Enumerated]string{
+	RestrictionTypePresentAllowedAreas:	"allowedAreas",
+	RestrictionTypePresentNotAllowedAreas:	"notAllowedAreas",
+}
+
+func (v RestrictionType) String() string {
+	if s, ok := restrictionTypeNames[v.Value]; ok {
+		return s
+	}
+	return unknownEnumString("RestrictionType", int64(v.Value))
+}
+
+// IsValid reports whether the value is one of the named values.
+func (v RestrictionType) IsValid() bool {
+	_, ok := restrictionTypeNames[v.Value]
+	return ok
+}
+
+func ParseRestrictionType(s string) (RestrictionType, error) {
+	for value, name := range restrictionTypeNames {
+		if name == s {
+			return RestrictionType{Value: value}, nil
+		}
+	}
+	return RestrictionType{}, unknownEnumError("RestrictionType", s)
+}
+
@@ -12,3 +12,30 @@ type RoamerInOut struct {
 	Value	asn.Enumerated 
 }
 
+var roamerInOutNames = map[asn.Enumerated]string{
+	RoamerInOutPresentRoamerInBound:	"roamerInBound",
+	RoamerInOutPresentRoamerOutBound:	"roamerOutBound",
+}
+
+func (v RoamerInOut) String() string {
+	if s, ok := roamerInOutNames[v.Value]; ok {
+		return s
+	}
+	return unknownEnumString("RoamerInOut", int64(v.Value))
+}
+
+// IsValid reports whether the value is one of the named values.
+func (v RoamerInOut) IsValid() bool {
+	_, ok := roamerInOutNames[v.Value]
+	return ok
+}
+
+func ParseRoamerInOut(s string) (RoamerInOut, error) {
+	for value, name := range roamerInOutNames {
+		if name == s {
+			return RoamerInOut{Value: value}, nil
+		}
+	}
+	return RoamerInOut{}, unknownEnumError("RoamerInOut", s)
+}
+
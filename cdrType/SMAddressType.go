@@ -20,3 +20,38 @@ type SMAddressType struct {
 	Value	asn.Enumerated 
 }
 
+var smAddressTypeNames = map[asn.Enumerated]string{
+	SMAddressTypePresentEmailAddress:	"emailAddress",
+	SMAddressTypePresentMSISDN:	"mSISDN",
+	SMAddressTypePresentIPv4Address:	"iPv4Address",
+	SMAddressTypePresentIPv6Address:	"iPv6Address",
+	SMAddressTypePresentNumericShortCode:	"numericShortCode",
+	SMAddressTypePresentAlphanumericShortCode:	"alphanumericShortCode",
+	SMAddressTypePresentOther:	"other",
+	SMAddressTypePresentIMSI:	"iMSI",
+	SMAddressTypePresentNAI:	"nAI",
+	SMAddressTypePresentExternalId:	"externalId",
+}
+
+func (v SMAddressType) String() string {
+	if s, ok := smAddressTypeNames[v.Value]; ok {
+		return s
+	}
+	return unknownEnumString("SMAddressType", int64(v.Value))
+}
+
+// IsValid reports whether the value is one of the named values.
+func (v SMAddressType) IsValid() bool {
+	_, ok := smAddressTypeNames[v.Value]
+	return ok
+}
+
+func ParseSMAddressType(s string) (SMAddressType, error) {
+	for value, name := range smAddressTypeNames {
+		if name == s {
+			return SMAddressType{Value: value}, nil
+		}
+	}
+	return SMAddressType{}, unknownEnumError("SMAddressType", s)
+}
+
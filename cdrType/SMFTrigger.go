@@ -2,7 +2,117 @@ package cdrType
 
 // Need to import "gofree5gc/lib/aper" if it uses "aper"
 
+const (	/* Named Numbers */
+	SMFTriggerPresentQuotaThreshold	int64 = 1
+	SMFTriggerPresentQuotaHoldingTime	int64 = 2
+	SMFTriggerPresentFinal	int64 = 3
+	SMFTriggerPresentQuotaExhausted	int64 = 4
+	SMFTriggerPresentValidityTime	int64 = 5
+	SMFTriggerPresentOtherQuotaType	int64 = 6
+	SMFTriggerPresentForcedReauthorisation	int64 = 7
+	SMFTriggerPresentUnusedQuotaTimer	int64 = 8
+	SMFTriggerPresentUnitCountInactivityTimer	int64 = 9
+	SMFTriggerPresentAbnormalRelease	int64 = 10
+	SMFTriggerPresentQoSChange	int64 = 11
+	SMFTriggerPresentVolumeLimit	int64 = 12
+	SMFTriggerPresentTimeLimit	int64 = 13
+	SMFTriggerPresentEventLimit	int64 = 14
+	SMFTriggerPresentPLMNChange	int64 = 15
+	SMFTriggerPresentUserLocationChange	int64 = 16
+	SMFTriggerPresentRATChange	int64 = 17
+	SMFTriggerPresentSessionAMBRChange	int64 = 18
+	SMFTriggerPresentUETimeZoneChange	int64 = 19
+	SMFTriggerPresentTariffTimeChange	int64 = 20
+	SMFTriggerPresentMaxNumberOfChangesInChargingConditions	int64 = 21
+	SMFTriggerPresentManagementIntervention	int64 = 22
+	SMFTriggerPresentChangeOfUEPresenceInPresenceReportingArea	int64 = 23
+	SMFTriggerPresentChangeOf3GPPPSDataOffStatus	int64 = 24
+	SMFTriggerPresentServingNodeChange	int64 = 25
+	SMFTriggerPresentRemovalOfUPF	int64 = 26
+	SMFTriggerPresentAdditionOfUPF	int64 = 27
+	SMFTriggerPresentInsertionOfISMF	int64 = 28
+	SMFTriggerPresentRemovalOfISMF	int64 = 29
+	SMFTriggerPresentChangeOfISMF	int64 = 30
+	SMFTriggerPresentStartOfServiceDataFlow	int64 = 31
+	SMFTriggerPresentECGIChange	int64 = 32
+	SMFTriggerPresentTAIChange	int64 = 33
+	SMFTriggerPresentHandoverCancel	int64 = 34
+	SMFTriggerPresentHandoverStart	int64 = 35
+	SMFTriggerPresentHandoverComplete	int64 = 36
+	SMFTriggerPresentGFBRGuaranteedStatusChange	int64 = 37
+	SMFTriggerPresentAdditionOfAccess	int64 = 38
+	SMFTriggerPresentRemovalOfAccess	int64 = 39
+	SMFTriggerPresentStartOfSDFAdditionalAccess	int64 = 40
+	SMFTriggerPresentRedundantTransmissionChange	int64 = 41
+)
+
 type SMFTrigger struct {
 	Value	int64 
 }
 
+var smfTriggerNames = map[int64]string{
+	SMFTriggerPresentQuotaThreshold:	"quotaThreshold",
+	SMFTriggerPresentQuotaHoldingTime:	"quotaHoldingTime",
+	SMFTriggerPresentFinal:	"final",
+	SMFTriggerPresentQuotaExhausted:	"quotaExhausted",
+	SMFTriggerPresentValidityTime:	"validityTime",
+	SMFTriggerPresentOtherQuotaType:	"otherQuotaType",
+	SMFTriggerPresentForcedReauthorisation:	"forcedReauthorisation",
+	SMFTriggerPresentUnusedQuotaTimer:	"unusedQuotaTimer",
+	SMFTriggerPresentUnitCountInactivityTimer:	"unitCountInactivityTimer",
+	SMFTriggerPresentAbnormalRelease:	"abnormalRelease",
+	SMFTriggerPresentQoSChange:	"qoSChange",
+	SMFTriggerPresentVolumeLimit:	"volumeLimit",
+	SMFTriggerPresentTimeLimit:	"timeLimit",
+	SMFTriggerPresentEventLimit:	"eventLimit",
+	SMFTriggerPresentPLMNChange:	"pLMNChange",
+	SMFTriggerPresentUserLocationChange:	"userLocationChange",
+	SMFTriggerPresentRATChange:	"rATChange",
+	SMFTriggerPresentSessionAMBRChange:	"sessionAMBRChange",
+	SMFTriggerPresentUETimeZoneChange:	"uETimeZoneChange",
+	SMFTriggerPresentTariffTimeChange:	"tariffTimeChange",
+	SMFTriggerPresentMaxNumberOfChangesInChargingConditions:	"maxNumberOfChangesInChargingConditions",
+	SMFTriggerPresentManagementIntervention:	"managementIntervention",
+	SMFTriggerPresentChangeOfUEPresenceInPresenceReportingArea:	"changeOfUEPresenceInPresenceReportingArea",
+	SMFTriggerPresentChangeOf3GPPPSDataOffStatus:	"changeOf3GPPPSDataOffStatus",
+	SMFTriggerPresentServingNodeChange:	"servingNodeChange",
+	SMFTriggerPresentRemovalOfUPF:	"removalOfUPF",
+	SMFTriggerPresentAdditionOfUPF:	"additionOfUPF",
+	SMFTriggerPresentInsertionOfISMF:	"insertionOfISMF",
+	SMFTriggerPresentRemovalOfISMF:	"removalOfISMF",
+	SMFTriggerPresentChangeOfISMF:	"changeOfISMF",
+	SMFTriggerPresentStartOfServiceDataFlow:	"startOfServiceDataFlow",
+	SMFTriggerPresentECGIChange:	"eCGIChange",
+	SMFTriggerPresentTAIChange:	"tAIChange",
+	SMFTriggerPresentHandoverCancel:	"handoverCancel",
+	SMFTriggerPresentHandoverStart:	"handoverStart",
+	SMFTriggerPresentHandoverComplete:	"handoverComplete",
+	SMFTriggerPresentGFBRGuaranteedStatusChange:	"gFBRGuaranteedStatusChange",
+	SMFTriggerPresentAdditionOfAccess:	"additionOfAccess",
+	SMFTriggerPresentRemovalOfAccess:	"removalOfAccess",
+	SMFTriggerPresentStartOfSDFAdditionalAccess:	"startOfSDFAdditionalAccess",
+	SMFTriggerPresentRedundantTransmissionChange:	"redundantTransmissionChange",
+}
+
+func (v SMFTrigger) String() string {
+	if s, ok := smfTriggerNames[v.Value]; ok {
+		return s
+	}
+	return unknownEnumString("SMFTrigger", int64(v.Value))
+}
+
+// IsValid reports whether the value is one of the named values.
+func (v SMFTrigger) IsValid() bool {
+	_, ok := smfTriggerNames[v.Value]
+	return ok
+}
+
+func ParseSMFTrigger(s string) (SMFTrigger, error) {
+	for value, name := range smfTriggerNames {
+		if name == s {
+			return SMFTrigger{Value: value}, nil
+		}
+	}
+	return SMFTrigger{}, unknownEnumError("SMFTrigger", s)
+}
+
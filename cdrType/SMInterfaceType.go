@@ -16,3 +16,34 @@ type SMInterfaceType struct {
 	Value	asn.Enumerated 
 }
 
+var smInterfaceTypeNames = map[asn.Enumerated]string{
+	SMInterfaceTypePresentUnkown:	"unkown",
+	SMInterfaceTypePresentMobileOriginating:	"mobileOriginating",
+	SMInterfaceTypePresentMobileTerminating:	"mobileTerminating",
+	SMInterfaceTypePresentApplicationOriginating:	"applicationOriginating",
+	SMInterfaceTypePresentApplicationTerminating:	"applicationTerminating",
+	SMInterfaceTypePresentDeviceTrigger:	"deviceTrigger",
+}
+
+func (v SMInterfaceType) String() string {
+	if s, ok := smInterfaceTypeNames[v.Value]; ok {
+		return s
+	}
+	return unknownEnumString("SMInterfaceType", int64(v.Value))
+}
+
+// IsValid reports whether the value is one of the named values.
+func (v SMInterfaceType) IsValid() bool {
+	_, ok := smInterfaceTypeNames[v.Value]
+	return ok
+}
+
+func ParseSMInterfaceType(s string) (SMInterfaceType, error) {
+	for value, name := range smInterfaceTypeNames {
+		if name == s {
+			return SMInterfaceType{Value: value}, nil
+		}
+	}
+	return SMInterfaceType{}, unknownEnumError("SMInterfaceType", s)
+}
+
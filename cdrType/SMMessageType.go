@@ -16,3 +16,34 @@ type SMMessageType struct {
 	Value	asn.Enumerated 
 }
 
+var smMessageTypeNames = map[asn.Enumerated]string{
+	SMMessageTypePresentSubmission:	"submission",
+	SMMessageTypePresentDeliveryReport:	"deliveryReport",
+	SMMessageTypePresentSMServiceRequest:	"sMServiceRequest",
+	SMMessageTypePresentDelivery:	"delivery",
+	SMMessageTypePresentT4DeviceTrigger:	"t4DeviceTrigger",
+	SMMessageTypePresentSMDeviceTrigger:	"sMDeviceTrigger",
+}
+
+func (v SMMessageType) String() string {
+	if s, ok := smMessageTypeNames[v.Value]; ok {
+		return s
+	}
+	return unknownEnumString("SMMessageType", int64(v.Value))
+}
+
+// IsValid reports whether the value is one of the named values.
+func (v SMMessageType) IsValid() bool {
+	_, ok := smMessageTypeNames[v.Value]
+	return ok
+}
+
+func ParseSMMessageType(s string) (SMMessageType, error) {
+	for value, name := range smMessageTypeNames {
+		if name == s {
+			return SMMessageType{Value: value}, nil
+		}
+	}
+	return SMMessageType{}, unknownEnumError("SMMessageType", s)
+}
+
@@ -12,3 +12,30 @@ type SMReplyPathRequested struct {
 	Value	asn.Enumerated 
 }
 
+var smReplyPathRequestedNames = map[asn.Enumerated]string{
+	SMReplyPathRequestedPresentNoReplyPathSet:	"noReplyPathSet",
+	SMReplyPathRequestedPresentReplyPathSet:	"replyPathSet",
+}
+
+func (v SMReplyPathRequested) String() string {
+	if s, ok := smReplyPathRequestedNames[v.Value]; ok {
+		return s
+	}
+	return unknownEnumString("SMReplyPathRequested", int64(v.Value))
+}
+
+// IsValid reports whether the value is one of the named values.
+func (v SMReplyPathRequested) IsValid() bool {
+	_, ok := smReplyPathRequestedNames[v.Value]
+	return ok
+}
+
+func ParseSMReplyPathRequested(s string) (SMReplyPathRequested, error) {
+	for value, name := range smReplyPathRequestedNames {
+		if name == s {
+			return SMReplyPathRequested{Value: value}, nil
+		}
+	}
+	return SMReplyPathRequested{}, unknownEnumError("SMReplyPathRequested", s)
+}
+
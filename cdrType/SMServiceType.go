@@ -2,7 +2,57 @@ package cdrType
 
 // Need to import "gofree5gc/lib/aper" if it uses "aper"
 
+const (	/* Named Numbers */
+	SMServiceTypePresentVAS4SMSShortMessageContentProcessing	int64 = 0
+	SMServiceTypePresentVAS4SMSShortMessageForwarding	int64 = 1
+	SMServiceTypePresentVAS4SMSShortMessageForwardingMultipleSubscriptions	int64 = 2
+	SMServiceTypePresentVAS4SMSShortMessageFiltering	int64 = 3
+	SMServiceTypePresentVAS4SMSShortMessageReceipt	int64 = 4
+	SMServiceTypePresentVAS4SMSShortMessageNetworkStorage	int64 = 5
+	SMServiceTypePresentVAS4SMSShortMessageToMultipleDestinations	int64 = 6
+	SMServiceTypePresentVAS4SMSShortMessageVirtualPrivateNetwork	int64 = 7
+	SMServiceTypePresentVAS4SMSShortMessageAutoReply	int64 = 8
+	SMServiceTypePresentVAS4SMSShortMessagePersonalSignature	int64 = 9
+	SMServiceTypePresentVAS4SMSShortMessageDeferredDelivery	int64 = 10
+)
+
 type SMServiceType struct {
 	Value	int64 
 }
 
+var smServiceTypeNames = map[int64]string{
+	SMServiceTypePresentVAS4SMSShortMessageContentProcessing:	"vAS4SMSShortMessageContentProcessing",
+	SMServiceTypePresentVAS4SMSShortMessageForwarding:	"vAS4SMSShortMessageForwarding",
+	SMServiceTypePresentVAS4SMSShortMessageForwardingMultipleSubscriptions:	"vAS4SMSShortMessageForwardingMultipleSubscriptions",
+	SMServiceTypePresentVAS4SMSShortMessageFiltering:	"vAS4SMSShortMessageFiltering",
+	SMServiceTypePresentVAS4SMSShortMessageReceipt:	"vAS4SMSShortMessageReceipt",
+	SMServiceTypePresentVAS4SMSShortMessageNetworkStorage:	"vAS4SMSShortMessageNetworkStorage",
+	SMServiceTypePresentVAS4SMSShortMessageToMultipleDestinations:	"vAS4SMSShortMessageToMultipleDestinations",
+	SMServiceTypePresentVAS4SMSShortMessageVirtualPrivateNetwork:	"vAS4SMSShortMessageVirtualPrivateNetwork",
+	SMServiceTypePresentVAS4SMSShortMessageAutoReply:	"vAS4SMSShortMessageAutoReply",
+	SMServiceTypePresentVAS4SMSShortMessagePersonalSignature:	"vAS4SMSShortMessagePersonalSignature",
+	SMServiceTypePresentVAS4SMSShortMessageDeferredDelivery:	"vAS4SMSShortMessageDeferredDelivery",
+}
+
+func (v SMServiceType) String() string {
+	if s, ok := smServiceTypeNames[v.Value]; ok {
+		return s
+	}
+	return unknownEnumString("SMServiceType", int64(v.Value))
+}
+
+// IsValid reports whether the value is one of the named values.
+func (v SMServiceType) IsValid() bool {
+	_, ok := smServiceTypeNames[v.Value]
+	return ok
+}
+
+func ParseSMServiceType(s string) (SMServiceType, error) {
+	for value, name := range smServiceTypeNames {
+		if name == s {
+			return SMServiceType{Value: value}, nil
+		}
+	}
+	return SMServiceType{}, unknownEnumError("SMServiceType", s)
+}
+
@@ -12,3 +12,30 @@ type SMdeliveryReportRequested struct {
 	Value	asn.Enumerated 
 }
 
+var sMdeliveryReportRequestedNames = map[asn.Enumerated]string{
+	SMdeliveryReportRequestedPresentYes:	"yes",
+	SMdeliveryReportRequestedPresentNo:	"no",
+}
+
+func (v SMdeliveryReportRequested) String() string {
+	if s, ok := sMdeliveryReportRequestedNames[v.Value]; ok {
+		return s
+	}
+	return unknownEnumString("SMdeliveryReportRequested", int64(v.Value))
+}
+
+// IsValid reports whether the value is one of the named values.
+func (v SMdeliveryReportRequested) IsValid() bool {
+	_, ok := sMdeliveryReportRequestedNames[v.Value]
+	return ok
+}
+
+func ParseSMdeliveryReportRequested(s string) (SMdeliveryReportRequested, error) {
+	for value, name := range sMdeliveryReportRequestedNames {
+		if name == s {
+			return SMdeliveryReportRequested{Value: value}, nil
+		}
+	}
+	return SMdeliveryReportRequested{}, unknownEnumError("SMdeliveryReportRequested", s)
+}
+
@@ -2,7 +2,41 @@ package cdrType
 
 // Need to import "gofree5gc/lib/aper" if it uses "aper"
 
+const (	/* Named Numbers */
+	SSCModePresentSSCMode1	int64 = 1
+	SSCModePresentSSCMode2	int64 = 2
+	SSCModePresentSSCMode3	int64 = 3
+)
+
 type SSCMode struct {
 	Value	int64 
 }
 
+var sscModeNames = map[int64]string{
+	SSCModePresentSSCMode1:	"sSCMode1",
+	SSCModePresentSSCMode2:	"sSCMode2",
+	SSCModePresentSSCMode3:	"sSCMode3",
+}
+
+func (v SSCMode) String() string {
+	if s, ok := sscModeNames[v.Value]; ok {
+		return s
+	}
+	return unknownEnumString("SSCMode", int64(v.Value))
+}
+
+// IsValid reports whether the value is one of the named values.
+func (v SSCMode) IsValid() bool {
+	_, ok := sscModeNames[v.Value]
+	return ok
+}
+
+func ParseSSCMode(s string) (SSCMode, error) {
+	for value, name := range sscModeNames {
+		if name == s {
+			return SSCMode{Value: value}, nil
+		}
+	}
+	return SSCMode{}, unknownEnumError("SSCMode", s)
+}
+
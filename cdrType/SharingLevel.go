@@ -12,3 +12,30 @@ type SharingLevel struct {
 	Value	asn.Enumerated 
 }
 
+var sharingLevelNames = map[asn.Enumerated]string{
+	SharingLevelPresentSHARED:	"sHARED",
+	SharingLevelPresentNONSHARED:	"nONSHARED",
+}
+
+func (v SharingLevel) String() string {
+	if s, ok := sharingLevelNames[v.Value]; ok {
+		return s
+	}
+	return unknownEnumString("SharingLevel", int64(v.Value))
+}
+
+// IsValid reports whether the value is one of the named values.
+func (v SharingLevel) IsValid() bool {
+	_, ok := sharingLevelNames[v.Value]
+	return ok
+}
+
+func ParseSharingLevel(s string) (SharingLevel, error) {
+	for value, name := range sharingLevelNames {
+		if name == s {
+			return SharingLevel{Value: value}, nil
+		}
+	}
+	return SharingLevel{}, unknownEnumError("SharingLevel", s)
+}
+
@@ -12,3 +12,30 @@ type SmsIndication struct {
 	Value	asn.Enumerated 
 }
 
+var smsIndicationNames = map[asn.Enumerated]string{
+	SmsIndicationPresentSMSSupported:	"sMSSupported",
+	SmsIndicationPresentSMSNotSupported:	"sMSNotSupported",
+}
+
+func (v SmsIndication) String() string {
+	if s, ok := smsIndicationNames[v.Value]; ok {
+		return s
+	}
+	return unknownEnumString("SmsIndication", int64(v.Value))
+}
+
+// IsValid reports whether the value is one of the named values.
+func (v SmsIndication) IsValid() bool {
+	_, ok := smsIndicationNames[v.Value]
+	return ok
+}
+
+func ParseSmsIndication(s string) (SmsIndication, error) {
+	for value, name := range smsIndicationNames {
+		if name == s {
+			return SmsIndication{Value: value}, nil
+		}
+	}
+	return SmsIndication{}, unknownEnumError("SmsIndication", s)
+}
+
@@ -14,3 +14,32 @@ type SteerModeValue struct {
 	Value	asn.Enumerated 
 }
 
+var steerModeValueNames = map[asn.Enumerated]string{
+	SteerModeValuePresentActiveStandby:	"activeStandby",
+	SteerModeValuePresentLoadBalancing:	"loadBalancing",
+	SteerModeValuePresentSmallestDelay:	"smallestDelay",
+	SteerModeValuePresentPriorityBased:	"priorityBased",
+}
+
+func (v SteerModeValue) String() string {
+	if s, ok := steerModeValueNames[v.Value]; ok {
+		return s
+	}
+	return unknownEnumString("SteerModeValue", int64(v.Value))
+}
+
+// IsValid reports whether the value is one of the named values.
+func (v SteerModeValue) IsValid() bool {
+	_, ok := steerModeValueNames[v.Value]
+	return ok
+}
+
+func ParseSteerModeValue(s string) (SteerModeValue, error) {
+	for value, name := range steerModeValueNames {
+		if name == s {
+			return SteerModeValue{Value: value}, nil
+		}
+	}
+	return SteerModeValue{}, unknownEnumError("SteerModeValue", s)
+}
+
@@ -14,3 +14,32 @@ type SubscriberEquipmentType struct {
 	Value	asn.Enumerated 
 }
 
+var subscriberEquipmentTypeNames = map[asn.Enumerated]string{
+	SubscriberEquipmentTypePresentIMEISV:	"iMEISV",
+	SubscriberEquipmentTypePresentMAC:	"mAC",
+	SubscriberEquipmentTypePresentEUI64:	"eUI64",
+	SubscriberEquipmentTypePresentModifiedEUI64:	"modifiedEUI64",
+}
+
+func (v SubscriberEquipmentType) String() string {
+	if s, ok := subscriberEquipmentTypeNames[v.Value]; ok {
+		return s
+	}
+	return unknownEnumString("SubscriberEquipmentType", int64(v.Value))
+}
+
+// IsValid reports whether the value is one of the named values.
+func (v SubscriberEquipmentType) IsValid() bool {
+	_, ok := subscriberEquipmentTypeNames[v.Value]
+	return ok
+}
+
+func ParseSubscriberEquipmentType(s string) (SubscriberEquipmentType, error) {
+	for value, name := range subscriberEquipmentTypeNames {
+		if name == s {
+			return SubscriberEquipmentType{Value: value}, nil
+		}
+	}
+	return SubscriberEquipmentType{}, unknownEnumError("SubscriberEquipmentType", s)
+}
+
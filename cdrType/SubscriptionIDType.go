@@ -15,3 +15,33 @@ type SubscriptionIDType struct {
 	Value	asn.Enumerated 
 }
 
+var subscriptionIDTypeNames = map[asn.Enumerated]string{
+	SubscriptionIDTypePresentENDUSERE164:	"eNDUSERE164",
+	SubscriptionIDTypePresentENDUSERIMSI:	"eNDUSERIMSI",
+	SubscriptionIDTypePresentENDUSERSIPURI:	"eNDUSERSIPURI",
+	SubscriptionIDTypePresentENDUSERNAI:	"eNDUSERNAI",
+	SubscriptionIDTypePresentENDUSERPRIVATE:	"eNDUSERPRIVATE",
+}
+
+func (v SubscriptionIDType) String() string {
+	if s, ok := subscriptionIDTypeNames[v.Value]; ok {
+		return s
+	}
+	return unknownEnumString("SubscriptionIDType", int64(v.Value))
+}
+
+// IsValid reports whether the value is one of the named values.
+func (v SubscriptionIDType) IsValid() bool {
+	_, ok := subscriptionIDTypeNames[v.Value]
+	return ok
+}
+
+func ParseSubscriptionIDType(s string) (SubscriptionIDType, error) {
+	for value, name := range subscriptionIDTypeNames {
+		if name == s {
+			return SubscriptionIDType{Value: value}, nil
+		}
+	}
+	return SubscriptionIDType{}, unknownEnumError("SubscriptionIDType", s)
+}
+
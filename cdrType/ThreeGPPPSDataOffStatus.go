@@ -12,3 +12,30 @@ type ThreeGPPPSDataOffStatus struct {
 	Value	asn.Enumerated 
 }
 
+var threeGPPPSDataOffStatusNames = map[asn.Enumerated]string{
+	ThreeGPPPSDataOffStatusPresentActive:	"active",
+	ThreeGPPPSDataOffStatusPresentInactive:	"inactive",
+}
+
+func (v ThreeGPPPSDataOffStatus) String() string {
+	if s, ok := threeGPPPSDataOffStatusNames[v.Value]; ok {
+		return s
+	}
+	return unknownEnumString("ThreeGPPPSDataOffStatus", int64(v.Value))
+}
+
+// IsValid reports whether the value is one of the named values.
+func (v ThreeGPPPSDataOffStatus) IsValid() bool {
+	_, ok := threeGPPPSDataOffStatusNames[v.Value]
+	return ok
+}
+
+func ParseThreeGPPPSDataOffStatus(s string) (ThreeGPPPSDataOffStatus, error) {
+	for value, name := range threeGPPPSDataOffStatusNames {
+		if name == s {
+			return ThreeGPPPSDataOffStatus{Value: value}, nil
+		}
+	}
+	return ThreeGPPPSDataOffStatus{}, unknownEnumError("ThreeGPPPSDataOffStatus", s)
+}
+
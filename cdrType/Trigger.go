@@ -12,3 +12,26 @@ type Trigger struct {
 	SMFTrigger	*SMFTrigger `ber:"tagNum:0"`
 }
 
+func NewTriggerSMFTrigger(v SMFTrigger) Trigger {
+	return Trigger{Present: TriggerPresentSMFTrigger, SMFTrigger: &v}
+}
+
+// Value returns the alternative selected by Present, nil if none is or
+// the selected one is not set.
+func (c Trigger) Value() interface{} {
+	switch c.Present {
+	case TriggerPresentSMFTrigger:
+		if c.SMFTrigger != nil {
+			return c.SMFTrigger
+		}
+	}
+	return nil
+}
+
+// Validate checks that exactly the alternative selected by Present is set.
+func (c Trigger) Validate() error {
+	return validateChoice("Trigger", c.Present, []choiceAlternative{
+		{"SMFTrigger", c.SMFTrigger != nil},
+	})
+}
+
@@ -12,3 +12,30 @@ type TriggerCategory struct {
 	Value	asn.Enumerated 
 }
 
+var triggerCategoryNames = map[asn.Enumerated]string{
+	TriggerCategoryPresentImmediateReport:	"immediateReport",
+	TriggerCategoryPresentDeferredReport:	"deferredReport",
+}
+
+func (v TriggerCategory) String() string {
+	if s, ok := triggerCategoryNames[v.Value]; ok {
+		return s
+	}
+	return unknownEnumString("TriggerCategory", int64(v.Value))
+}
+
+// IsValid reports whether the value is one of the named values.
+func (v TriggerCategory) IsValid() bool {
+	_, ok := triggerCategoryNames[v.Value]
+	return ok
+}
+
+func ParseTriggerCategory(s string) (TriggerCategory, error) {
+	for value, name := range triggerCategoryNames {
+		if name == s {
+			return TriggerCategory{Value: value}, nil
+		}
+	}
+	return TriggerCategory{}, unknownEnumError("TriggerCategory", s)
+}
+
@@ -18,3 +18,36 @@ type UnauthorizedLCSClientDiagnostic struct {
 	Value	asn.Enumerated 
 }
 
+var unauthorizedLCSClientDiagnosticNames = map[asn.Enumerated]string{
+	UnauthorizedLCSClientDiagnosticPresentNoAdditionalInformation:	"noAdditionalInformation",
+	UnauthorizedLCSClientDiagnosticPresentClientNotInMSPrivacyExceptionList:	"clientNotInMSPrivacyExceptionList",
+	UnauthorizedLCSClientDiagnosticPresentCallToClientNotSetup:	"callToClientNotSetup",
+	UnauthorizedLCSClientDiagnosticPresentPrivacyOverrideNotApplicable:	"privacyOverrideNotApplicable",
+	UnauthorizedLCSClientDiagnosticPresentDisallowedByLocalRegulatoryRequirements:	"disallowedByLocalRegulatoryRequirements",
+	UnauthorizedLCSClientDiagnosticPresentUnauthorizedPrivacyClass:	"unauthorizedPrivacyClass",
+	UnauthorizedLCSClientDiagnosticPresentUnauthorizedCallSessionUnrelatedExternalClient:	"unauthorizedCallSessionUnrelatedExternalClient",
+	UnauthorizedLCSClientDiagnosticPresentUnauthorizedCallSessionRelatedExternalClient:	"unauthorizedCallSessionRelatedExternalClient",
+}
+
+func (v UnauthorizedLCSClientDiagnostic) String() string {
+	if s, ok := unauthorizedLCSClientDiagnosticNames[v.Value]; ok {
+		return s
+	}
+	return unknownEnumString("UnauthorizedLCSClientDiagnostic", int64(v.Value))
+}
+
+// IsValid reports whether the value is one of the named values.
+func (v UnauthorizedLCSClientDiagnostic) IsValid() bool {
+	_, ok := unauthorizedLCSClientDiagnosticNames[v.Value]
+	return ok
+}
+
+func ParseUnauthorizedLCSClientDiagnostic(s string) (UnauthorizedLCSClientDiagnostic, error) {
+	for value, name := range unauthorizedLCSClientDiagnosticNames {
+		if name == s {
+			return UnauthorizedLCSClientDiagnostic{Value: value}, nil
+		}
+	}
+	return UnauthorizedLCSClientDiagnostic{}, unknownEnumError("UnauthorizedLCSClientDiagnostic", s)
+}
+
@@ -12,3 +12,30 @@ type V2XCommunicationModeIndicator struct {
 	Value	asn.Enumerated 
 }
 
+var v2XCommunicationModeIndicatorNames = map[asn.Enumerated]string{
+	V2XCommunicationModeIndicatorPresentV2XComSupported:	"v2XComSupported",
+	V2XCommunicationModeIndicatorPresentV2XComNotSupported:	"v2XComNotSupported",
+}
+
+func (v V2XCommunicationModeIndicator) String() string {
+	if s, ok := v2XCommunicationModeIndicatorNames[v.Value]; ok {
+		return s
+	}
+	return unknownEnumString("V2XCommunicationModeIndicator", int64(v.Value))
+}
+
+// IsValid reports whether the value is one of the named values.
+func (v V2XCommunicationModeIndicator) IsValid() bool {
+	_, ok := v2XCommunicationModeIndicatorNames[v.Value]
+	return ok
+}
+
+func ParseV2XCommunicationModeIndicator(s string) (V2XCommunicationModeIndicator, error) {
+	for value, name := range v2XCommunicationModeIndicatorNames {
+		if name == s {
+			return V2XCommunicationModeIndicator{Value: value}, nil
+		}
+	}
+	return V2XCommunicationModeIndicator{}, unknownEnumError("V2XCommunicationModeIndicator", s)
+}
+
@@ -0,0 +1,29 @@
+package cdrType
+
+import "fmt"
+
+// choiceAlternative is the name of a CHOICE alternative and whether its
+// pointer is set, in declaration order.
+type choiceAlternative struct {
+	name string
+	set  bool
+}
+
+// validateChoice is the Validate method generated for CHOICE types. The asn
+// package only marshals the alternative selected by Present, so a mismatch
+// would silently drop or fail on the value.
+func validateChoice(typeName string, present int, alternatives []choiceAlternative) error {
+	if present <= 0 || present > len(alternatives) {
+		return fmt.Errorf("%s: invalid Present %d", typeName, present)
+	}
+	selected := alternatives[present-1].name
+	for i, alt := range alternatives {
+		if i == present-1 && !alt.set {
+			return fmt.Errorf("%s: %s selected but not set", typeName, alt.name)
+		}
+		if i != present-1 && alt.set {
+			return fmt.Errorf("%s: %s set but %s selected", typeName, alt.name, selected)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package cdrType
+
+import (
+	"testing"
+
+	"github.com/free5gc/CDRUtil/asn"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChoice(t *testing.T) {
+	t.Parallel()
+
+	party := NewInvolvedPartySIPURI(asn.GraphicString("sip:alice@example.com"))
+	require.Equal(t, InvolvedPartyPresentSIPURI, party.Present)
+	require.NoError(t, party.Validate())
+	require.Equal(t, party.SIPURI, party.Value())
+
+	testCases := []struct {
+		name  string
+		party InvolvedParty
+	}{
+		{"nothing", InvolvedParty{}},
+		{"outOfRange", InvolvedParty{Present: InvolvedPartyPresentExternalId + 1}},
+		{"notSet", InvolvedParty{Present: InvolvedPartyPresentURN}},
+		{"otherSet", InvolvedParty{Present: InvolvedPartyPresentSIPURI, SIPURI: party.SIPURI, URN: party.SIPURI}},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			require.Error(t, tc.party.Validate())
+		})
+	}
+	require.Nil(t, InvolvedParty{}.Value())
+	require.True(t, InvolvedParty{Present: InvolvedPartyPresentURN}.Value() == nil)
+
+	trigger := NewTriggerSMFTrigger(SMFTrigger{Value: SMFTriggerPresentVolumeLimit})
+	require.NoError(t, trigger.Validate())
+	require.Equal(t, &SMFTrigger{Value: SMFTriggerPresentVolumeLimit}, trigger.Value())
+}
@@ -0,0 +1,90 @@
+package cdrType
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/free5gc/CDRUtil/asn"
+)
+
+// TimeStamp is YYMMDDhhmmssShhmm: the local date and time as BCD digits, the
+// sign of the offset from UTC as the ASCII "+" or "-", and the magnitude of
+// the offset in hours and minutes as BCD digits.
+
+const (
+	timeStampLength    = 9
+	timeStampSignOctet = 6
+	timeStampCentury   = 2000
+)
+
+func bcdOctet(v int) byte {
+	return byte(v/10)<<4 | byte(v%10)
+}
+
+// NewTimeStamp encodes t in its own zone. Only the last two digits of the
+// year are kept.
+func NewTimeStamp(t time.Time) TimeStamp {
+	_, offset := t.Zone()
+	sign := byte('+')
+	if offset < 0 {
+		sign = '-'
+		offset = -offset
+	}
+	return TimeStamp{Value: asn.OctetString{
+		bcdOctet(t.Year() % 100),
+		bcdOctet(int(t.Month())),
+		bcdOctet(t.Day()),
+		bcdOctet(t.Hour()),
+		bcdOctet(t.Minute()),
+		bcdOctet(t.Second()),
+		sign,
+		bcdOctet(offset / 3600),
+		bcdOctet(offset % 3600 / 60),
+	}}
+}
+
+// Decode returns the time of the TimeStamp in a fixed zone of its offset,
+// the year taken in this century.
+func (ts TimeStamp) Decode() (time.Time, error) {
+	if len(ts.Value) != timeStampLength {
+		return time.Time{}, fmt.Errorf("TimeStamp: invalid length %d", len(ts.Value))
+	}
+	var digits [timeStampLength]int
+	for i, octet := range ts.Value {
+		if i == timeStampSignOctet {
+			continue
+		}
+		high, low := octet>>4, octet&0x0f
+		if high > 9 || low > 9 {
+			return time.Time{}, fmt.Errorf("TimeStamp: invalid BCD octet 0x%02x", octet)
+		}
+		digits[i] = int(high)*10 + int(low)
+	}
+	year, month, day := timeStampCentury+digits[0], digits[1], digits[2]
+	hour, minute, second := digits[3], digits[4], digits[5]
+	offsetHour, offsetMinute := digits[7], digits[8]
+
+	if month < 1 || month > 12 {
+		return time.Time{}, fmt.Errorf("TimeStamp: invalid month %d", month)
+	}
+	// day 0 of the next month is the last day of the month
+	if day < 1 || day > time.Date(year, time.Month(month)+1, 0, 0, 0, 0, 0, time.UTC).Day() {
+		return time.Time{}, fmt.Errorf("TimeStamp: invalid day %d", day)
+	}
+	if hour > 23 || minute > 59 || second > 59 {
+		return time.Time{}, fmt.Errorf("TimeStamp: invalid time %02d:%02d:%02d", hour, minute, second)
+	}
+	if offsetHour > 23 || offsetMinute > 59 {
+		return time.Time{}, fmt.Errorf("TimeStamp: invalid offset %02d:%02d", offsetHour, offsetMinute)
+	}
+	offset := offsetHour*3600 + offsetMinute*60
+	switch ts.Value[timeStampSignOctet] {
+	case '+':
+	case '-':
+		offset = -offset
+	default:
+		return time.Time{}, fmt.Errorf("TimeStamp: invalid offset sign 0x%02x", ts.Value[timeStampSignOctet])
+	}
+
+	return time.Date(year, time.Month(month), day, hour, minute, second, 0, time.FixedZone("", offset)), nil
+}
@@ -0,0 +1,55 @@
+package cdrType
+
+import (
+	"testing"
+	"time"
+
+	"github.com/free5gc/CDRUtil/asn"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeStamp(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name   string
+		offset int
+		out    asn.OctetString
+	}{
+		{"utc", 0, asn.OctetString{0x22, 0x05, 0x04, 0x10, 0x20, 0x30, '+', 0x00, 0x00}},
+		{"plus530", 5*3600 + 30*60, asn.OctetString{0x22, 0x05, 0x04, 0x10, 0x20, 0x30, '+', 0x05, 0x30}},
+		{"minus8", -8 * 3600, asn.OctetString{0x22, 0x05, 0x04, 0x10, 0x20, 0x30, '-', 0x08, 0x00}},
+		{"minus930", -(9*3600 + 30*60), asn.OctetString{0x22, 0x05, 0x04, 0x10, 0x20, 0x30, '-', 0x09, 0x30}},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			in := time.Date(2022, time.May, 4, 10, 20, 30, 0, time.FixedZone("", tc.offset))
+			ts := NewTimeStamp(in)
+			require.Equal(t, tc.out, ts.Value)
+
+			out, err := ts.Decode()
+			require.NoError(t, err)
+			require.True(t, in.Equal(out))
+			_, offset := out.Zone()
+			require.Equal(t, tc.offset, offset)
+		})
+	}
+
+	for _, value := range []asn.OctetString{
+		{0x22, 0x05, 0x04, 0x10, 0x20, 0x30, '+', 0x00},
+		{0x22, 0x13, 0x04, 0x10, 0x20, 0x30, '+', 0x00, 0x00},
+		{0x22, 0x00, 0x04, 0x10, 0x20, 0x30, '+', 0x00, 0x00},
+		{0x22, 0x05, 0x32, 0x10, 0x20, 0x30, '+', 0x00, 0x00},
+		{0x22, 0x02, 0x29, 0x10, 0x20, 0x30, '+', 0x00, 0x00},
+		{0x22, 0x05, 0x04, 0x24, 0x20, 0x30, '+', 0x00, 0x00},
+		{0x22, 0x05, 0x04, 0x10, 0x60, 0x30, '+', 0x00, 0x00},
+		{0x22, 0x05, 0x04, 0x10, 0x20, 0x30, '+', 0x00, 0x60},
+		{0x22, 0x05, 0x04, 0x10, 0x20, 0x30, ' ', 0x00, 0x00},
+		{0x22, 0x05, 0x04, 0x1a, 0x20, 0x30, '+', 0x00, 0x00},
+	} {
+		_, err := TimeStamp{Value: value}.Decode()
+		require.Error(t, err, "%x", []byte(value))
+	}
+}
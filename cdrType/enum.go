@@ -0,0 +1,14 @@
+package cdrType
+
+import "fmt"
+
+// Helpers of the String and Parse functions generated for enumerations and
+// INTEGER types with named numbers.
+
+func unknownEnumString(typeName string, value int64) string {
+	return fmt.Sprintf("%s(%d)", typeName, value)
+}
+
+func unknownEnumError(typeName string, name string) error {
+	return fmt.Errorf("%s: unknown value %q", typeName, name)
+}
@@ -0,0 +1,69 @@
+package cdrType
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnumString(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name  string
+		value interface {
+			String() string
+			IsValid() bool
+		}
+		out   string
+		valid bool
+	}{
+		{"cause", CauseForRecClosing{Value: CauseForRecClosingPresentVolumeLimit}, "volumeLimit", true},
+		{"causeUnknown", CauseForRecClosing{Value: 2}, "CauseForRecClosing(2)", false},
+		{"ratType", RATType{Value: RATTypePresentNR}, "NR", true},
+		{"ratTypeLteM", RATType{Value: RATTypePresentLTEM}, "LTE-M", true},
+		{"ratTypeReserved", RATType{Value: 0}, "RATType(0)", false},
+		{"recordType", RecordType{Value: RecordTypePresentChargingFunctionRecord}, "chargingFunctionRecord", true},
+		{"recordTypeGgsn", RecordType{Value: 19}, "ggsnPDPRecord", true},
+		{"recordTypeUnused", RecordType{Value: 29}, "RecordType(29)", false},
+		{"smfTrigger", SMFTrigger{Value: SMFTriggerPresentQoSChange}, "qoSChange", true},
+		{"sscMode", SSCMode{Value: 0}, "SSCMode(0)", false},
+		{"pduSessionType", PDUSessionType{Value: PDUSessionTypePresentEthernet}, "ethernet", true},
+		{"pduSessionTypeUnknown", PDUSessionType{Value: 7}, "PDUSessionType(7)", false},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.out, tc.value.String())
+			require.Equal(t, tc.valid, tc.value.IsValid())
+		})
+	}
+}
+
+func TestEnumParse(t *testing.T) {
+	t.Parallel()
+
+	cause, err := ParseCauseForRecClosing("managementIntervention")
+	require.NoError(t, err)
+	require.Equal(t, CauseForRecClosingPresentManagementIntervention, cause.Value)
+
+	mode, err := ParseSSCMode("sSCMode3")
+	require.NoError(t, err)
+	require.Equal(t, SSCModePresentSSCMode3, mode.Value)
+
+	pduSessionType, err := ParsePDUSessionType("iPv4v6")
+	require.NoError(t, err)
+	require.Equal(t, PDUSessionTypePresentIPv4v6, pduSessionType.Value)
+
+	ratType, err := ParseRATType("UTRA")
+	require.NoError(t, err)
+	require.Equal(t, RATTypePresentUTRAN, ratType.Value)
+
+	recordType, err := ParseRecordType("s-CSCFRecord")
+	require.NoError(t, err)
+	require.Equal(t, RecordTypePresentSCSCFRecord, recordType.Value)
+
+	_, err = ParseRATType("nR")
+	require.Error(t, err)
+}
@@ -0,0 +1,5 @@
+package cdrType
+
+// The upper-case named files of this package are generated from the ASN.1
+// modules in cdrGen/asn1; helpers written by hand live in lower-case files.
+//go:generate go run ../cdrGen -out . ../cdrGen/asn1/CHFChargingDataTypes.asn ../cdrGen/asn1/GenericChargingDataTypes.asn ../cdrGen/asn1/GPRSChargingDataTypes.asn ../cdrGen/asn1/MAP-CommonDataTypes.asn ../cdrGen/asn1/MAP-ER-DataTypes.asn
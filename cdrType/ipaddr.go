@@ -0,0 +1,158 @@
+package cdrType
+
+import (
+	"fmt"
+	"net"
+	"net/netip"
+
+	"github.com/free5gc/CDRUtil/asn"
+)
+
+// DefaultIPv6PrefixLength is the PDP/PDN address prefix length of an
+// IPBinV6AddressWithPrefixLength without one.
+const DefaultIPv6PrefixLength = 64
+
+// NewIPAddress returns the binary IPv4 or IPv6 alternative for ip.
+func NewIPAddress(ip net.IP) (IPAddress, error) {
+	if ip4 := ip.To4(); ip4 != nil {
+		return NewIPAddressIPBinV4Address(IPBinV4Address{Value: asn.OctetString(ip4)}), nil
+	}
+	if ip16 := ip.To16(); ip16 != nil {
+		return NewIPAddressIPBinV6Address(IPBinV6Address{Value: asn.OctetString(ip16)}), nil
+	}
+	return IPAddress{}, fmt.Errorf("IPAddress: invalid IP %v", ip)
+}
+
+// NewIPTextAddress returns the text IPv4 or IPv6 alternative for ip.
+func NewIPTextAddress(ip net.IP) (IPAddress, error) {
+	text := asn.IA5String(ip.String())
+	if ip.To4() != nil {
+		return NewIPAddressIPTextV4Address(text), nil
+	}
+	if ip.To16() != nil {
+		return NewIPAddressIPTextV6Address(text), nil
+	}
+	return IPAddress{}, fmt.Errorf("IPAddress: invalid IP %v", ip)
+}
+
+// NewIPAddressFromPrefix returns the IPv6 address with prefix length
+// alternative for an IPv6 prefix, and the binary IPv4 alternative for an
+// IPv4 prefix, which has no prefix length in IPAddress.
+func NewIPAddressFromPrefix(prefix netip.Prefix) (IPAddress, error) {
+	if !prefix.IsValid() {
+		return IPAddress{}, fmt.Errorf("IPAddress: invalid prefix %v", prefix)
+	}
+	addr := prefix.Addr()
+	if addr.Is4() || addr.Is4In6() {
+		return NewIPAddress(net.IP(addr.AsSlice()))
+	}
+
+	v6 := addr.As16()
+	prefixLength := PDPAddressPrefixLength{Value: int64(prefix.Bits())}
+	return NewIPAddressIPBinV6AddressWithPrefix(IPBinV6AddressWithPrefixLength{
+		IPBinV6Address:         IPBinV6Address{Value: asn.OctetString(v6[:])},
+		PDPAddressPrefixLength: &prefixLength,
+	}), nil
+}
+
+// IP returns the address carried by any of the IPAddress alternatives.
+func (a IPAddress) IP() (net.IP, error) {
+	var ip net.IP
+
+	switch a.Present {
+	case IPAddressPresentIPBinV4Address:
+		if a.IPBinV4Address != nil && len(a.IPBinV4Address.Value) == net.IPv4len {
+			ip = net.IP(a.IPBinV4Address.Value)
+		}
+	case IPAddressPresentIPBinV6Address:
+		if a.IPBinV6Address != nil && len(a.IPBinV6Address.Value) == net.IPv6len {
+			ip = net.IP(a.IPBinV6Address.Value)
+		}
+	case IPAddressPresentIPTextV4Address:
+		if a.IPTextV4Address != nil {
+			ip = net.ParseIP(string(*a.IPTextV4Address)).To4()
+		}
+	case IPAddressPresentIPTextV6Address:
+		if a.IPTextV6Address != nil {
+			ip = net.ParseIP(string(*a.IPTextV6Address))
+		}
+	case IPAddressPresentIPBinV6AddressWithPrefix:
+		if a.IPBinV6AddressWithPrefix != nil &&
+			len(a.IPBinV6AddressWithPrefix.IPBinV6Address.Value) == net.IPv6len {
+			ip = net.IP(a.IPBinV6AddressWithPrefix.IPBinV6Address.Value)
+		}
+	default:
+		return nil, fmt.Errorf("IPAddress: invalid present %d", a.Present)
+	}
+
+	if ip == nil {
+		return nil, fmt.Errorf("IPAddress: invalid address for present %d", a.Present)
+	}
+	return ip, nil
+}
+
+// Prefix returns the address as a prefix. Addresses without a prefix length
+// are returned as single address prefixes.
+func (a IPAddress) Prefix() (netip.Prefix, error) {
+	ip, err := a.IP()
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+	addr, _ := netip.AddrFromSlice(ip)
+	addr = addr.Unmap()
+
+	bits := addr.BitLen()
+	if a.Present == IPAddressPresentIPBinV6AddressWithPrefix {
+		bits = DefaultIPv6PrefixLength
+		if l := a.IPBinV6AddressWithPrefix.PDPAddressPrefixLength; l != nil {
+			bits = int(l.Value)
+		}
+	}
+
+	prefix := netip.PrefixFrom(addr, bits)
+	if !prefix.IsValid() {
+		return netip.Prefix{}, fmt.Errorf("IPAddress: invalid prefix length %d", bits)
+	}
+	return prefix, nil
+}
+
+// NewPDUAddress builds the PDUAddress of a PDU session. An invalid ipv4
+// leaves the IPv4 part out and an empty ipv6Prefixes the IPv6 part, so a
+// dual-stack session sets both. The first IPv6 prefix is the PDU session
+// prefix and any further ones are reported as additional prefixes. The
+// dynamic flags are only set for the address families present.
+func NewPDUAddress(ipv4 netip.Addr, ipv4Dynamic bool,
+	ipv6Prefixes []netip.Prefix, ipv6Dynamic bool) (PDUAddress, error) {
+	var pduAddress PDUAddress
+
+	if ipv4.IsValid() {
+		if !ipv4.Is4() {
+			return PDUAddress{}, fmt.Errorf("PDUAddress: %v is not an IPv4 address", ipv4)
+		}
+		v4 := ipv4.As4()
+		address := NewIPAddressIPBinV4Address(IPBinV4Address{Value: asn.OctetString(v4[:])})
+		pduAddress.PDUIPv4Address = &address
+		pduAddress.IPV4dynamicAddressFlag = &DynamicAddressFlag{Value: ipv4Dynamic}
+	}
+
+	for i, prefix := range ipv6Prefixes {
+		if !prefix.Addr().Is6() || prefix.Addr().Is4In6() {
+			return PDUAddress{}, fmt.Errorf("PDUAddress: %v is not an IPv6 prefix", prefix)
+		}
+		address, err := NewIPAddressFromPrefix(prefix)
+		if err != nil {
+			return PDUAddress{}, fmt.Errorf("PDUAddress: %v", err)
+		}
+		if i == 0 {
+			pduAddress.PDUIPv6AddresswithPrefix = &address
+			pduAddress.IPV6dynamicPrefixFlag = &DynamicAddressFlag{Value: ipv6Dynamic}
+		} else {
+			pduAddress.AdditionalPDUIPv6Prefixes = append(pduAddress.AdditionalPDUIPv6Prefixes, address)
+		}
+	}
+
+	if pduAddress.PDUIPv4Address == nil && pduAddress.PDUIPv6AddresswithPrefix == nil {
+		return PDUAddress{}, fmt.Errorf("PDUAddress: no address present")
+	}
+	return pduAddress, nil
+}
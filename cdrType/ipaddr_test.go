@@ -0,0 +1,90 @@
+package cdrType
+
+import (
+	"net"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIPAddress(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name    string
+		in      IPAddress
+		present int
+		ip      string
+		prefix  string
+	}{
+		{"binV4", mustIPAddress(NewIPAddress(net.ParseIP("10.60.0.1"))),
+			IPAddressPresentIPBinV4Address, "10.60.0.1", "10.60.0.1/32"},
+		{"binV6", mustIPAddress(NewIPAddress(net.ParseIP("2001:db8::1"))),
+			IPAddressPresentIPBinV6Address, "2001:db8::1", "2001:db8::1/128"},
+		{"textV4", mustIPAddress(NewIPTextAddress(net.ParseIP("10.60.0.1"))),
+			IPAddressPresentIPTextV4Address, "10.60.0.1", "10.60.0.1/32"},
+		{"textV6", mustIPAddress(NewIPTextAddress(net.ParseIP("2001:db8::1"))),
+			IPAddressPresentIPTextV6Address, "2001:db8::1", "2001:db8::1/128"},
+		{"v6Prefix", mustIPAddress(NewIPAddressFromPrefix(netip.MustParsePrefix("2001:db8:1::/56"))),
+			IPAddressPresentIPBinV6AddressWithPrefix, "2001:db8:1::", "2001:db8:1::/56"},
+		{"v4Prefix", mustIPAddress(NewIPAddressFromPrefix(netip.MustParsePrefix("10.60.0.0/16"))),
+			IPAddressPresentIPBinV4Address, "10.60.0.0", "10.60.0.0/32"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.present, tc.in.Present)
+
+			ip, err := tc.in.IP()
+			require.NoError(t, err)
+			require.Equal(t, tc.ip, ip.String())
+
+			prefix, err := tc.in.Prefix()
+			require.NoError(t, err)
+			require.Equal(t, tc.prefix, prefix.String())
+		})
+	}
+
+	_, err := IPAddress{Present: IPAddressPresentIPBinV4Address}.IP()
+	require.Error(t, err)
+	_, err = NewIPAddress(net.IP{1, 2})
+	require.Error(t, err)
+}
+
+func TestPDUAddress(t *testing.T) {
+	t.Parallel()
+
+	pduAddress, err := NewPDUAddress(netip.MustParseAddr("10.60.0.1"), true,
+		[]netip.Prefix{
+			netip.MustParsePrefix("2001:db8:1::/64"),
+			netip.MustParsePrefix("2001:db8:2::/64"),
+		}, false)
+	require.NoError(t, err)
+	require.True(t, pduAddress.IPV4dynamicAddressFlag.Value)
+	require.False(t, pduAddress.IPV6dynamicPrefixFlag.Value)
+	require.Len(t, pduAddress.AdditionalPDUIPv6Prefixes, 1)
+	prefix, err := pduAddress.PDUIPv6AddresswithPrefix.Prefix()
+	require.NoError(t, err)
+	require.Equal(t, "2001:db8:1::/64", prefix.String())
+
+	pduAddress, err = NewPDUAddress(netip.MustParseAddr("10.60.0.1"), false, nil, true)
+	require.NoError(t, err)
+	require.Nil(t, pduAddress.PDUIPv6AddresswithPrefix)
+	require.Nil(t, pduAddress.IPV6dynamicPrefixFlag)
+
+	_, err = NewPDUAddress(netip.Addr{}, false, nil, false)
+	require.Error(t, err)
+	_, err = NewPDUAddress(netip.MustParseAddr("2001:db8::1"), false, nil, false)
+	require.Error(t, err)
+	_, err = NewPDUAddress(netip.Addr{}, false, []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")}, false)
+	require.Error(t, err)
+}
+
+func mustIPAddress(a IPAddress, err error) IPAddress {
+	if err != nil {
+		panic(err)
+	}
+	return a
+}
@@ -0,0 +1,85 @@
+package cdrType
+
+import (
+	"fmt"
+
+	"github.com/free5gc/CDRUtil/asn"
+)
+
+// PLMN-Id of TS 32.298 is a copy of the PLMN identity of TS 24.008:
+//   octet 1: MCC digit 2 | MCC digit 1
+//   octet 2: MNC digit 3 | MCC digit 3  (MNC digit 3 is 0xF for a 2 digit MNC)
+//   octet 3: MNC digit 2 | MNC digit 1
+
+const plmnIdLength = 3
+
+// NewPLMNId encodes a 3 digit MCC and a 2 or 3 digit MNC into a PLMNId.
+func NewPLMNId(mcc, mnc string) (PLMNId, error) {
+	if len(mcc) != 3 {
+		return PLMNId{}, fmt.Errorf("PLMNId: invalid MCC %q", mcc)
+	}
+	if len(mnc) != 2 && len(mnc) != 3 {
+		return PLMNId{}, fmt.Errorf("PLMNId: invalid MNC %q", mnc)
+	}
+	if err := checkDecimalDigits(mcc + mnc); err != nil {
+		return PLMNId{}, fmt.Errorf("PLMNId: %v", err)
+	}
+
+	mnc3 := byte(tbcdFiller)
+	if len(mnc) == 3 {
+		mnc3 = mnc[2] - '0'
+	}
+	value := asn.OctetString{
+		(mcc[1]-'0')<<4 | (mcc[0] - '0'),
+		mnc3<<4 | (mcc[2] - '0'),
+		(mnc[1]-'0')<<4 | (mnc[0] - '0'),
+	}
+	return PLMNId{Value: value}, nil
+}
+
+// Decode returns the MCC and MNC digits of the PLMNId.
+func (p PLMNId) Decode() (mcc, mnc string, err error) {
+	if len(p.Value) != plmnIdLength {
+		return "", "", fmt.Errorf("PLMNId: invalid length %d", len(p.Value))
+	}
+
+	digits := [6]byte{
+		p.Value[0] & 0xf, p.Value[0] >> 4, p.Value[1] & 0xf,
+		p.Value[2] & 0xf, p.Value[2] >> 4, p.Value[1] >> 4,
+	}
+	n := len(digits)
+	if digits[5] == tbcdFiller {
+		n--
+	}
+	for i := 0; i < n; i++ {
+		if digits[i] > 9 {
+			return "", "", fmt.Errorf("PLMNId: invalid digit 0x%x", digits[i])
+		}
+		digits[i] += '0'
+	}
+	return string(digits[:3]), string(digits[3:n]), nil
+}
+
+// Validate checks that the PLMNId carries a well formed MCC and MNC.
+func (p PLMNId) Validate() error {
+	_, _, err := p.Decode()
+	return err
+}
+
+// MCC returns the mobile country code, or "" if the PLMNId is malformed.
+func (p PLMNId) MCC() string {
+	mcc, _, err := p.Decode()
+	if err != nil {
+		return ""
+	}
+	return mcc
+}
+
+// MNC returns the mobile network code, or "" if the PLMNId is malformed.
+func (p PLMNId) MNC() string {
+	_, mnc, err := p.Decode()
+	if err != nil {
+		return ""
+	}
+	return mnc
+}
@@ -0,0 +1,41 @@
+package cdrType
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPLMNId(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name string
+		mcc  string
+		mnc  string
+		out  string
+	}{
+		{"twoDigitMnc", "208", "93", "02f839"},
+		{"threeDigitMnc", "310", "410", "130014"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			plmnId, err := NewPLMNId(tc.mcc, tc.mnc)
+			require.NoError(t, err)
+			require.Equal(t, tc.out, hex.EncodeToString(plmnId.Value))
+			require.Equal(t, tc.mcc, plmnId.MCC())
+			require.Equal(t, tc.mnc, plmnId.MNC())
+		})
+	}
+
+	for _, in := range [][2]string{{"20", "93"}, {"208", "9"}, {"208", "9a"}, {"2088", "93"}} {
+		_, err := NewPLMNId(in[0], in[1])
+		require.Error(t, err, in)
+	}
+	require.Error(t, PLMNId{Value: []byte{0x02, 0xf8}}.Validate())
+	require.Error(t, PLMNId{Value: []byte{0x0a, 0xf8, 0x39}}.Validate())
+	require.Equal(t, "", PLMNId{}.MCC())
+}
@@ -0,0 +1,52 @@
+package cdrType
+
+// RATType carries the RAT Type value of TS 29.274, named after the RatType of
+// TS 29.571 it is reported as on the SBI, or after TS 29.274 for the RAT types
+// without one. Value 0 is reserved.
+const (
+	RATTypePresentUTRAN         int64 = 1
+	RATTypePresentGERAN         int64 = 2
+	RATTypePresentWLAN          int64 = 3
+	RATTypePresentGAN           int64 = 4
+	RATTypePresentHSPAEvolution int64 = 5
+	RATTypePresentEUTRAN        int64 = 6
+	RATTypePresentVirtual       int64 = 7
+	RATTypePresentEUTRANNBIoT   int64 = 8
+	RATTypePresentLTEM          int64 = 9
+	RATTypePresentNR            int64 = 10
+)
+
+var ratTypeNames = map[int64]string{
+	RATTypePresentUTRAN:         "UTRA",
+	RATTypePresentGERAN:         "GERA",
+	RATTypePresentWLAN:          "WLAN",
+	RATTypePresentGAN:           "GAN",
+	RATTypePresentHSPAEvolution: "HSPA Evolution",
+	RATTypePresentEUTRAN:        "EUTRA",
+	RATTypePresentVirtual:       "VIRTUAL",
+	RATTypePresentEUTRANNBIoT:   "NBIOT",
+	RATTypePresentLTEM:          "LTE-M",
+	RATTypePresentNR:            "NR",
+}
+
+func (v RATType) String() string {
+	if s, ok := ratTypeNames[v.Value]; ok {
+		return s
+	}
+	return unknownEnumString("RATType", v.Value)
+}
+
+// IsValid reports whether the value is one of the named values.
+func (v RATType) IsValid() bool {
+	_, ok := ratTypeNames[v.Value]
+	return ok
+}
+
+func ParseRATType(s string) (RATType, error) {
+	for value, name := range ratTypeNames {
+		if name == s {
+			return RATType{Value: value}, nil
+		}
+	}
+	return RATType{}, unknownEnumError("RATType", s)
+}
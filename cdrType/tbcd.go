@@ -0,0 +1,327 @@
+package cdrType
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/free5gc/CDRUtil/asn"
+)
+
+// TBCD-STRING as defined in TS 29.002: two digits per octet, the first digit
+// in bits 4..1 and the second in bits 8..5. An odd number of digits is padded
+// with the filler 0xF in bits 8..5 of the last octet.
+
+const tbcdFiller = 0xf
+
+// Size limits of TS 29.002
+const (
+	imsiMinOctets          = 3
+	imsiMaxOctets          = 8
+	imsiMaxDigits          = 15
+	imeiOctets             = 8
+	imeiDigits             = 15
+	imeisvDigits           = 16
+	maxAddressLength       = 20
+	maxISDNAddressLength   = 9
+	macAddressOctets       = 6
+	addressStringHdrLength = 1
+)
+
+// Nature of address indicator of an AddressString (TS 29.002)
+const (
+	AddressNatureUnknown         = 0
+	AddressNatureInternational   = 1
+	AddressNatureNational        = 2
+	AddressNatureNetworkSpecific = 3
+	AddressNatureSubscriber      = 4
+	AddressNatureAbbreviated     = 6
+)
+
+// Numbering plan indicator of an AddressString (TS 29.002)
+const (
+	NumberingPlanUnknown  = 0
+	NumberingPlanISDN     = 1 // ITU-T Rec. E.164
+	NumberingPlanData     = 3 // ITU-T Rec. X.121
+	NumberingPlanTelex    = 4 // ITU-T Rec. F.69
+	NumberingPlanLandMob  = 6 // ITU-T Rec. E.212
+	NumberingPlanNational = 8
+	NumberingPlanPrivate  = 9
+)
+
+var tbcdDigits = "0123456789*#abc"
+
+// EncodeTBCD packs a digit string into TBCD octets. Besides decimal digits,
+// '*', '#', 'a', 'b' and 'c' are accepted for the values 0xA to 0xE.
+func EncodeTBCD(digits string) (asn.OctetString, error) {
+	octets := make(asn.OctetString, (len(digits)+1)/2)
+
+	for i := 0; i < len(digits); i++ {
+		nibble := strings.IndexByte(tbcdDigits, digits[i])
+		if nibble < 0 {
+			return nil, fmt.Errorf("invalid TBCD digit %q at position %d", digits[i], i)
+		}
+		if i%2 == 0 {
+			octets[i/2] = byte(nibble)
+		} else {
+			octets[i/2] |= byte(nibble) << 4
+		}
+	}
+	if len(digits)%2 == 1 {
+		octets[len(octets)-1] |= tbcdFiller << 4
+	}
+
+	return octets, nil
+}
+
+// DecodeTBCD unpacks TBCD octets into a digit string. The filler 0xF is only
+// accepted in bits 8..5 of the last octet.
+func DecodeTBCD(octets asn.OctetString) (string, error) {
+	var sb strings.Builder
+	sb.Grow(len(octets) * 2)
+
+	for i, o := range octets {
+		low, high := o&0x0f, o>>4
+		if low == tbcdFiller {
+			return "", fmt.Errorf("TBCD filler in bits 4..1 of octet %d", i)
+		}
+		sb.WriteByte(tbcdDigits[low])
+		if high == tbcdFiller {
+			if i != len(octets)-1 {
+				return "", fmt.Errorf("TBCD filler in octet %d is not in the last octet", i)
+			}
+			break
+		}
+		sb.WriteByte(tbcdDigits[high])
+	}
+
+	return sb.String(), nil
+}
+
+func checkDecimalDigits(digits string) error {
+	for i := 0; i < len(digits); i++ {
+		if digits[i] < '0' || digits[i] > '9' {
+			return fmt.Errorf("invalid decimal digit %q at position %d", digits[i], i)
+		}
+	}
+	return nil
+}
+
+// NewTBCDSTRING encodes digits into a TBCDSTRING.
+func NewTBCDSTRING(digits string) (TBCDSTRING, error) {
+	octets, err := EncodeTBCD(digits)
+	if err != nil {
+		return TBCDSTRING{}, err
+	}
+	return TBCDSTRING{Value: octets}, nil
+}
+
+// Digits returns the digit string carried by the TBCDSTRING.
+func (t TBCDSTRING) Digits() (string, error) {
+	return DecodeTBCD(t.Value)
+}
+
+// NewIMSI encodes an IMSI of at most 15 decimal digits, i.e. MCC, MNC and MSIN.
+func NewIMSI(digits string) (IMSI, error) {
+	if err := checkDecimalDigits(digits); err != nil {
+		return IMSI{}, fmt.Errorf("IMSI: %v", err)
+	}
+	if len(digits) > imsiMaxDigits || (len(digits)+1)/2 < imsiMinOctets {
+		return IMSI{}, fmt.Errorf("IMSI: invalid number of digits %d", len(digits))
+	}
+
+	tbcd, err := NewTBCDSTRING(digits)
+	if err != nil {
+		return IMSI{}, err
+	}
+	return IMSI{Value: tbcd}, nil
+}
+
+// Digits returns the IMSI as a decimal digit string.
+func (i IMSI) Digits() (string, error) {
+	if l := len(i.Value.Value); l < imsiMinOctets || l > imsiMaxOctets {
+		return "", fmt.Errorf("IMSI: invalid length %d", l)
+	}
+	digits, err := i.Value.Digits()
+	if err != nil {
+		return "", fmt.Errorf("IMSI: %v", err)
+	}
+	if err := checkDecimalDigits(digits); err != nil {
+		return "", fmt.Errorf("IMSI: %v", err)
+	}
+	return digits, nil
+}
+
+// NewAddressString builds an AddressString from the nature of address,
+// numbering plan and address digits.
+func NewAddressString(nature, plan uint8, digits string) (AddressString, error) {
+	if nature > 0x7 || plan > 0xf {
+		return AddressString{}, fmt.Errorf("AddressString: invalid nature %d or numbering plan %d", nature, plan)
+	}
+	tbcd, err := EncodeTBCD(digits)
+	if err != nil {
+		return AddressString{}, fmt.Errorf("AddressString: %v", err)
+	}
+	if addressStringHdrLength+len(tbcd) > maxAddressLength {
+		return AddressString{}, fmt.Errorf("AddressString: too many digits %d", len(digits))
+	}
+
+	value := make(asn.OctetString, 0, addressStringHdrLength+len(tbcd))
+	// Bit 8 is the extension indicator, always "no extension"
+	value = append(value, 0x80|nature<<4|plan)
+	value = append(value, tbcd...)
+	return AddressString{Value: value}, nil
+}
+
+// NatureOfAddress returns the nature of address indicator.
+func (a AddressString) NatureOfAddress() uint8 {
+	if len(a.Value) == 0 {
+		return AddressNatureUnknown
+	}
+	return (a.Value[0] >> 4) & 0x7
+}
+
+// NumberingPlan returns the numbering plan indicator.
+func (a AddressString) NumberingPlan() uint8 {
+	if len(a.Value) == 0 {
+		return NumberingPlanUnknown
+	}
+	return a.Value[0] & 0xf
+}
+
+// Digits returns the address digits following the nature/plan octet.
+func (a AddressString) Digits() (string, error) {
+	if len(a.Value) < addressStringHdrLength || len(a.Value) > maxAddressLength {
+		return "", fmt.Errorf("AddressString: invalid length %d", len(a.Value))
+	}
+	digits, err := DecodeTBCD(a.Value[addressStringHdrLength:])
+	if err != nil {
+		return "", fmt.Errorf("AddressString: %v", err)
+	}
+	return digits, nil
+}
+
+// NewMSISDN encodes an MSISDN in international E.164 format.
+func NewMSISDN(digits string) (MSISDN, error) {
+	if err := checkDecimalDigits(digits); err != nil {
+		return MSISDN{}, fmt.Errorf("MSISDN: %v", err)
+	}
+	if len(digits) == 0 || addressStringHdrLength+(len(digits)+1)/2 > maxISDNAddressLength {
+		return MSISDN{}, fmt.Errorf("MSISDN: invalid number of digits %d", len(digits))
+	}
+
+	addr, err := NewAddressString(AddressNatureInternational, NumberingPlanISDN, digits)
+	if err != nil {
+		return MSISDN{}, err
+	}
+	return MSISDN{Value: ISDNAddressString{Value: addr}}, nil
+}
+
+// Digits returns the MSISDN digits without the nature/plan octet.
+func (m MSISDN) Digits() (string, error) {
+	addr := m.Value.Value
+	if len(addr.Value) > maxISDNAddressLength {
+		return "", fmt.Errorf("MSISDN: invalid length %d", len(addr.Value))
+	}
+	return addr.Digits()
+}
+
+// NewSubscriberEquipmentNumberIMEI encodes a 15 digit IMEI or a 16 digit
+// IMEISV as subscriberEquipmentNumberData of type IMEISV.
+func NewSubscriberEquipmentNumberIMEI(digits string) (SubscriberEquipmentNumber, error) {
+	if err := checkDecimalDigits(digits); err != nil {
+		return SubscriberEquipmentNumber{}, fmt.Errorf("IMEI: %v", err)
+	}
+	if len(digits) != imeiDigits && len(digits) != imeisvDigits {
+		return SubscriberEquipmentNumber{}, fmt.Errorf("IMEI: invalid number of digits %d", len(digits))
+	}
+
+	data, err := EncodeTBCD(digits)
+	if err != nil {
+		return SubscriberEquipmentNumber{}, err
+	}
+	return SubscriberEquipmentNumber{
+		SubscriberEquipmentNumberType: SubscriberEquipmentType{
+			Value: SubscriberEquipmentTypePresentIMEISV,
+		},
+		SubscriberEquipmentNumberData: data,
+	}, nil
+}
+
+// IMEI returns the IMEI or IMEISV digits of an equipment number of type IMEISV.
+func (s SubscriberEquipmentNumber) IMEI() (string, error) {
+	if s.SubscriberEquipmentNumberType.Value != SubscriberEquipmentTypePresentIMEISV {
+		return "", fmt.Errorf("IMEI: equipment number type is %d", s.SubscriberEquipmentNumberType.Value)
+	}
+	if len(s.SubscriberEquipmentNumberData) != imeiOctets {
+		return "", fmt.Errorf("IMEI: invalid length %d", len(s.SubscriberEquipmentNumberData))
+	}
+	digits, err := DecodeTBCD(s.SubscriberEquipmentNumberData)
+	if err != nil {
+		return "", fmt.Errorf("IMEI: %v", err)
+	}
+	if err := checkDecimalDigits(digits); err != nil {
+		return "", fmt.Errorf("IMEI: %v", err)
+	}
+	return digits, nil
+}
+
+// SUPI, GPSI and PEI string forms of TS 29.571
+const (
+	supiPrefixIMSI   = "imsi-"
+	gpsiPrefixMSISDN = "msisdn-"
+	peiPrefixIMEI    = "imei-"
+	peiPrefixIMEISV  = "imeisv-"
+	peiPrefixMAC     = "mac-"
+)
+
+// NewIMSIFromSupi converts a SUPI of the form "imsi-<digits>" into an IMSI.
+func NewIMSIFromSupi(supi string) (IMSI, error) {
+	if !strings.HasPrefix(supi, supiPrefixIMSI) {
+		return IMSI{}, fmt.Errorf("SUPI %q is not an IMSI", supi)
+	}
+	return NewIMSI(strings.TrimPrefix(supi, supiPrefixIMSI))
+}
+
+// NewMSISDNFromGpsi converts a GPSI of the form "msisdn-<digits>" into an MSISDN.
+func NewMSISDNFromGpsi(gpsi string) (MSISDN, error) {
+	if !strings.HasPrefix(gpsi, gpsiPrefixMSISDN) {
+		return MSISDN{}, fmt.Errorf("GPSI %q is not an MSISDN", gpsi)
+	}
+	return NewMSISDN(strings.TrimPrefix(gpsi, gpsiPrefixMSISDN))
+}
+
+// NewSubscriberEquipmentNumberFromPei converts a PEI of the form
+// "imei-<digits>", "imeisv-<digits>" or "mac-<xx-xx-xx-xx-xx-xx>".
+func NewSubscriberEquipmentNumberFromPei(pei string) (SubscriberEquipmentNumber, error) {
+	switch {
+	case strings.HasPrefix(pei, peiPrefixIMEI):
+		return NewSubscriberEquipmentNumberIMEI(strings.TrimPrefix(pei, peiPrefixIMEI))
+	case strings.HasPrefix(pei, peiPrefixIMEISV):
+		return NewSubscriberEquipmentNumberIMEI(strings.TrimPrefix(pei, peiPrefixIMEISV))
+	case strings.HasPrefix(pei, peiPrefixMAC):
+		mac, err := parseMacAddress(strings.TrimPrefix(pei, peiPrefixMAC))
+		if err != nil {
+			return SubscriberEquipmentNumber{}, fmt.Errorf("PEI %q: %v", pei, err)
+		}
+		return SubscriberEquipmentNumber{
+			SubscriberEquipmentNumberType: SubscriberEquipmentType{
+				Value: SubscriberEquipmentTypePresentMAC,
+			},
+			SubscriberEquipmentNumberData: mac,
+		}, nil
+	}
+	return SubscriberEquipmentNumber{}, fmt.Errorf("unsupported PEI %q", pei)
+}
+
+func parseMacAddress(s string) (asn.OctetString, error) {
+	parts := strings.Split(s, "-")
+	if len(parts) != macAddressOctets {
+		return nil, fmt.Errorf("invalid MAC address %q", s)
+	}
+	mac, err := hex.DecodeString(strings.Join(parts, ""))
+	if err != nil || len(mac) != macAddressOctets {
+		return nil, fmt.Errorf("invalid MAC address %q", s)
+	}
+	return mac, nil
+}
@@ -0,0 +1,91 @@
+package cdrType
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTBCD(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name   string
+		digits string
+		out    string
+	}{
+		{"even", "208930", "029803"},
+		{"odd", "20893", "0298f3"},
+		{"imsi", "208930000000003", "02980300000000f3"},
+		{"special", "*#abc", "badcfe"},
+		{"empty", "", ""},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			octets, err := EncodeTBCD(tc.digits)
+			require.NoError(t, err)
+			require.Equal(t, tc.out, hex.EncodeToString(octets))
+
+			digits, err := DecodeTBCD(octets)
+			require.NoError(t, err)
+			require.Equal(t, tc.digits, digits)
+		})
+	}
+
+	_, err := EncodeTBCD("12x")
+	require.Error(t, err)
+	_, err = DecodeTBCD([]byte{0xf1, 0x21})
+	require.Error(t, err)
+	_, err = DecodeTBCD([]byte{0x0f})
+	require.Error(t, err)
+}
+
+func TestIdentities(t *testing.T) {
+	t.Parallel()
+
+	imsi, err := NewIMSIFromSupi("imsi-208930000000003")
+	require.NoError(t, err)
+	require.Equal(t, "02980300000000f3", hex.EncodeToString(imsi.Value.Value))
+	digits, err := imsi.Digits()
+	require.NoError(t, err)
+	require.Equal(t, "208930000000003", digits)
+
+	_, err = NewIMSIFromSupi("nai-user@example.com")
+	require.Error(t, err)
+	_, err = NewIMSI("1234567890123456")
+	require.Error(t, err)
+	_, err = NewIMSI("1234")
+	require.Error(t, err)
+
+	msisdn, err := NewMSISDNFromGpsi("msisdn-886912345678")
+	require.NoError(t, err)
+	require.Equal(t, "91889621436587", hex.EncodeToString(msisdn.Value.Value.Value))
+	require.Equal(t, uint8(AddressNatureInternational), msisdn.Value.Value.NatureOfAddress())
+	require.Equal(t, uint8(NumberingPlanISDN), msisdn.Value.Value.NumberingPlan())
+	digits, err = msisdn.Digits()
+	require.NoError(t, err)
+	require.Equal(t, "886912345678", digits)
+
+	_, err = NewMSISDN("12345678901234567")
+	require.Error(t, err)
+
+	pei, err := NewSubscriberEquipmentNumberFromPei("imeisv-4370816125816151")
+	require.NoError(t, err)
+	digits, err = pei.IMEI()
+	require.NoError(t, err)
+	require.Equal(t, "4370816125816151", digits)
+
+	pei, err = NewSubscriberEquipmentNumberFromPei("imei-437081612581615")
+	require.NoError(t, err)
+	require.Equal(t, "34071816521816f5", hex.EncodeToString(pei.SubscriberEquipmentNumberData))
+
+	pei, err = NewSubscriberEquipmentNumberFromPei("mac-00-00-5E-00-53-00")
+	require.NoError(t, err)
+	require.Equal(t, SubscriberEquipmentTypePresentMAC, pei.SubscriberEquipmentNumberType.Value)
+	require.Equal(t, "00005e005300", hex.EncodeToString(pei.SubscriberEquipmentNumberData))
+	_, err = pei.IMEI()
+	require.Error(t, err)
+}
@@ -0,0 +1,101 @@
+package cdrType
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/free5gc/CDRUtil/asn"
+)
+
+// MSTimeZone follows the UE Time Zone IE of TS 29.274:
+//   octet 1: Time Zone as in TS 24.008, the offset from UTC in quarters of an
+//            hour as two swapped BCD digits, with the sign in bit 4
+//   octet 2: Daylight Saving Time adjustment in bits 2..1
+// The time zone includes the daylight saving time adjustment.
+
+const (
+	msTimeZoneLength   = 2
+	timeZoneSignBit    = 0x08
+	timeZoneQuarter    = 15 * 60 // seconds
+	timeZoneMaxQuarter = 79      // largest value of the two BCD digits with the sign bit cleared
+	dstMask            = 0x03
+)
+
+// Daylight Saving Time adjustment
+const (
+	DaylightSavingNoAdjustment = 0
+	DaylightSavingPlusOneHour  = 1
+	DaylightSavingPlusTwoHours = 2
+)
+
+// NewMSTimeZone encodes an offset from UTC in seconds, which must be a
+// multiple of 15 minutes and include the daylight saving adjustment, and the
+// daylight saving adjustment in hours.
+func NewMSTimeZone(offset int, dst int) (MSTimeZone, error) {
+	if offset%timeZoneQuarter != 0 {
+		return MSTimeZone{}, fmt.Errorf("MSTimeZone: offset %ds is not a multiple of 15 minutes", offset)
+	}
+	if dst < DaylightSavingNoAdjustment || dst > DaylightSavingPlusTwoHours {
+		return MSTimeZone{}, fmt.Errorf("MSTimeZone: invalid daylight saving adjustment %d", dst)
+	}
+
+	quarters := offset / timeZoneQuarter
+	var sign byte
+	if quarters < 0 {
+		quarters = -quarters
+		sign = timeZoneSignBit
+	}
+	if quarters > timeZoneMaxQuarter {
+		return MSTimeZone{}, fmt.Errorf("MSTimeZone: offset %ds out of range", offset)
+	}
+
+	tz := byte(quarters%10)<<4 | byte(quarters/10) | sign
+	return MSTimeZone{Value: asn.OctetString{tz, byte(dst)}}, nil
+}
+
+// NewMSTimeZoneFromTime encodes the zone in effect at t. The daylight saving
+// adjustment is the difference to the standard offset of the zone that year.
+func NewMSTimeZoneFromTime(t time.Time) (MSTimeZone, error) {
+	_, offset := t.Zone()
+	_, jan := time.Date(t.Year(), time.January, 1, 0, 0, 0, 0, t.Location()).Zone()
+	_, jul := time.Date(t.Year(), time.July, 1, 0, 0, 0, 0, t.Location()).Zone()
+
+	standard := jan
+	if jul < standard {
+		standard = jul
+	}
+	return NewMSTimeZone(offset, (offset-standard)/3600)
+}
+
+// Decode returns the offset from UTC in seconds, including the daylight
+// saving adjustment, and the daylight saving adjustment in hours.
+func (z MSTimeZone) Decode() (offset int, dst int, err error) {
+	if len(z.Value) != msTimeZoneLength {
+		return 0, 0, fmt.Errorf("MSTimeZone: invalid length %d", len(z.Value))
+	}
+
+	tz := z.Value[0]
+	tens, units := tz&0x07, tz>>4
+	if units > 9 {
+		return 0, 0, fmt.Errorf("MSTimeZone: invalid time zone 0x%02x", tz)
+	}
+	offset = int(tens*10+units) * timeZoneQuarter
+	if tz&timeZoneSignBit != 0 {
+		offset = -offset
+	}
+
+	dst = int(z.Value[1] & dstMask)
+	if dst > DaylightSavingPlusTwoHours {
+		return 0, 0, fmt.Errorf("MSTimeZone: invalid daylight saving adjustment %d", dst)
+	}
+	return offset, dst, nil
+}
+
+// Location returns a fixed zone with the offset of the MSTimeZone.
+func (z MSTimeZone) Location() (*time.Location, error) {
+	offset, _, err := z.Decode()
+	if err != nil {
+		return nil, err
+	}
+	return time.FixedZone("", offset), nil
+}
@@ -0,0 +1,59 @@
+package cdrType
+
+import (
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMSTimeZone(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name   string
+		offset int
+		dst    int
+		out    string
+	}{
+		{"utc", 0, 0, "0000"},
+		{"plus8", 8 * 3600, 0, "2300"},
+		{"minus5dst", -4 * 3600, 1, "6901"},
+		{"plus545", 5*3600 + 45*60, 0, "3200"},
+		{"minus930", -(9*3600 + 30*60), 0, "8b00"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			tz, err := NewMSTimeZone(tc.offset, tc.dst)
+			require.NoError(t, err)
+			require.Equal(t, tc.out, hex.EncodeToString(tz.Value))
+
+			offset, dst, err := tz.Decode()
+			require.NoError(t, err)
+			require.Equal(t, tc.offset, offset)
+			require.Equal(t, tc.dst, dst)
+		})
+	}
+
+	_, err := NewMSTimeZone(10*60, 0)
+	require.Error(t, err)
+	_, err = NewMSTimeZone(0, 3)
+	require.Error(t, err)
+	_, err = NewMSTimeZone(20*3600, 0)
+	require.Error(t, err)
+	_, _, err = MSTimeZone{Value: []byte{0xa0, 0x00}}.Decode()
+	require.Error(t, err)
+
+	tz, err := NewMSTimeZoneFromTime(time.Date(2021, time.June, 1, 0, 0, 0, 0, time.FixedZone("", 2*3600)))
+	require.NoError(t, err)
+	require.Equal(t, "8000", hex.EncodeToString(tz.Value))
+
+	if loc, err := time.LoadLocation("Europe/Berlin"); err == nil {
+		tz, err = NewMSTimeZoneFromTime(time.Date(2021, time.June, 1, 0, 0, 0, 0, loc))
+		require.NoError(t, err)
+		require.Equal(t, "8001", hex.EncodeToString(tz.Value))
+	}
+}
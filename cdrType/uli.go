@@ -0,0 +1,305 @@
+package cdrType
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/free5gc/CDRUtil/asn"
+)
+
+// The octet string UserLocationInformation follows the 3GPP-User-Location-Info
+// of TS 29.061: one octet of Geographic Location Type followed by the location
+// fields of that type.
+
+// Geographic Location Type
+const (
+	ULITypeTAI            = 128
+	ULITypeECGI           = 129
+	ULITypeTAIAndECGI     = 130
+	ULITypeENodeBID       = 131
+	ULITypeTAIAndENodeBID = 132
+	ULITypeNCGI           = 135
+	ULIType5GSTAI         = 136
+	ULIType5GSTAIAndNCGI  = 137
+)
+
+const (
+	uliTypeLength        = 1
+	uliTAILength         = plmnIdLength + 2
+	uliECGILength        = plmnIdLength + 4
+	uliENodeBIDLength    = plmnIdLength + 3
+	uliNCGILength        = plmnIdLength + 5
+	uli5GSTAILength      = plmnIdLength + 3
+	uliSpareBitsMask     = 0x0f // the identities leave bits 8..5 of their first octet spare
+	eutraCellIdHexDigits = 7
+	nrCellIdHexDigits    = 9
+	macroENbIdHexDigits  = 5
+	macroENbIdPrefix     = "MacroeNB-"
+)
+
+// Decode parses the octet string into the structured user location.
+func (u UserLocationInformation) Decode() (UserLocationInformationStructured, error) {
+	var structured UserLocationInformationStructured
+
+	if len(u.Value) < uliTypeLength {
+		return structured, fmt.Errorf("UserLocationInformation: empty")
+	}
+	locType, data := u.Value[0], u.Value[uliTypeLength:]
+
+	var length int
+	switch locType {
+	case ULITypeTAI:
+		length = uliTAILength
+	case ULITypeECGI:
+		length = uliECGILength
+	case ULITypeTAIAndECGI:
+		length = uliTAILength + uliECGILength
+	case ULITypeENodeBID:
+		length = uliENodeBIDLength
+	case ULITypeTAIAndENodeBID:
+		length = uliTAILength + uliENodeBIDLength
+	case ULITypeNCGI:
+		length = uliNCGILength
+	case ULIType5GSTAI:
+		length = uli5GSTAILength
+	case ULIType5GSTAIAndNCGI:
+		length = uli5GSTAILength + uliNCGILength
+	default:
+		return structured, fmt.Errorf("UserLocationInformation: unsupported location type %d", locType)
+	}
+	if len(data) != length {
+		return structured, fmt.Errorf("UserLocationInformation: invalid length %d for location type %d",
+			len(data), locType)
+	}
+
+	switch locType {
+	case ULITypeTAI, ULITypeECGI, ULITypeTAIAndECGI, ULITypeENodeBID, ULITypeTAIAndENodeBID:
+		eutra := new(EutraLocation)
+		if locType == ULITypeTAI || locType == ULITypeTAIAndECGI || locType == ULITypeTAIAndENodeBID {
+			eutra.Tai = decodeULITAI(data[:uliTAILength])
+			data = data[uliTAILength:]
+		}
+		if locType == ULITypeECGI || locType == ULITypeTAIAndECGI {
+			eutra.Ecgi = decodeULIECGI(data)
+		}
+		if locType == ULITypeENodeBID || locType == ULITypeTAIAndENodeBID {
+			eutra.GlobalENbId = decodeULIENodeBID(data)
+		}
+		structured.EutraLocation = eutra
+	default:
+		nr := new(NrLocation)
+		if locType == ULIType5GSTAI || locType == ULIType5GSTAIAndNCGI {
+			nr.Tai = decodeULITAI(data[:uli5GSTAILength])
+			data = data[uli5GSTAILength:]
+		}
+		if locType == ULITypeNCGI || locType == ULIType5GSTAIAndNCGI {
+			nr.Ncgi = decodeULINCGI(data)
+		}
+		structured.NrLocation = nr
+	}
+
+	return structured, nil
+}
+
+func decodeULITAI(b []byte) *TAI {
+	return &TAI{
+		PLMNId: PLMNId{Value: append(asn.OctetString{}, b[:plmnIdLength]...)},
+		Tac:    TAC{Value: append(asn.OctetString{}, b[plmnIdLength:]...)},
+	}
+}
+
+func decodeULIECGI(b []byte) *Ecgi {
+	eci := append([]byte{}, b[plmnIdLength:]...)
+	eci[0] &= uliSpareBitsMask
+	return &Ecgi{
+		PlmnId:      PLMNId{Value: append(asn.OctetString{}, b[:plmnIdLength]...)},
+		EutraCellId: EutraCellId{Value: asn.UTF8String(hex.EncodeToString(eci)[1:])},
+	}
+}
+
+func decodeULINCGI(b []byte) *Ncgi {
+	nci := append([]byte{}, b[plmnIdLength:]...)
+	nci[0] &= uliSpareBitsMask
+	return &Ncgi{
+		PlmnId:   PLMNId{Value: append(asn.OctetString{}, b[:plmnIdLength]...)},
+		NrCellId: NrCellId{Value: asn.UTF8String(hex.EncodeToString(nci)[1:])},
+	}
+}
+
+func decodeULIENodeBID(b []byte) *GlobalRanNodeId {
+	plmnId := PLMNId{Value: append(asn.OctetString{}, b[:plmnIdLength]...)}
+	id := append([]byte{}, b[plmnIdLength:]...)
+	id[0] &= uliSpareBitsMask
+	return &GlobalRanNodeId{
+		PLMNId: &plmnId,
+		ENbId:  &ENbId{Value: asn.UTF8String(macroENbIdPrefix + hex.EncodeToString(id)[1:])},
+	}
+}
+
+// NewUserLocationInformation encodes a structured E-UTRA or NR location into
+// the octet string form. N3GA locations have no octet string representation.
+func NewUserLocationInformation(s UserLocationInformationStructured) (UserLocationInformation, error) {
+	var value asn.OctetString
+	var err error
+
+	switch {
+	case s.EutraLocation != nil:
+		value, err = encodeULIEutra(s.EutraLocation)
+	case s.NrLocation != nil:
+		value, err = encodeULINr(s.NrLocation)
+	case s.N3gaLocation != nil:
+		err = fmt.Errorf("N3GA location is not supported")
+	default:
+		err = fmt.Errorf("no location present")
+	}
+	if err != nil {
+		return UserLocationInformation{}, fmt.Errorf("UserLocationInformation: %v", err)
+	}
+	return UserLocationInformation{Value: value}, nil
+}
+
+func encodeULIEutra(loc *EutraLocation) (asn.OctetString, error) {
+	var locType byte
+	switch {
+	case loc.Tai != nil && loc.Ecgi != nil:
+		locType = ULITypeTAIAndECGI
+	case loc.Ecgi != nil:
+		locType = ULITypeECGI
+	case loc.Tai != nil && loc.GlobalENbId != nil:
+		locType = ULITypeTAIAndENodeBID
+	case loc.GlobalENbId != nil:
+		locType = ULITypeENodeBID
+	case loc.Tai != nil:
+		locType = ULITypeTAI
+	default:
+		return nil, fmt.Errorf("E-UTRA location without TAI, ECGI or eNB ID")
+	}
+
+	value := asn.OctetString{locType}
+	if loc.Tai != nil {
+		tai, err := encodeULITAI(loc.Tai, uliTAILength)
+		if err != nil {
+			return nil, err
+		}
+		value = append(value, tai...)
+	}
+	switch {
+	case loc.Ecgi != nil:
+		eci, err := parseCellId(string(loc.Ecgi.EutraCellId.Value), eutraCellIdHexDigits)
+		if err != nil {
+			return nil, fmt.Errorf("E-UTRA cell ID: %v", err)
+		}
+		plmnId, err := checkedPLMNId(loc.Ecgi.PlmnId)
+		if err != nil {
+			return nil, err
+		}
+		value = append(value, plmnId...)
+		value = append(value, eci...)
+	case loc.GlobalENbId != nil:
+		enb := loc.GlobalENbId
+		if enb.PLMNId == nil || enb.ENbId == nil {
+			return nil, fmt.Errorf("global eNB ID without PLMN or eNB ID")
+		}
+		id := string(enb.ENbId.Value)
+		if !strings.HasPrefix(id, macroENbIdPrefix) {
+			return nil, fmt.Errorf("unsupported eNB ID %q", id)
+		}
+		enbId, err := parseCellId(strings.TrimPrefix(id, macroENbIdPrefix), macroENbIdHexDigits)
+		if err != nil {
+			return nil, fmt.Errorf("eNB ID: %v", err)
+		}
+		plmnId, err := checkedPLMNId(*enb.PLMNId)
+		if err != nil {
+			return nil, err
+		}
+		value = append(value, plmnId...)
+		value = append(value, enbId...)
+	}
+	return value, nil
+}
+
+func encodeULINr(loc *NrLocation) (asn.OctetString, error) {
+	var locType byte
+	switch {
+	case loc.Tai != nil && loc.Ncgi != nil:
+		locType = ULIType5GSTAIAndNCGI
+	case loc.Ncgi != nil:
+		locType = ULITypeNCGI
+	case loc.Tai != nil:
+		locType = ULIType5GSTAI
+	default:
+		return nil, fmt.Errorf("NR location without TAI or NCGI")
+	}
+
+	value := asn.OctetString{locType}
+	if loc.Tai != nil {
+		tai, err := encodeULITAI(loc.Tai, uli5GSTAILength)
+		if err != nil {
+			return nil, err
+		}
+		value = append(value, tai...)
+	}
+	if loc.Ncgi != nil {
+		nci, err := parseCellId(string(loc.Ncgi.NrCellId.Value), nrCellIdHexDigits)
+		if err != nil {
+			return nil, fmt.Errorf("NR cell ID: %v", err)
+		}
+		plmnId, err := checkedPLMNId(loc.Ncgi.PlmnId)
+		if err != nil {
+			return nil, err
+		}
+		value = append(value, plmnId...)
+		value = append(value, nci...)
+	}
+	return value, nil
+}
+
+func encodeULITAI(tai *TAI, length int) (asn.OctetString, error) {
+	plmnId, err := checkedPLMNId(tai.PLMNId)
+	if err != nil {
+		return nil, err
+	}
+	if len(tai.Tac.Value) != length-plmnIdLength {
+		return nil, fmt.Errorf("invalid TAC length %d", len(tai.Tac.Value))
+	}
+	return append(plmnId, tai.Tac.Value...), nil
+}
+
+func checkedPLMNId(p PLMNId) (asn.OctetString, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	return append(asn.OctetString{}, p.Value...), nil
+}
+
+// parseCellId converts a cell or node identity of the given number of hex
+// digits into octets, leaving the leading spare bits as zero.
+func parseCellId(id string, digits int) (asn.OctetString, error) {
+	if len(id) != digits {
+		return nil, fmt.Errorf("%q is not %d hex digits", id, digits)
+	}
+	if _, err := strconv.ParseUint(id, 16, 64); err != nil {
+		return nil, fmt.Errorf("%q is not %d hex digits", id, digits)
+	}
+	return hex.DecodeString("0" + id)
+}
+
+// ResolveUserLocationInformation returns the structured user location from
+// whichever of the two user location fields the producer filled, preferring
+// the structured one.
+func ResolveUserLocationInformation(uli *UserLocationInformation,
+	uliASN1 *UserLocationInformationStructured) (*UserLocationInformationStructured, error) {
+	if uliASN1 != nil {
+		return uliASN1, nil
+	}
+	if uli == nil {
+		return nil, nil
+	}
+	structured, err := uli.Decode()
+	if err != nil {
+		return nil, err
+	}
+	return &structured, nil
+}
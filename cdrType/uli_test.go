@@ -0,0 +1,62 @@
+package cdrType
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserLocationInformation(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name string
+		in   string
+	}{
+		{"tai", "8002f8390001"},
+		{"ecgi", "8102f83901234567"},
+		{"taiAndEcgi", "8202f839000102f83901234567"},
+		{"eNodeBID", "8302f8390abcde"},
+		{"ncgi", "8702f839000000000f"},
+		{"5gsTai", "8802f839000001"},
+		{"5gsTaiAndNcgi", "8902f83900000102f8390123456789"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			in, err := hex.DecodeString(tc.in)
+			require.NoError(t, err)
+
+			structured, err := UserLocationInformation{Value: in}.Decode()
+			require.NoError(t, err)
+
+			uli, err := NewUserLocationInformation(structured)
+			require.NoError(t, err)
+			require.Equal(t, tc.in, hex.EncodeToString(uli.Value))
+		})
+	}
+
+	structured, err := UserLocationInformation{Value: []byte{
+		ULIType5GSTAIAndNCGI, 0x02, 0xf8, 0x39, 0x00, 0x00, 0x01,
+		0x02, 0xf8, 0x39, 0xf1, 0x23, 0x45, 0x67, 0x89,
+	}}.Decode()
+	require.NoError(t, err)
+	require.Nil(t, structured.EutraLocation)
+	require.Equal(t, "208", structured.NrLocation.Tai.PLMNId.MCC())
+	require.Equal(t, "123456789", string(structured.NrLocation.Ncgi.NrCellId.Value))
+
+	_, err = UserLocationInformation{Value: []byte{ULITypeTAI, 0x02}}.Decode()
+	require.Error(t, err)
+	_, err = UserLocationInformation{Value: []byte{0}}.Decode()
+	require.Error(t, err)
+	_, err = NewUserLocationInformation(UserLocationInformationStructured{N3gaLocation: &N3gaLocation{}})
+	require.Error(t, err)
+
+	resolved, err := ResolveUserLocationInformation(&UserLocationInformation{Value: []byte{
+		ULITypeTAI, 0x02, 0xf8, 0x39, 0x00, 0x01,
+	}}, nil)
+	require.NoError(t, err)
+	require.Equal(t, "0001", hex.EncodeToString(resolved.EutraLocation.Tai.Tac.Value))
+}
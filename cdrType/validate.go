@@ -0,0 +1,177 @@
+package cdrType
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// FieldError is a problem found by Validate at the path of a field, such as
+// ChargingRecord.ListOfMultipleUnitUsage[0].UsedUnitContainers[1].
+type FieldError struct {
+	Path string
+	Err  string
+}
+
+func (e FieldError) Error() string {
+	return e.Path + ": " + e.Err
+}
+
+// ValidationErrors are all the problems Validate found in a value.
+type ValidationErrors []FieldError
+
+func (e ValidationErrors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, fieldErr := range e {
+		msgs = append(msgs, fieldErr.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+type validator interface {
+	Validate() error
+}
+
+type enumValidator interface {
+	IsValid() bool
+	String() string
+}
+
+// Validate walks a value of the cdrType tree and reports, as
+// ValidationErrors:
+//   - mandatory strings and CHOICEs left empty
+//   - CHOICEs whose Present does not match the alternative set
+//   - enumerated values outside the named values
+//   - values breaking the encoding checked by their own Validate, such as
+//     PLMNId
+//   - used unit containers whose total volume is not uplink plus downlink
+//
+// Optional members are only checked when present.
+func Validate(v interface{}) error {
+	value := reflect.ValueOf(v)
+	if !value.IsValid() {
+		return nil
+	}
+	var errs ValidationErrors
+	name := reflect.Indirect(value).Type().Name()
+	validateValue(value, name, true, &errs)
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// Validate checks the record before it is encoded, see Validate.
+func (r ChargingRecord) Validate() error {
+	return Validate(r)
+}
+
+func validateValue(v reflect.Value, path string, mandatory bool, errs *ValidationErrors) {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return
+		}
+		// a present optional member must be complete
+		v, mandatory = v.Elem(), true
+	}
+
+	add := func(format string, args ...interface{}) {
+		*errs = append(*errs, FieldError{Path: path, Err: fmt.Sprintf(format, args...)})
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		if mandatory && v.Len() == 0 {
+			add("empty")
+		}
+		return
+	case reflect.Slice:
+		if v.Type().Elem().Kind() != reflect.Struct {
+			if mandatory && v.Len() == 0 {
+				add("empty")
+			}
+			return
+		}
+		for i := 0; i < v.Len(); i++ {
+			validateValue(v.Index(i), fmt.Sprintf("%s[%d]", path, i), true, errs)
+		}
+		return
+	case reflect.Struct:
+	default:
+		return
+	}
+
+	t := v.Type()
+	if t.NumField() == 0 || t.PkgPath() != reflect.TypeOf(ChargingRecord{}).PkgPath() {
+		// asn.BitString and the like
+		if mandatory && v.IsZero() {
+			add("missing")
+		}
+		return
+	}
+
+	switch t.Field(0).Name {
+	case "Present":
+		present := int(v.Field(0).Int())
+		if t.NumField() == 1 {
+			return // open type
+		}
+		if present == 0 {
+			if mandatory {
+				add("missing")
+			}
+			return
+		}
+		if choice, ok := v.Interface().(validator); ok {
+			if err := choice.Validate(); err != nil {
+				add("%v", strings.TrimPrefix(err.Error(), t.Name()+": "))
+				return
+			}
+		}
+		validateValue(v.Field(present), path+"."+t.Field(present).Name, true, errs)
+		return
+	case "Value":
+		if enum, ok := v.Interface().(enumValidator); ok {
+			if !enum.IsValid() {
+				add("out of range value %v", enum)
+			}
+			return
+		}
+		if t.NumField() == 1 {
+			n := len(*errs)
+			validateValue(v.Field(0), path, mandatory, errs)
+			// value types with their own encoding rules, such as PLMNId
+			if check, ok := v.Interface().(validator); ok && len(*errs) == n && !v.IsZero() {
+				if err := check.Validate(); err != nil {
+					add("%v", strings.TrimPrefix(err.Error(), t.Name()+": "))
+				}
+			}
+			return
+		}
+	case "List":
+		validateValue(v.Field(0), path, mandatory, errs)
+		return
+	}
+
+	// SEQUENCE or SET, whose zero value may be valid
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		optional := strings.Contains(f.Tag.Get("ber"), "optional")
+		validateValue(v.Field(i), path+"."+f.Name, !optional, errs)
+	}
+	if container, ok := v.Interface().(UsedUnitContainer); ok {
+		validateVolumes(container.DataTotalVolume, container.DataVolumeUplink, container.DataVolumeDownlink, add)
+	}
+	if container, ok := v.Interface().(MultipleQFIContainer); ok {
+		validateVolumes(container.DataTotalVolume, container.DataVolumeUplink, container.DataVolumeDownlink, add)
+	}
+}
+
+func validateVolumes(total, uplink, downlink *DataVolumeOctets, add func(string, ...interface{})) {
+	if total == nil || uplink == nil || downlink == nil {
+		return
+	}
+	if total.Value != uplink.Value+downlink.Value {
+		add("total volume %d is not uplink %d + downlink %d", total.Value, uplink.Value, downlink.Value)
+	}
+}
@@ -0,0 +1,78 @@
+package cdrType
+
+import (
+	"testing"
+
+	"github.com/free5gc/CDRUtil/asn"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	plmnId, err := NewPLMNId("208", "93")
+	require.NoError(t, err)
+	validRecord := func() ChargingRecord {
+		return ChargingRecord{
+			RecordType:                 RecordType{Value: RecordTypePresentChargingFunctionRecord},
+			RecordingNetworkFunctionID: NetworkFunctionName{Value: "CHF"},
+			NFunctionConsumerInformation: NetworkFunctionInformation{
+				NetworkFunctionality:          NetworkFunctionality{Value: NetworkFunctionalityPresentSMF},
+				NetworkFunctionPLMNIdentifier: &plmnId,
+			},
+			ListOfMultipleUnitUsage: []MultipleUnitUsage{{
+				RatingGroup: RatingGroupId{Value: 1},
+				UsedUnitContainers: []UsedUnitContainer{{
+					DataTotalVolume:    &DataVolumeOctets{Value: 30},
+					DataVolumeUplink:   &DataVolumeOctets{Value: 10},
+					DataVolumeDownlink: &DataVolumeOctets{Value: 20},
+				}},
+			}},
+			RecordOpeningTime:  TimeStamp{Value: asn.OctetString{0x21, 0x06, 0x01, 0x00, 0x00, 0x00, 0x2b, 0x00, 0x00}},
+			CauseForRecClosing: CauseForRecClosing{Value: CauseForRecClosingPresentNormalRelease},
+		}
+	}
+
+	record := validRecord()
+	require.NoError(t, record.Validate())
+	require.NoError(t, Validate(&record))
+
+	testCases := []struct {
+		name   string
+		modify func(r *ChargingRecord)
+		errs   ValidationErrors
+	}{
+		{"emptyString", func(r *ChargingRecord) { r.RecordingNetworkFunctionID.Value = "" },
+			ValidationErrors{{"ChargingRecord.RecordingNetworkFunctionID", "empty"}}},
+		{"emptyTimeStamp", func(r *ChargingRecord) { r.RecordOpeningTime.Value = nil },
+			ValidationErrors{{"ChargingRecord.RecordOpeningTime", "empty"}}},
+		{"enum", func(r *ChargingRecord) { r.CauseForRecClosing.Value = 2 },
+			ValidationErrors{{"ChargingRecord.CauseForRecClosing", "out of range value CauseForRecClosing(2)"}}},
+		{"plmnId", func(r *ChargingRecord) {
+			r.NFunctionConsumerInformation.NetworkFunctionPLMNIdentifier = &PLMNId{Value: asn.OctetString{0x02}}
+		}, ValidationErrors{{"ChargingRecord.NFunctionConsumerInformation.NetworkFunctionPLMNIdentifier",
+			"invalid length 1"}}},
+		{"volumes", func(r *ChargingRecord) {
+			r.ListOfMultipleUnitUsage[0].UsedUnitContainers[0].DataTotalVolume.Value = 31
+		}, ValidationErrors{{"ChargingRecord.ListOfMultipleUnitUsage[0].UsedUnitContainers[0]",
+			"total volume 31 is not uplink 10 + downlink 20"}}},
+		{"choice", func(r *ChargingRecord) {
+			r.SubscriberIdentifier = &SubscriptionID{
+				SubscriptionIDType: SubscriptionIDType{Value: SubscriptionIDTypePresentENDUSERIMSI},
+			}
+			r.Diagnostics = &Diagnostics{Present: DiagnosticsPresentGsm0408Cause}
+		}, ValidationErrors{
+			{"ChargingRecord.SubscriberIdentifier.SubscriptionIDData", "empty"},
+			{"ChargingRecord.Diagnostics", "Gsm0408Cause selected but not set"},
+		}},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			record := validRecord()
+			tc.modify(&record)
+			require.Equal(t, tc.errs, record.Validate())
+		})
+	}
+}
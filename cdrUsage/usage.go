@@ -0,0 +1,181 @@
+// Package cdrUsage sums up the unit usage of charging records.
+package cdrUsage
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/free5gc/CDRUtil/cdrType"
+)
+
+// Key identifies the usage summed up in a Summary.
+type Key struct {
+	// SubscriptionIDData of the subscriber identifier, empty if none
+	Subscriber  string
+	RatingGroup int64
+	// DataNetworkNameIdentifier of the PDU session, empty if none
+	Dnn string
+	// in UTC
+	BucketStart time.Time
+}
+
+// Totals are the units of the used unit containers summed up.
+type Totals struct {
+	// octets
+	Uplink   int64
+	Downlink int64
+	Total    int64
+	// seconds
+	Time                 int64
+	ServiceSpecificUnits int64
+	Containers           int
+}
+
+// Summary is the usage of a subscriber for a rating group and DNN in a time
+// bucket.
+type Summary struct {
+	Key
+	Totals
+}
+
+// Aggregator sums up the used unit containers of charging records per
+// subscriber, rating group, DNN and time bucket. A container falls in the
+// bucket of its TriggerTimeStamp or, failing that, of the opening of its
+// record. Its total volume is the sum of its uplink and downlink volumes when
+// it reports none.
+//
+// It is safe for concurrent use.
+type Aggregator struct {
+	bucket time.Duration
+
+	mu     sync.Mutex
+	totals map[Key]*Totals
+}
+
+// NewAggregator returns an aggregator of buckets of the given length, aligned
+// on the Unix epoch.
+func NewAggregator(bucket time.Duration) (*Aggregator, error) {
+	if bucket < time.Second {
+		return nil, fmt.Errorf("bucket %v is shorter than a second", bucket)
+	}
+	return &Aggregator{
+		bucket: bucket,
+		totals: make(map[Key]*Totals),
+	}, nil
+}
+
+// Add sums up the unit usage of record. Nothing is added if its time stamps
+// cannot be decoded.
+func (a *Aggregator) Add(record cdrType.ChargingRecord) error {
+	var key Key
+	if record.SubscriberIdentifier != nil {
+		key.Subscriber = string(record.SubscriberIdentifier.SubscriptionIDData)
+	}
+	if info := record.PDUSessionChargingInformation; info != nil && info.DataNetworkNameIdentifier != nil {
+		key.Dnn = string(info.DataNetworkNameIdentifier.Value)
+	}
+	openingTime, err := record.RecordOpeningTime.Decode()
+	if err != nil {
+		return fmt.Errorf("recordOpeningTime: %v", err)
+	}
+
+	// the keys of the containers, checked before any is added
+	type keyedContainer struct {
+		key       Key
+		container *cdrType.UsedUnitContainer
+	}
+	var containers []keyedContainer
+	for i := range record.ListOfMultipleUnitUsage {
+		usage := &record.ListOfMultipleUnitUsage[i]
+		key.RatingGroup = usage.RatingGroup.Value
+		for j := range usage.UsedUnitContainers {
+			container := &usage.UsedUnitContainers[j]
+			t := openingTime
+			if container.TriggerTimeStamp != nil {
+				t, err = container.TriggerTimeStamp.Decode()
+				if err != nil {
+					return fmt.Errorf("triggerTimeStamp: %v", err)
+				}
+			}
+			key.BucketStart = a.bucketStart(t)
+			containers = append(containers, keyedContainer{key, container})
+		}
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for _, c := range containers {
+		totals, ok := a.totals[c.key]
+		if !ok {
+			totals = &Totals{}
+			a.totals[c.key] = totals
+		}
+		totals.add(c.container)
+	}
+	return nil
+}
+
+func (a *Aggregator) bucketStart(t time.Time) time.Time {
+	sinceEpoch := time.Duration(t.UnixNano())
+	return time.Unix(0, int64(sinceEpoch-sinceEpoch%a.bucket)).UTC()
+}
+
+// Summaries returns the usage summed up so far, in the order of subscriber,
+// DNN, rating group and bucket.
+func (a *Aggregator) Summaries() []Summary {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	summaries := make([]Summary, 0, len(a.totals))
+	for key, totals := range a.totals {
+		summaries = append(summaries, Summary{Key: key, Totals: *totals})
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		a, b := summaries[i].Key, summaries[j].Key
+		switch {
+		case a.Subscriber != b.Subscriber:
+			return a.Subscriber < b.Subscriber
+		case a.Dnn != b.Dnn:
+			return a.Dnn < b.Dnn
+		case a.RatingGroup != b.RatingGroup:
+			return a.RatingGroup < b.RatingGroup
+		}
+		return a.BucketStart.Before(b.BucketStart)
+	})
+	return summaries
+}
+
+// Reset forgets the usage summed up so far.
+func (a *Aggregator) Reset() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.totals = make(map[Key]*Totals)
+}
+
+func (t *Totals) add(container *cdrType.UsedUnitContainer) {
+	var uplink, downlink int64
+	if container.DataVolumeUplink != nil {
+		uplink = container.DataVolumeUplink.Value
+	}
+	if container.DataVolumeDownlink != nil {
+		downlink = container.DataVolumeDownlink.Value
+	}
+	t.Uplink += uplink
+	t.Downlink += downlink
+	if container.DataTotalVolume != nil {
+		t.Total += container.DataTotalVolume.Value
+	} else {
+		t.Total += uplink + downlink
+	}
+	if container.Time != nil {
+		t.Time += container.Time.Value
+	}
+	if container.ServiceSpecificUnits != nil {
+		t.ServiceSpecificUnits += *container.ServiceSpecificUnits
+	}
+	t.Containers++
+}
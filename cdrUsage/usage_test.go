@@ -0,0 +1,90 @@
+package cdrUsage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/free5gc/CDRUtil/cdrType"
+	"github.com/stretchr/testify/require"
+)
+
+func usedUnitContainer(triggerTime *time.Time, uplink, downlink int64, total *int64) cdrType.UsedUnitContainer {
+	container := cdrType.UsedUnitContainer{
+		DataVolumeUplink:   &cdrType.DataVolumeOctets{Value: uplink},
+		DataVolumeDownlink: &cdrType.DataVolumeOctets{Value: downlink},
+		Time:               &cdrType.CallDuration{Value: 60},
+	}
+	if total != nil {
+		container.DataTotalVolume = &cdrType.DataVolumeOctets{Value: *total}
+	}
+	if triggerTime != nil {
+		triggerTimeStamp := cdrType.NewTimeStamp(*triggerTime)
+		container.TriggerTimeStamp = &triggerTimeStamp
+	}
+	return container
+}
+
+func TestAggregator(t *testing.T) {
+	t.Parallel()
+
+	opening := time.Date(2022, time.May, 4, 10, 50, 0, 0, time.FixedZone("", 8*3600))
+	later := opening.Add(20 * time.Minute)
+	total := int64(1000)
+	record := cdrType.ChargingRecord{
+		SubscriberIdentifier: &cdrType.SubscriptionID{
+			SubscriptionIDType: cdrType.SubscriptionIDType{Value: cdrType.SubscriptionIDTypePresentENDUSERIMSI},
+			SubscriptionIDData: "208930000000001",
+		},
+		RecordOpeningTime: cdrType.NewTimeStamp(opening),
+		ListOfMultipleUnitUsage: []cdrType.MultipleUnitUsage{{
+			RatingGroup: cdrType.RatingGroupId{Value: 1},
+			UsedUnitContainers: []cdrType.UsedUnitContainer{
+				usedUnitContainer(nil, 10, 20, nil),
+				usedUnitContainer(&opening, 100, 200, &total),
+				usedUnitContainer(&later, 1, 2, nil),
+			},
+		}, {
+			RatingGroup: cdrType.RatingGroupId{Value: 2},
+			UsedUnitContainers: []cdrType.UsedUnitContainer{
+				usedUnitContainer(nil, 5, 5, nil),
+			},
+		}},
+		PDUSessionChargingInformation: &cdrType.PDUSessionChargingInformation{
+			DataNetworkNameIdentifier: &cdrType.DataNetworkNameIdentifier{Value: "internet"},
+		},
+	}
+
+	aggregator, err := NewAggregator(time.Hour)
+	require.NoError(t, err)
+	require.NoError(t, aggregator.Add(record))
+	require.NoError(t, aggregator.Add(record))
+
+	key := Key{Subscriber: "208930000000001", RatingGroup: 1, Dnn: "internet"}
+	bucket1 := time.Date(2022, time.May, 4, 2, 0, 0, 0, time.UTC)
+	bucket2 := bucket1.Add(time.Hour)
+	expected := []Summary{}
+	for _, summary := range []struct {
+		ratingGroup int64
+		bucket      time.Time
+		totals      Totals
+	}{
+		{1, bucket1, Totals{Uplink: 220, Downlink: 440, Total: 2060, Time: 240, Containers: 4}},
+		{1, bucket2, Totals{Uplink: 2, Downlink: 4, Total: 6, Time: 120, Containers: 2}},
+		{2, bucket1, Totals{Uplink: 10, Downlink: 10, Total: 20, Time: 120, Containers: 2}},
+	} {
+		key.RatingGroup = summary.ratingGroup
+		key.BucketStart = summary.bucket
+		expected = append(expected, Summary{Key: key, Totals: summary.totals})
+	}
+	require.Equal(t, expected, aggregator.Summaries())
+
+	record.ListOfMultipleUnitUsage[1].UsedUnitContainers[0].TriggerTimeStamp = &cdrType.TimeStamp{}
+	require.Error(t, aggregator.Add(record))
+	require.Equal(t, expected, aggregator.Summaries())
+
+	aggregator.Reset()
+	require.Empty(t, aggregator.Summaries())
+
+	_, err = NewAggregator(time.Millisecond)
+	require.Error(t, err)
+}
@@ -1,12 +1,18 @@
 module github.com/free5gc/CDRUtil
 
-go 1.14
+go 1.18
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
-	github.com/free5gc/openapi v1.0.4
+	github.com/free5gc/openapi v1.0.7
+	github.com/golang-jwt/jwt v3.2.1+incompatible // indirect
+	github.com/jlaffaye/ftp v0.0.0-20220201222555-02685330ee35
+	github.com/kr/fs v0.1.0 // indirect
+	github.com/pkg/sftp v1.13.5
+	github.com/pmezard/go-difflib v1.0.0 // indirect
 	github.com/stretchr/testify v1.7.1
+	golang.org/x/crypto v0.0.0-20220525230936-793ad666bf5e
+	golang.org/x/sys v0.0.0-20211216021012-1d35b9e2eb4e // indirect
+	gopkg.in/yaml.v3 v3.0.1
 )
 
-replace github.com/free5gc/openapi => /home/uduck/openapi
-
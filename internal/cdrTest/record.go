@@ -0,0 +1,28 @@
+// Package cdrTest holds the fixtures shared by the tests of the CDR
+// packages.
+package cdrTest
+
+import (
+	"time"
+
+	"github.com/free5gc/CDRUtil/asn"
+	"github.com/free5gc/CDRUtil/cdrType"
+)
+
+// OpeningTime is the RecordOpeningTime of the records of ChargingRecord.
+var OpeningTime = time.Date(2022, time.May, 4, 10, 0, 0, 0, time.UTC)
+
+// ChargingRecord returns a minimal record of the CHF named chf for an SMF,
+// the partial record sequenceNumber of the charging session sessionId.
+func ChargingRecord(sessionId string, sequenceNumber int64) cdrType.ChargingRecord {
+	return cdrType.ChargingRecord{
+		RecordType:                 cdrType.RecordType{Value: cdrType.RecordTypePresentChargingFunctionRecord},
+		RecordingNetworkFunctionID: cdrType.NetworkFunctionName{Value: "chf"},
+		NFunctionConsumerInformation: cdrType.NetworkFunctionInformation{
+			NetworkFunctionality: cdrType.NetworkFunctionality{Value: cdrType.NetworkFunctionalityPresentSMF},
+		},
+		RecordOpeningTime:         cdrType.NewTimeStamp(OpeningTime),
+		RecordSequenceNumber:      &sequenceNumber,
+		ChargingSessionIdentifier: &cdrType.ChargingSessionIdentifier{Value: asn.OctetString(sessionId)},
+	}
+}
@@ -0,0 +1,75 @@
+// Package safeFile replaces files so that a crash leaves either the old or
+// the new content, and moves files without overwriting others.
+package safeFile
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// TmpExt is the extension of the temporary file written by Replace next to
+// the file it replaces.
+const TmpExt = ".tmp"
+
+// Replace writes data to the file at path through a synced temporary file
+// renamed over it, the directory being synced as well so that the rename
+// survives a crash.
+func Replace(path string, data []byte) error {
+	return ReplaceThrough(path, path+TmpExt, data)
+}
+
+// ReplaceThrough is Replace through the temporary file at tmpPath, in the
+// directory of path.
+func ReplaceThrough(path, tmpPath string, data []byte) error {
+	file, err := os.OpenFile(tmpPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o666)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return err
+	}
+	return SyncDir(filepath.Dir(path))
+}
+
+// SyncDir commits the entries created, renamed or removed in the directory
+// at path.
+func SyncDir(path string) error {
+	dir, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	if err := dir.Sync(); err != nil {
+		dir.Close()
+		return err
+	}
+	return dir.Close()
+}
+
+// Move moves the file at from to the path to, in the same file system. Unlike
+// os.Rename, it fails with an error satisfying os.IsExist if another file is
+// at to already. A move cut by a crash, the file being at both paths, is
+// completed.
+func Move(from, to string) error {
+	if err := os.Link(from, to); err != nil {
+		if !os.IsExist(err) {
+			return err
+		}
+		fromInfo, fromErr := os.Stat(from)
+		toInfo, toErr := os.Stat(to)
+		if fromErr != nil || toErr != nil || !os.SameFile(fromInfo, toInfo) {
+			return err
+		}
+	}
+	return os.Remove(from)
+}
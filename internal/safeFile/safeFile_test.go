@@ -0,0 +1,60 @@
+package safeFile
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplace(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "file")
+
+	for _, data := range []string{"old", "new"} {
+		require.NoError(t, Replace(path, []byte(data)))
+		b, err := os.ReadFile(path)
+		require.NoError(t, err)
+		require.Equal(t, data, string(b))
+	}
+	_, err := os.Stat(path + TmpExt)
+	require.True(t, os.IsNotExist(err))
+
+	require.Error(t, Replace(filepath.Join(dir, "none", "file"), nil))
+	require.Error(t, SyncDir(filepath.Join(dir, "none")))
+}
+
+func TestMove(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	from, to := filepath.Join(dir, "from"), filepath.Join(dir, "to")
+	require.NoError(t, os.WriteFile(from, []byte("new"), 0o666))
+	require.NoError(t, os.WriteFile(to, []byte("old"), 0o666))
+
+	// another file in the way
+	err := Move(from, to)
+	require.True(t, os.IsExist(err))
+	b, err := os.ReadFile(to)
+	require.NoError(t, err)
+	require.Equal(t, "old", string(b))
+	require.FileExists(t, from)
+
+	require.NoError(t, os.Remove(to))
+	require.NoError(t, Move(from, to))
+	b, err = os.ReadFile(to)
+	require.NoError(t, err)
+	require.Equal(t, "new", string(b))
+	require.NoFileExists(t, from)
+
+	// a move cut after the link
+	require.NoError(t, os.Link(to, from))
+	require.NoError(t, Move(from, to))
+	require.NoFileExists(t, from)
+	require.FileExists(t, to)
+
+	require.Error(t, Move(from, to))
+}